@@ -21,6 +21,7 @@ import (
 	"github.com/spf13/pflag"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/discovery"
 	"os"
 	"runtime"
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -125,6 +126,19 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Expose boots by Route on OpenShift, otherwise by Ingress
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+	logan.RouteEnabled, err = k8sutil.ResourceExists(dc, "route.openshift.io/v1", "Route")
+	if err != nil {
+		log.Error(err, "Failed to discover route.openshift.io")
+		os.Exit(1)
+	}
+	log.Info(fmt.Sprintf("Logan Operator RouteEnabled: %t", logan.RouteEnabled))
+
 	log.Info("Registering Components.")
 
 	// Setup Scheme for all resources
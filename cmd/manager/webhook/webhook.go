@@ -1,7 +1,10 @@
 package webhook
 
 import (
+	"fmt"
 	"github.com/go-logr/logr"
+	"github.com/logancloud/logan-app-operator/pkg/logan"
+	"github.com/logancloud/logan-app-operator/pkg/logan/registry"
 	bootmutation "github.com/logancloud/logan-app-operator/pkg/logan/webhook/mutation"
 	bootvalidation "github.com/logancloud/logan-app-operator/pkg/logan/webhook/validation"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -23,6 +26,9 @@ func RegisterWebhook(mgr manager.Manager, log logr.Logger, operatorNs string) {
 		Handler: &bootmutation.BootMutator{
 			Schema:   mgr.GetScheme(),
 			Recorder: mgr.GetEventRecorderFor("logan-webhook-mutation"),
+			Resolver: registry.NewClient(),
+			// The username of the ServiceAccount, see k8s.io/apiserver/pkg/authentication/serviceaccount
+			OperatorUsername: fmt.Sprintf("system:serviceaccount:%s:%s", operatorNs, logan.OperatorServiceAccount),
 		},
 	})
 
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"github.com/logancloud/logan-app-operator/pkg/apis"
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	"github.com/logancloud/logan-app-operator/pkg/logan"
+	"github.com/logancloud/logan-app-operator/pkg/logan/operator"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"os"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"text/tabwriter"
+	"time"
+)
+
+const usage = `Usage: logan-revision <command> [flags]
+
+Commands:
+  compare  compare two revisions of a boot
+  list     list the revisions of a boot or a namespace, filtered by the author
+`
+
+var bootTypes = map[string]bool{
+	logan.BootJava:   true,
+	logan.BootPhp:    true,
+	logan.BootPython: true,
+	logan.BootNodeJS: true,
+	logan.BootWeb:    true,
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "compare":
+		err = compare(os.Args[2:])
+	case "list":
+		err = list(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// compare print the changes of the boot's revision `to` from the revision `from`
+func compare(args []string) error {
+	flags := pflag.NewFlagSet("compare", pflag.ExitOnError)
+	namespace := flags.StringP("namespace", "n", "", "the boot's namespace")
+	bootType := flags.StringP("type", "t", logan.BootJava, "the boot's type: java, php, python, nodejs or web")
+	from := flags.Int("from", 0, "the revision ID compared from")
+	to := flags.Int("to", 0, "the revision ID compared to")
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 || *namespace == "" || *from <= 0 || *to <= 0 {
+		return fmt.Errorf("usage: logan-revision compare <boot> -n <namespace> [-t <type>] --from <id> --to <id>")
+	}
+	if !bootTypes[*bootType] {
+		return fmt.Errorf("unknown boot type %s", *bootType)
+	}
+
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	boot := &appv1.Boot{
+		ObjectMeta: metav1.ObjectMeta{Name: flags.Arg(0), Namespace: *namespace},
+		BootType:   *bootType,
+	}
+	changes, err := operator.CompareRevisions(c, boot, *from, *to)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "PATH\tOLD\tNEW")
+	for _, change := range changes {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", change.Path, change.Old, change.New)
+	}
+	return w.Flush()
+}
+
+// list print the revisions of the boot, or all the boots in the namespace if no boot, filtered by the author
+func list(args []string) error {
+	flags := pflag.NewFlagSet("list", pflag.ExitOnError)
+	namespace := flags.StringP("namespace", "n", "", "the namespace of the revisions")
+	bootType := flags.StringP("type", "t", logan.BootJava, "the boot's type: java, php, python, nodejs or web")
+	author := flags.String("author", "", "list only the revisions changed by the user")
+	_ = flags.Parse(args)
+
+	if flags.NArg() > 1 || *namespace == "" {
+		return fmt.Errorf("usage: logan-revision list [<boot>] -n <namespace> [-t <type>] [--author <user>]")
+	}
+	if !bootTypes[*bootType] {
+		return fmt.Errorf("unknown boot type %s", *bootType)
+	}
+
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	var boot *appv1.Boot
+	if flags.NArg() == 1 {
+		boot = &appv1.Boot{
+			ObjectMeta: metav1.ObjectMeta{Name: flags.Arg(0), Namespace: *namespace},
+			BootType:   *bootType,
+		}
+	}
+	revisions, err := operator.ListRevisions(c, *namespace, boot, *author)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "BOOT\tTYPE\tREVISION\tPHASE\tAUTHOR\tOPERATION\tCHANGE-CAUSE\tCREATED")
+	for _, revision := range revisions {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+			revision.Labels[keys.BootNameKey],
+			revision.Labels[keys.BootTypeKey],
+			revision.GetRevisionId(),
+			revision.Annotations[keys.BootRevisionPhaseAnnotationKey],
+			revision.Annotations[keys.BootChangedByAnnotationKey],
+			revision.Annotations[keys.BootChangeOperationAnnotationKey],
+			revision.Annotations[keys.BootChangeCauseAnnotationKey],
+			revision.CreationTimestamp.UTC().Format(time.RFC3339))
+	}
+	return w.Flush()
+}
+
+// newClient return the client of the cluster in the kubeconfig
+func newClient() (*util.K8SClient, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := runtime.NewScheme()
+	err = apis.AddToScheme(scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := crclient.New(cfg, crclient.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+
+	k8sClient := util.NewClient(c)
+	return &k8sClient, nil
+}
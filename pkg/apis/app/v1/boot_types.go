@@ -3,7 +3,10 @@ package v1
 import (
 	autoscaling "k8s.io/api/autoscaling/v2beta1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -36,6 +39,12 @@ type BootSpec struct {
 	Image string `json:"image"`
 	// Version is the app container's image version.
 	Version string `json:"version"`
+	// ImageDigest is the immutable digest of the image's version, such as `sha256:...`, which pins the app container's
+	// image to `image@digest`. In the digest mode of the config's settings, it is resolved by the operator
+	// once the version is changed. It is cleared when the version is changed without it.
+	// +kubebuilder:validation:Pattern=^sha256:[a-f0-9]{64}$
+	// +optional
+	ImageDigest string `json:"imageDigest,omitempty"`
 	// Replicas is the number of desired replicas.
 	// This is a pointer to distinguish between explicit zero and unspecified.
 	// Defaults to 1.
@@ -47,12 +56,30 @@ type BootSpec struct {
 	// +patchMergeKey=name
 	// +patchStrategy=merge
 	Env []corev1.EnvVar `json:"env,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+	// EnvFrom is list of ConfigMaps and Secrets to populate environment variables in the app container.
+	// The keys can not override the environment variables enforced by the settings,
+	// and the permission of the secrets must be granted by the secret's `app.logancloud.com/secret-<boot>` annotation.
+	// +optional
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
 	// Port that are exposed by the app container
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=65535
 	Port int32 `json:"port,omitempty"`
-	// Reserved, not used. for latter use
+	// Ports is the list of additional ports of the app container, the primary Port is always named `http`.
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	Ports []BootPort `json:"ports,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+	// MetricsPort is the name of the port for prometheus to scrape, default is `http`, the primary Port.
+	// +optional
+	MetricsPort string `json:"metricsPort,omitempty"`
+	// SubDomain will expose the boot externally by an Ingress, or a Route on OpenShift. The host is built from
+	// the subDomain and the domainTemplate of the operator settings. If empty, the boot will not be exposed.
 	SubDomain string `json:"subDomain,omitempty"`
+	// TLSSecret is the name of the secret holding the TLS certificate(`tls.crt` and `tls.key`) of the subDomain's
+	// host. If empty, the host is served over http.
+	// +optional
+	TLSSecret string `json:"tlsSecret,omitempty"`
 	// Health is check path for the app container.
 	// +kubebuilder:validation:MinLength=0
 	// +kubebuilder:validation:MaxLength=2048
@@ -61,6 +88,10 @@ type BootSpec struct {
 	// +kubebuilder:validation:MinLength=0
 	// +kubebuilder:validation:MaxLength=2048
 	Readiness *string `json:"readiness,omitempty"`
+	// Probes is the structured liveness and readiness probes for the app container, and the startup delay of
+	// the liveness probe. A probe defined here takes precedence over the Health and Readiness shorthand.
+	// +optional
+	Probes *BootProbes `json:"probes,omitempty"`
 	// Prometheus will scrape metrics from the service, default is `true`
 	// +kubebuilder:validation:Enum=true;false
 	Prometheus string `json:"prometheus,omitempty"`
@@ -69,8 +100,37 @@ type BootSpec struct {
 	// NodeSelector is a selector which must be true for the pod to fit on a node.
 	// Selector which must match a node's labels for the pod to be scheduled on that node.
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations will set the tolerations of the boot's pods, merged with the tolerations of the boot type's config.
+	// The taints locked by the config can not be tolerated.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// NodeAffinity is the required and preferred node affinity of the boot's pods,
+	// merged with the node affinity of the boot type's config. The node labels locked by the config can not be used.
+	// +optional
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+	// ZoneAntiAffinity will spread the boot's pods across zones, can be `Preferred` or `Required`. default is ``
+	// +kubebuilder:validation:Enum=Preferred;Required
+	ZoneAntiAffinity ZoneAntiAffinity `json:"zoneAntiAffinity,omitempty"`
+	// TopologySpreadConstraints describes how the boot's pods spread across the topology domains.
+	// +optional
+	TopologySpreadConstraints []BootTopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
 	// Command is command for boot's container. If empty, will use image's ENTRYPOINT, specified here if needed override.
 	Command []string `json:"command,omitempty"`
+	// Args are the arguments of boot's container. If empty, will use image's CMD, specified here if needed override.
+	// +optional
+	Args []string `json:"args,omitempty"`
+	// WorkingDir is the working directory of boot's container. If empty, will use image's WORKDIR.
+	// +optional
+	WorkingDir string `json:"workingDir,omitempty"`
+	// ImagePullPolicy is the image pull policy of boot's container, can be `Always`, `IfNotPresent` or `Never`.
+	// Defaults to the imagePullPolicy of the config's settings, or `Always`.
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	// +optional
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+	// ImagePullSecrets are the secrets in the boot's namespace to pull the images.
+	// Defaults to the imagePullSecrets of the config's settings.
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
 	// SessionAffinity is SessionAffinity for boot's created service. If empty, will not set
 	// +kubebuilder:validation:Enum=ClientIP;None
 	SessionAffinity string `json:"sessionAffinity,omitempty"`
@@ -82,8 +142,29 @@ type BootSpec struct {
 	// +patchMergeKey=name
 	// +patchStrategy=merge
 	Pvc []PersistentVolumeClaimMount `json:"pvc,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+	// Volumes is list of ConfigMap, Secret, EmptyDir or Projected volumes to mount in the app container.
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	Volumes []BootVolume `json:"volumes,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+	// AutoRestart will restart the pods when the Secrets or ConfigMaps referenced by env, envFrom and volumes
+	// are changed, default is `true`.
+	// +optional
+	AutoRestart *bool `json:"autoRestart,omitempty"`
+	// ServiceAccount is the ServiceAccount of the boot's pods, which references an existing ServiceAccount or
+	// creates one owned by the boot. If not specified, the pods run with the namespace's default ServiceAccount.
+	// +optional
+	ServiceAccount *BootServiceAccount `json:"serviceAccount,omitempty"`
+	// SecurityContext is the security options of the boot's pods and app container, which must comply with the
+	// security policy of the boot type's config in the environment.
+	// +optional
+	SecurityContext *BootSecurityContext `json:"securityContext,omitempty"`
 	// Priority will set the priorityClassName for the boot's workloads, default is ``
 	Priority string `json:"priority,omitempty"`
+	// Placement is the name of the placement class defined in the boot type's config, which provides the
+	// nodeSelector, tolerations, node affinity and priorityClass of the boot's pods. The namespace must be granted
+	// by the Namespace's `app.logancloud.com/placement-<class>` annotation. default is ``
+	Placement string `json:"placement,omitempty"`
 	// Workload will set the wordload type for the boot,can be `Deployment` or `StatefulSet`. default is `Deployment`
 	// +kubebuilder:validation:Enum=Deployment;StatefulSet
 	Workload Workload `json:"workload,omitempty"`
@@ -92,6 +173,392 @@ type BootSpec struct {
 	// implementing the scale subresource based on the metrics specified.
 	// +optional
 	Hpa *Hpa `json:"hpa,omitempty"`
+	// Strategy is the update strategy used to replace old pods by new ones.
+	// Defaults to the strategy of the boot type's config.
+	// +optional
+	Strategy *BootStrategy `json:"strategy,omitempty"`
+	// Rollout is the progressive rollout of the boot's new version. If not specified, the whole workload
+	// is rolled at once by the update strategy.
+	// +optional
+	Rollout *BootRollout `json:"rollout,omitempty"`
+	// Disruption is the PodDisruptionBudget of the boot's pods, which limits the pods taken down at the same
+	// time by voluntary disruptions, such as node drains. Defaults to the disruption of the boot type's config.
+	// +optional
+	Disruption *BootDisruption `json:"disruption,omitempty"`
+	// Shutdown is the graceful shutdown of the boot's pods: the termination grace period, the preStop hook
+	// and the drain delay. Defaults to the shutdown of the boot type's config.
+	// +optional
+	Shutdown *BootShutdown `json:"shutdown,omitempty"`
+	// PodMetadata is the additional labels and annotations of the boot's pods, changing it restarts the pods.
+	// The reserved keys, such as the selector labels and the prometheus annotations, can not be used.
+	// +optional
+	PodMetadata *BootMetadata `json:"podMetadata,omitempty"`
+	// ServiceMetadata is the additional labels and annotations of the boot's Services.
+	// +optional
+	ServiceMetadata *BootMetadata `json:"serviceMetadata,omitempty"`
+	// WorkloadMetadata is the additional labels and annotations of the boot's Deployments or StatefulSet.
+	// +optional
+	WorkloadMetadata *BootMetadata `json:"workloadMetadata,omitempty"`
+	// Overrides are the strategic merge patches of the objects generated for the boot, for the fields which
+	// can not be expressed by the boot. The patched paths must be allowed by the config's overrides.
+	// +optional
+	Overrides *BootOverrides `json:"overrides,omitempty"`
+}
+
+// BootPort defines an additional port of the app container
+// +k8s:openapi-gen=true
+type BootPort struct {
+	// Name of the port, must be an IANA_SVC_NAME and unique within the boot. `http` is reserved for the primary Port.
+	Name string `json:"name"`
+	// ContainerPort is the number of the port exposed by the app container.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	ContainerPort int32 `json:"containerPort"`
+	// Protocol of the port, can be `TCP`, `UDP` or `SCTP`. default is `TCP`.
+	// +kubebuilder:validation:Enum=TCP;UDP;SCTP
+	// +optional
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
+	// AppProtocol is the application protocol of the port, such as `grpc`. The Service has no appProtocol field
+	// in this kubernetes version, so it is recorded in the `app.logancloud.com/app-protocols` annotation.
+	// +optional
+	AppProtocol string `json:"appProtocol,omitempty"`
+	// Expose will add the port to the app Service, default is `true`.
+	// +optional
+	Expose *bool `json:"expose,omitempty"`
+}
+
+// StrategyType defines the update strategy type of the boot
+type StrategyType string
+
+const (
+	// RollingUpdateStrategy replaces the old pods by new ones using rolling update
+	RollingUpdateStrategy StrategyType = "RollingUpdate"
+	// RecreateStrategy kills all existing pods before creating new ones, only for Deployment
+	RecreateStrategy StrategyType = "Recreate"
+)
+
+// BootStrategy defines the update strategy of the boot's workload
+// +k8s:openapi-gen=true
+type BootStrategy struct {
+	// Type of the strategy, can be `RollingUpdate` or `Recreate`. default is `RollingUpdate`.
+	// `Recreate` is only supported by Deployment.
+	// +kubebuilder:validation:Enum=RollingUpdate;Recreate
+	// +optional
+	Type StrategyType `json:"type,omitempty"`
+	// MaxSurge is the maximum number of pods that can be scheduled above the desired number of pods
+	// during the rolling update of Deployment. Value can be an absolute number (ex: 5) or a percentage(ex: 10%).
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+	// MaxUnavailable is the maximum number of pods that can be unavailable during the rolling update of Deployment.
+	// Value can be an absolute number (ex: 5) or a percentage(ex: 10%).
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	// ProgressDeadlineSeconds is the maximum time in seconds for a Deployment to make progress before it is
+	// considered to be failed.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+	// MinReadySeconds is the minimum number of seconds for which a newly created pod of Deployment should be
+	// ready without any of its container crashing, for it to be considered available.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
+	// Partition is the ordinal of StatefulSet, pods with an ordinal greater than or equal to partition
+	// will be updated. default is 0.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Partition *int32 `json:"partition,omitempty"`
+	// ActiveDeadlineSeconds is the maximum time in seconds for the boot's latest revision to become Active after
+	// it is created, before it is marked Failed. No deadline if not specified.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int32 `json:"activeDeadlineSeconds,omitempty"`
+	// AutoRollback restores the spec of the last Complete revision when the latest revision is marked Failed.
+	// Defaults to false.
+	// +optional
+	AutoRollback bool `json:"autoRollback,omitempty"`
+}
+
+// RolloutMode defines the progressive rollout mode of the boot
+type RolloutMode string
+
+const (
+	// CanaryRollout runs the new version next to the stable one, and shifts the replicas to it step by step
+	CanaryRollout RolloutMode = "Canary"
+	// BlueGreenRollout runs the new version as another color with all the replicas, and switches the
+	// Services to it when all the pods are ready. Only for Deployment.
+	BlueGreenRollout RolloutMode = "BlueGreen"
+)
+
+// BootRollout defines the progressive rollout of the boot
+// +k8s:openapi-gen=true
+type BootRollout struct {
+	// Mode of the rollout, can be `Canary` or `BlueGreen`.
+	// Enabling `BlueGreen` restarts the pods once, to label them with the color.
+	// With `Canary`, the stable pods are labeled with the track `stable` when they are rolled to the next version.
+	// +kubebuilder:validation:Enum=Canary;BlueGreen
+	Mode RolloutMode `json:"mode"`
+	// Steps of the canary. Every step sets the percentage of replicas running the new version,
+	// the last step promotes the new version to all the replicas.
+	// +optional
+	Steps []RolloutStep `json:"steps,omitempty"`
+	// ScaleDownDelaySeconds is the time to keep the previous color's replicas after the Services are switched
+	// to the new color, rollback during the delay is an instant switch back. Only for `BlueGreen`, default is 30.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ScaleDownDelaySeconds *int32 `json:"scaleDownDelaySeconds,omitempty"`
+}
+
+// RolloutStep defines a step of the canary rollout
+// +k8s:openapi-gen=true
+type RolloutStep struct {
+	// Weight is the percentage of replicas running the new version in this step.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Weight int32 `json:"weight"`
+	// PauseSeconds is the time to wait after the new replicas are ready, before promoting to the next step.
+	// If not specified, the step is paused until promoted by the `app.logancloud.com/canary-promote` annotation.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PauseSeconds *int32 `json:"pauseSeconds,omitempty"`
+}
+
+// ZoneAntiAffinity is the zone level anti-affinity of the boot's pods
+type ZoneAntiAffinity string
+
+const (
+	// ZoneAntiAffinityPreferred prefers to schedule the pods to the zones without the boot's pod
+	ZoneAntiAffinityPreferred ZoneAntiAffinity = "Preferred"
+	// ZoneAntiAffinityRequired schedules at most one pod of the boot in a zone
+	ZoneAntiAffinityRequired ZoneAntiAffinity = "Required"
+)
+
+// UnsatisfiableConstraintAction is the action for the pod which doesn't satisfy the spread constraint
+type UnsatisfiableConstraintAction string
+
+const (
+	// DoNotSchedule instructs the scheduler not to schedule the pod when constraints are not satisfied.
+	DoNotSchedule UnsatisfiableConstraintAction = "DoNotSchedule"
+	// ScheduleAnyway instructs the scheduler to schedule the pod even if constraints are not satisfied.
+	ScheduleAnyway UnsatisfiableConstraintAction = "ScheduleAnyway"
+)
+
+// BootTopologySpreadConstraint specifies how to spread the boot's pods among the given topology.
+// The pod's topologySpreadConstraints is not available in the supported kubernetes version, so it is
+// approximated by the preferred pod anti-affinity on the topologyKey, which prefers the domains without the
+// boot's pod. It is not a real spread constraint, `DoNotSchedule` is not enforced either, since the required
+// anti-affinity would leave the pods pending once the replicas exceed the domains.
+// +k8s:openapi-gen=true
+type BootTopologySpreadConstraint struct {
+	// TopologyKey is the key of node labels, the nodes with the same value are in the same topology domain.
+	TopologyKey string `json:"topologyKey"`
+	// WhenUnsatisfiable indicates how to deal with a pod if it doesn't satisfy the spread constraint,
+	// can be `DoNotSchedule` or `ScheduleAnyway`. default is `ScheduleAnyway`
+	// +kubebuilder:validation:Enum=DoNotSchedule;ScheduleAnyway
+	WhenUnsatisfiable UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty"`
+}
+
+// BootDisruption defines the PodDisruptionBudget of the boot, only one of minAvailable and maxUnavailable
+// can be set. If the value would block the eviction of all the pods, such as the replicas is 1, the operator
+// uses maxUnavailable 1 instead.
+// +k8s:openapi-gen=true
+type BootDisruption struct {
+	// MinAvailable is the number of pods that must be still available after the eviction.
+	// Value can be an absolute number (ex: 5) or a percentage(ex: 10%).
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// MaxUnavailable is the number of pods that can be unavailable after the eviction.
+	// Value can be an absolute number (ex: 5) or a percentage(ex: 10%).
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// BootServiceAccount defines the ServiceAccount of the boot's pods, and the Role bound to it
+// +k8s:openapi-gen=true
+type BootServiceAccount struct {
+	// Name is the name of the ServiceAccount. Required if create is false, defaults to the boot's name if create is true.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Create will create the ServiceAccount owned by the boot, otherwise the existing ServiceAccount is referenced,
+	// which must be granted to the boot by its annotation `app.logancloud.com/serviceaccount-<boot>`.
+	// +optional
+	Create bool `json:"create,omitempty"`
+	// AutomountToken indicates whether the ServiceAccount's token is mounted into the pods.
+	// default is `true` if rules are set, otherwise `false`
+	// +optional
+	AutomountToken *bool `json:"automountToken,omitempty"`
+	// Rules are the rules of the Role bound to the ServiceAccount, which must be allowed by the boot type's config.
+	// The Role and RoleBinding are named `<boot>-boot` and owned by the boot.
+	// +optional
+	Rules []rbacv1.PolicyRule `json:"rules,omitempty"`
+}
+
+// BootSecurityContext defines the security options of the boot's pods and app container
+// +k8s:openapi-gen=true
+type BootSecurityContext struct {
+	// RunAsUser is the UID to run the entrypoint of the pod's containers.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RunAsUser *int64 `json:"runAsUser,omitempty"`
+	// RunAsNonRoot indicates that the pod's containers must run as a non-root user.
+	// +optional
+	RunAsNonRoot *bool `json:"runAsNonRoot,omitempty"`
+	// ReadOnlyRootFilesystem indicates whether the app container has a read-only root filesystem.
+	// +optional
+	ReadOnlyRootFilesystem *bool `json:"readOnlyRootFilesystem,omitempty"`
+	// Capabilities are the capabilities to add or drop of the app container.
+	// +optional
+	Capabilities *corev1.Capabilities `json:"capabilities,omitempty"`
+	// FSGroup is the supplemental group applied to the pod's volumes.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FSGroup *int64 `json:"fsGroup,omitempty"`
+}
+
+// BootMetadata defines the additional labels and annotations of the objects created for the boot
+// +k8s:openapi-gen=true
+type BootMetadata struct {
+	// Labels are the additional labels
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are the additional annotations
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// BootOverrides defines the strategic merge patches of the objects generated for the boot
+// +k8s:openapi-gen=true
+type BootOverrides struct {
+	// Workload is the patch of the boot's Deployment or StatefulSet, such as `spec.template.spec.hostAliases`
+	// +optional
+	Workload *runtime.RawExtension `json:"workload,omitempty"`
+	// Service is the patch of the boot's app Service, such as `spec.externalTrafficPolicy`
+	// +optional
+	Service *runtime.RawExtension `json:"service,omitempty"`
+	// Hpa is the patch of the boot's HorizontalPodAutoscaler
+	// +optional
+	Hpa *runtime.RawExtension `json:"hpa,omitempty"`
+}
+
+// BootShutdown defines the graceful shutdown of the boot's pods. When the pod is deleted, the app container
+// waits for the drain delay, runs the preStop hook, and then receives SIGTERM.
+// +k8s:openapi-gen=true
+type BootShutdown struct {
+	// TerminationGracePeriodSeconds is the duration in seconds the pod needs to terminate gracefully,
+	// include the drain delay and the preStop hook. default is `30`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+	// PreStop is the hook executed in the app container before it receives SIGTERM.
+	// +optional
+	PreStop *BootPreStop `json:"preStop,omitempty"`
+	// DrainDelaySeconds is the seconds to sleep before the preStop hook and SIGTERM, so that the pod is removed
+	// from the endpoints of the services before the app stops accepting requests.
+	// The app image must provide `/bin/sh`, and it can not be used with the HTTP preStop hook.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	DrainDelaySeconds *int32 `json:"drainDelaySeconds,omitempty"`
+}
+
+// BootPreStop defines the preStop hook of the app container
+// +k8s:openapi-gen=true
+type BootPreStop struct {
+	// Type is the handler type of the hook, can be `HTTP` or `Exec`. default is `HTTP`
+	// +kubebuilder:validation:Enum=HTTP;Exec
+	// +optional
+	Type ProbeType `json:"type,omitempty"`
+	// Port is the port of the HTTP hook. Defaults to the app's port.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+	// Path is the HTTP path of the hook.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Scheme is the scheme of the HTTP hook, can be `HTTP` or `HTTPS`. default is `HTTP`
+	// +kubebuilder:validation:Enum=HTTP;HTTPS
+	// +optional
+	Scheme corev1.URIScheme `json:"scheme,omitempty"`
+	// Command is the command of the Exec hook.
+	// +optional
+	Command []string `json:"command,omitempty"`
+}
+
+// BootProbes defines the probes of the app container
+// +k8s:openapi-gen=true
+type BootProbes struct {
+	// Liveness is the liveness probe, overrides the Health shorthand.
+	// +optional
+	Liveness *BootProbe `json:"liveness,omitempty"`
+	// Readiness is the readiness probe, overrides the Readiness shorthand.
+	// +optional
+	Readiness *BootProbe `json:"readiness,omitempty"`
+	// Startup is not a startupProbe, which is not supported by the cluster. It only delays the liveness probe:
+	// its initialDelaySeconds + periodSeconds * failureThreshold is the minimum initialDelaySeconds of the
+	// liveness probe. Only these three fields can be set, and a liveness probe or the Health shorthand is required.
+	// +optional
+	Startup *BootProbe `json:"startup,omitempty"`
+}
+
+// ProbeType defines the handler type of a probe
+type ProbeType string
+
+const (
+	// ProbeHTTP defines the HTTP GET probe handler
+	ProbeHTTP ProbeType = "HTTP"
+	// ProbeTCP defines the TCP socket probe handler
+	ProbeTCP ProbeType = "TCP"
+	// ProbeExec defines the exec probe handler
+	ProbeExec ProbeType = "Exec"
+	// ProbeGRPC defines the gRPC health checking probe handler, executed by grpc_health_probe in the app container.
+	ProbeGRPC ProbeType = "GRPC"
+)
+
+// BootProbe defines a probe of the app container
+// +k8s:openapi-gen=true
+type BootProbe struct {
+	// Type is the handler type of the probe, can be `HTTP`, `TCP`, `Exec` or `GRPC`. default is `HTTP`
+	// +kubebuilder:validation:Enum=HTTP;TCP;Exec;GRPC
+	// +optional
+	Type ProbeType `json:"type,omitempty"`
+	// Port is the port to probe. Defaults to the app's health port.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+	// Path is the HTTP path to probe. Defaults to the Health or Readiness shorthand.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Scheme is the scheme of the HTTP probe, can be `HTTP` or `HTTPS`. default is `HTTP`
+	// +kubebuilder:validation:Enum=HTTP;HTTPS
+	// +optional
+	Scheme corev1.URIScheme `json:"scheme,omitempty"`
+	// Command is the command of the Exec probe.
+	// +optional
+	Command []string `json:"command,omitempty"`
+	// Service is the service name of the gRPC health checking.
+	// +optional
+	Service string `json:"service,omitempty"`
+	// Number of seconds after the container has started before the probe is initiated.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+	// How often (in seconds) to perform the probe.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+	// Number of seconds after which the probe times out.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// Minimum consecutive successes for the probe to be considered successful after having failed.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	SuccessThreshold *int32 `json:"successThreshold,omitempty"`
+	// Minimum consecutive failures for the probe to be considered failed after having succeeded.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
 }
 
 // Workload defines the wordload type for the boot
@@ -114,6 +581,9 @@ type BootStatus struct {
 	// Services is the service's name of the boot, include app and sidecar
 	// +optional
 	Services string `json:"services,omitempty"`
+	// Ingresses is the Ingress or Route's name of the boot, split by ,
+	// +optional
+	Ingresses string `json:"ingresses,omitempty"`
 	// Workload is the wordload type for the boot,can be `Deployment` or `StatefulSet`
 	// +optional
 	// +kubebuilder:validation:Enum=Deployment;StatefulSet
@@ -136,6 +606,56 @@ type BootStatus struct {
 	// Revision is the revision ID of the boot
 	// +optional
 	Revision string `json:"revision,omitempty"`
+	// ImageDigest is the digest of the image the boot's pods are pinned to
+	// +optional
+	ImageDigest string `json:"imageDigest,omitempty"`
+	// ObservedGeneration is the most recent generation observed by the operator.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions represent the latest available observations of the boot's current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []BootCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// BootConditionType defines the condition type of the boot
+type BootConditionType string
+
+const (
+	// BootAvailable means all the desired replicas of the boot are ready.
+	BootAvailable BootConditionType = "Available"
+	// BootProgressing means the boot's workload is rolling out a new template.
+	BootProgressing BootConditionType = "Progressing"
+	// BootDegraded means the boot's workload failed to make progress.
+	BootDegraded BootConditionType = "Degraded"
+	// BootReconcileFailed means the latest reconcile of the boot failed.
+	BootReconcileFailed BootConditionType = "ReconcileFailed"
+	// BootConfigInvalid means the operator config selected by the boot is invalid.
+	BootConfigInvalid BootConditionType = "ConfigInvalid"
+	// BootRolledBack means the boot's latest revision is rolled back from a previous one.
+	BootRolledBack BootConditionType = "RolledBack"
+)
+
+// BootCondition describes the state of a boot at a certain point.
+// +k8s:openapi-gen=true
+type BootCondition struct {
+	// Type of boot condition.
+	Type BootConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+	// The last time this condition was updated.
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+	// Last time the condition transitioned from one status to another.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// The reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// A human readable message indicating details about the transition.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // PersistentVolumeClaimMount defines the Boot match a PersistentVolumeClaim
@@ -155,6 +675,38 @@ type PersistentVolumeClaimMount struct {
 	MountPath string `json:"mountPath" protobuf:"bytes,3,opt,name=mountPath"`
 }
 
+// BootVolume defines a volume declared on the Boot and mounted in the app container.
+// Exactly one of ConfigMap, Secret, EmptyDir and Projected must be set.
+// +k8s:openapi-gen=true
+type BootVolume struct {
+	// Name of the volume, must be a DNS_LABEL and unique within the boot.
+	Name string `json:"name"`
+	// Path within the container at which the volume should be mounted.  Must
+	// not contain ':'.
+	MountPath string `json:"mountPath"`
+	// Path within the volume from which the container's volume should be mounted.
+	// Defaults to "" (volume's root).
+	// +optional
+	SubPath string `json:"subPath,omitempty"`
+	// Mounted read-only if true, read-write otherwise (false or unspecified).
+	// Defaults to false.
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+	// ConfigMap represents a configMap that should populate this volume
+	// +optional
+	ConfigMap *corev1.ConfigMapVolumeSource `json:"configMap,omitempty"`
+	// Secret represents a secret that should populate this volume, the permission of the boot
+	// must be granted by the secret's `app.logancloud.com/secret-<boot>` annotation.
+	// +optional
+	Secret *corev1.SecretVolumeSource `json:"secret,omitempty"`
+	// EmptyDir represents a temporary directory that shares a pod's lifetime.
+	// +optional
+	EmptyDir *corev1.EmptyDirVolumeSource `json:"emptyDir,omitempty"`
+	// Projected represents the items of configMaps and secrets projected into one directory.
+	// +optional
+	Projected *corev1.ProjectedVolumeSource `json:"projected,omitempty"`
+}
+
 type Hpa struct {
 	// Enable is used to define whether HPA are enabled or not
 	// Defaults to false.
@@ -68,3 +68,25 @@ func (in *BootRevisionList) SelectLatestRevision() *BootRevision {
 
 	return &in.Items[index]
 }
+
+// SelectRevision will return the revision of the ID, nil if not found
+func (in *BootRevisionList) SelectRevision(id int) *BootRevision {
+	for i := range in.Items {
+		if in.Items[i].GetRevisionId() == id {
+			return &in.Items[i]
+		}
+	}
+	return nil
+}
+
+// SelectPreviousRevision will return the revision before the ID, which has the max ID less than it, nil if not found
+func (in *BootRevisionList) SelectPreviousRevision(id int) *BootRevision {
+	var previous *BootRevision
+	for i := range in.Items {
+		revisionId := in.Items[i].GetRevisionId()
+		if revisionId < id && (previous == nil || revisionId > previous.GetRevisionId()) {
+			previous = &in.Items[i]
+		}
+	}
+	return previous
+}
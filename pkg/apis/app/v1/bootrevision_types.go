@@ -12,19 +12,47 @@ import (
 // +kubebuilder:resource:path=bootrevisions,scope=Namespaced
 // +kubebuilder:printcolumn:name="Desired",type="integer",JSONPath=".spec.replicas",description="Number of desired pods"
 // +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".spec.version",description="The Version of Boot"
+// +kubebuilder:printcolumn:name="Author",type="string",JSONPath=".metadata.annotations.app\\.logancloud\\.com/changed-by",description="The user who changed the Boot"
+// +kubebuilder:printcolumn:name="Operation",type="string",JSONPath=".metadata.annotations.app\\.logancloud\\.com/change-operation",description="The operation which changed the Boot"
+// +kubebuilder:printcolumn:name="Change-Cause",type="string",JSONPath=".metadata.annotations.app\\.logancloud\\.com/change-cause",description="The cause of the change"
+// +kubebuilder:printcolumn:name="Groups",type="string",JSONPath=".metadata.annotations.app\\.logancloud\\.com/changed-by-groups",description="The groups of the user who changed the Boot",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type BootRevision struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	// spec contains the desired behavior of the Boot
 	Spec BootSpec `json:"spec,omitempty"`
-	// status contains the last observed state of the BootStatus
-	Status BootStatus `json:"status,omitempty"`
+	// status contains the changes of the revision
+	Status BootRevisionStatus `json:"status,omitempty"`
 
 	BootType string `json:"bootType"`
 	AppKey   string `json:"appKey"`
 }
 
+// BootRevisionStatus defines the observed state of BootRevision
+// +k8s:openapi-gen=true
+type BootRevisionStatus struct {
+	// Changes are the field-level changes of the revision's spec from the previous revision
+	// +optional
+	Changes []BootRevisionChange `json:"changes,omitempty"`
+}
+
+// BootRevisionChange is a change of a field between two revisions
+// +k8s:openapi-gen=true
+type BootRevisionChange struct {
+	// Path is the JSON path of the field, the lists of named objects are indexed by the names, e.g. spec.env[JAVA_OPTS].value
+	Path string `json:"path"`
+	// Old is the field's value in the previous revision, empty if the field is added. The values of the envs from
+	// secrets are redacted
+	// +optional
+	Old string `json:"old,omitempty"`
+	// New is the field's value in the revision, empty if the field is removed. The values of the envs from secrets
+	// are redacted
+	// +optional
+	New string `json:"new,omitempty"`
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // BootRevisionList contains a list of BootRevision
@@ -0,0 +1,54 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetCondition return the condition with the given type, nil if not found
+func (in *BootStatus) GetCondition(condType BootConditionType) *BootCondition {
+	for i := range in.Conditions {
+		if in.Conditions[i].Type == condType {
+			return &in.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// IsConditionTrue return whether the condition with the given type is True
+func (in *BootStatus) IsConditionTrue(condType BootConditionType) bool {
+	cond := in.GetCondition(condType)
+	return cond != nil && cond.Status == corev1.ConditionTrue
+}
+
+// SetCondition will set the condition with the given type, return true if the condition is changed.
+// LastTransitionTime is only changed when the status changed.
+func (in *BootStatus) SetCondition(condType BootConditionType, status corev1.ConditionStatus,
+	reason, message string) bool {
+	now := metav1.Now()
+	cond := in.GetCondition(condType)
+	if cond == nil {
+		in.Conditions = append(in.Conditions, BootCondition{
+			Type:               condType,
+			Status:             status,
+			LastUpdateTime:     now,
+			LastTransitionTime: now,
+			Reason:             reason,
+			Message:            message,
+		})
+		return true
+	}
+
+	if cond.Status == status && cond.Reason == reason && cond.Message == message {
+		return false
+	}
+
+	if cond.Status != status {
+		cond.LastTransitionTime = now
+	}
+	cond.Status = status
+	cond.Reason = reason
+	cond.Message = message
+	cond.LastUpdateTime = now
+	return true
+}
@@ -12,7 +12,10 @@ import (
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
 		"github.com/logancloud/logan-app-operator/pkg/apis/app/v1.Boot":                       schema_pkg_apis_app_v1_Boot(ref),
+		"github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootCondition":              schema_pkg_apis_app_v1_BootCondition(ref),
 		"github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootRevision":               schema_pkg_apis_app_v1_BootRevision(ref),
+		"github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootRevisionChange":         schema_pkg_apis_app_v1_BootRevisionChange(ref),
+		"github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootRevisionStatus":         schema_pkg_apis_app_v1_BootRevisionStatus(ref),
 		"github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootSpec":                   schema_pkg_apis_app_v1_BootSpec(ref),
 		"github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootStatus":                 schema_pkg_apis_app_v1_BootStatus(ref),
 		"github.com/logancloud/logan-app-operator/pkg/apis/app/v1.JavaBoot":                   schema_pkg_apis_app_v1_JavaBoot(ref),
@@ -83,6 +86,62 @@ func schema_pkg_apis_app_v1_Boot(ref common.ReferenceCallback) common.OpenAPIDef
 	}
 }
 
+func schema_pkg_apis_app_v1_BootCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "BootCondition describes the state of a boot at a certain point.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type of boot condition.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Status of the condition, one of True, False, Unknown.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastUpdateTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The last time this condition was updated.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"lastTransitionTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Last time the condition transitioned from one status to another.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The reason for the condition's last transition.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A human readable message indicating details about the transition.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"type", "status"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema_pkg_apis_app_v1_BootRevision(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -117,8 +176,8 @@ func schema_pkg_apis_app_v1_BootRevision(ref common.ReferenceCallback) common.Op
 					},
 					"status": {
 						SchemaProps: spec.SchemaProps{
-							Description: "status contains the last observed state of the BootStatus",
-							Ref:         ref("github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootStatus"),
+							Description: "status contains the changes of the revision",
+							Ref:         ref("github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootRevisionStatus"),
 						},
 					},
 					"bootType": {
@@ -138,7 +197,70 @@ func schema_pkg_apis_app_v1_BootRevision(ref common.ReferenceCallback) common.Op
 			},
 		},
 		Dependencies: []string{
-			"github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootSpec", "github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootRevisionStatus", "github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_app_v1_BootRevisionChange(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "BootRevisionChange is a change of a field between two revisions",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Path is the JSON path of the field, the lists of named objects are indexed by the names, e.g. spec.env[JAVA_OPTS].value",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"old": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Old is the field's value in the previous revision, empty if the field is added. The values of the envs from secrets are redacted",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"new": {
+						SchemaProps: spec.SchemaProps{
+							Description: "New is the field's value in the revision, empty if the field is removed. The values of the envs from secrets are redacted",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"path"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_app_v1_BootRevisionStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "BootRevisionStatus defines the observed state of BootRevision",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"changes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Changes are the field-level changes of the revision's spec from the previous revision",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootRevisionChange"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootRevisionChange"},
 	}
 }
 
@@ -163,6 +285,13 @@ func schema_pkg_apis_app_v1_BootSpec(ref common.ReferenceCallback) common.OpenAP
 							Format:      "",
 						},
 					},
+					"imageDigest": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImageDigest is the immutable digest of the image's version, such as `sha256:...`, which pins the app container's image to `image@digest`. In the digest mode of the config's settings, it is resolved by the operator once the version is changed. It is cleared when the version is changed without it.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"replicas": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Replicas is the number of desired replicas. This is a pointer to distinguish between explicit zero and unspecified. Defaults to 1.",
@@ -189,6 +318,19 @@ func schema_pkg_apis_app_v1_BootSpec(ref common.ReferenceCallback) common.OpenAP
 							},
 						},
 					},
+					"envFrom": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EnvFrom is list of ConfigMaps and Secrets to populate environment variables in the app container. The keys can not override the environment variables enforced by the settings, and the permission of the secrets must be granted by the secret's `app.logancloud.com/secret-<boot>` annotation.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.EnvFromSource"),
+									},
+								},
+							},
+						},
+					},
 					"port": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Port that are exposed by the app container",
@@ -196,9 +338,42 @@ func schema_pkg_apis_app_v1_BootSpec(ref common.ReferenceCallback) common.OpenAP
 							Format:      "int32",
 						},
 					},
+					"ports": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-patch-merge-key": "name",
+								"x-kubernetes-patch-strategy":  "merge",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Ports is the list of additional ports of the app container, the primary Port is always named `http`.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootPort"),
+									},
+								},
+							},
+						},
+					},
+					"metricsPort": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MetricsPort is the name of the port for prometheus to scrape, default is `http`, the primary Port.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"subDomain": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Reserved, not used. for latter use",
+							Description: "SubDomain will expose the boot externally by an Ingress, or a Route on OpenShift. The host is built from the subDomain and the domainTemplate of the operator settings. If empty, the boot will not be exposed.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"tlsSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TLSSecret is the name of the secret holding the TLS certificate(`tls.crt` and `tls.key`) of the subDomain's host. If empty, the host is served over http.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -217,6 +392,12 @@ func schema_pkg_apis_app_v1_BootSpec(ref common.ReferenceCallback) common.OpenAP
 							Format:      "",
 						},
 					},
+					"probes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Probes is the structured liveness and readiness probes for the app container, and the startup delay of the liveness probe. A probe defined here takes precedence over the Health and Readiness shorthand.",
+							Ref:         ref("github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootProbes"),
+						},
+					},
 					"prometheus": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Prometheus will scrape metrics from the service, default is `true`",
@@ -245,6 +426,45 @@ func schema_pkg_apis_app_v1_BootSpec(ref common.ReferenceCallback) common.OpenAP
 							},
 						},
 					},
+					"tolerations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Tolerations will set the tolerations of the boot's pods, merged with the tolerations of the boot type's config. The taints locked by the config can not be tolerated.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.Toleration"),
+									},
+								},
+							},
+						},
+					},
+					"nodeAffinity": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NodeAffinity is the required and preferred node affinity of the boot's pods, merged with the node affinity of the boot type's config. The node labels locked by the config can not be used.",
+							Ref:         ref("k8s.io/api/core/v1.NodeAffinity"),
+						},
+					},
+					"zoneAntiAffinity": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ZoneAntiAffinity will spread the boot's pods across zones, can be `Preferred` or `Required`. default is ``",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"topologySpreadConstraints": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TopologySpreadConstraints describes how the boot's pods spread across the topology domains.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootTopologySpreadConstraint"),
+									},
+								},
+							},
+						},
+					},
 					"command": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Command is command for boot's container. If empty, will use image's ENTRYPOINT, specified here if needed override.",
@@ -259,6 +479,47 @@ func schema_pkg_apis_app_v1_BootSpec(ref common.ReferenceCallback) common.OpenAP
 							},
 						},
 					},
+					"args": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Args are the arguments of boot's container. If empty, will use image's CMD, specified here if needed override.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"workingDir": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WorkingDir is the working directory of boot's container. If empty, will use image's WORKDIR.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"imagePullPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImagePullPolicy is the image pull policy of boot's container, can be `Always`, `IfNotPresent` or `Never`. Defaults to the imagePullPolicy of the config's settings, or `Always`.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"imagePullSecrets": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImagePullSecrets are the secrets in the boot's namespace to pull the images. Defaults to the imagePullSecrets of the config's settings.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.LocalObjectReference"),
+									},
+								},
+							},
+						},
+					},
 					"sessionAffinity": {
 						SchemaProps: spec.SchemaProps{
 							Description: "SessionAffinity is SessionAffinity for boot's created service. If empty, will not set",
@@ -292,6 +553,44 @@ func schema_pkg_apis_app_v1_BootSpec(ref common.ReferenceCallback) common.OpenAP
 							},
 						},
 					},
+					"volumes": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-patch-merge-key": "name",
+								"x-kubernetes-patch-strategy":  "merge",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Volumes is list of ConfigMap, Secret, EmptyDir or Projected volumes to mount in the app container.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootVolume"),
+									},
+								},
+							},
+						},
+					},
+					"autoRestart": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AutoRestart will restart the pods when the Secrets or ConfigMaps referenced by env, envFrom and volumes are changed, default is `true`.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"serviceAccount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ServiceAccount is the ServiceAccount of the boot's pods, which references an existing ServiceAccount or creates one owned by the boot. If not specified, the pods run with the namespace's default ServiceAccount.",
+							Ref:         ref("github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootServiceAccount"),
+						},
+					},
+					"securityContext": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SecurityContext is the security options of the boot's pods and app container, which must comply with the security policy of the boot type's config in the environment.",
+							Ref:         ref("github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootSecurityContext"),
+						},
+					},
 					"priority": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Priority will set the priorityClassName for the boot's workloads, default is ``",
@@ -299,6 +598,13 @@ func schema_pkg_apis_app_v1_BootSpec(ref common.ReferenceCallback) common.OpenAP
 							Format:      "",
 						},
 					},
+					"placement": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Placement is the name of the placement class defined in the boot type's config, which provides the nodeSelector, tolerations, node affinity and priorityClass of the boot's pods. The namespace must be granted by the Namespace's `app.logancloud.com/placement-<class>` annotation. default is ``",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"workload": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Workload will set the wordload type for the boot,can be `Deployment` or `StatefulSet`. default is `Deployment`",
@@ -312,12 +618,60 @@ func schema_pkg_apis_app_v1_BootSpec(ref common.ReferenceCallback) common.OpenAP
 							Ref:         ref("github.com/logancloud/logan-app-operator/pkg/apis/app/v1.Hpa"),
 						},
 					},
+					"strategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Strategy is the update strategy used to replace old pods by new ones. Defaults to the strategy of the boot type's config.",
+							Ref:         ref("github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootStrategy"),
+						},
+					},
+					"rollout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Rollout is the progressive rollout of the boot's new version. If not specified, the whole workload is rolled at once by the update strategy.",
+							Ref:         ref("github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootRollout"),
+						},
+					},
+					"disruption": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Disruption is the PodDisruptionBudget of the boot's pods, which limits the pods taken down at the same time by voluntary disruptions, such as node drains. Defaults to the disruption of the boot type's config.",
+							Ref:         ref("github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootDisruption"),
+						},
+					},
+					"shutdown": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Shutdown is the graceful shutdown of the boot's pods: the termination grace period, the preStop hook and the drain delay. Defaults to the shutdown of the boot type's config.",
+							Ref:         ref("github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootShutdown"),
+						},
+					},
+					"podMetadata": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodMetadata is the additional labels and annotations of the boot's pods, changing it restarts the pods. The reserved keys, such as the selector labels and the prometheus annotations, can not be used.",
+							Ref:         ref("github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootMetadata"),
+						},
+					},
+					"serviceMetadata": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ServiceMetadata is the additional labels and annotations of the boot's Services.",
+							Ref:         ref("github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootMetadata"),
+						},
+					},
+					"workloadMetadata": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WorkloadMetadata is the additional labels and annotations of the boot's Deployments or StatefulSet.",
+							Ref:         ref("github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootMetadata"),
+						},
+					},
+					"overrides": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Overrides are the strategic merge patches of the objects generated for the boot, for the fields which can not be expressed by the boot. The patched paths must be allowed by the config's overrides.",
+							Ref:         ref("github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootOverrides"),
+						},
+					},
 				},
 				Required: []string{"image", "version"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/logancloud/logan-app-operator/pkg/apis/app/v1.Hpa", "github.com/logancloud/logan-app-operator/pkg/apis/app/v1.PersistentVolumeClaimMount", "k8s.io/api/core/v1.EnvVar", "k8s.io/api/core/v1.ResourceRequirements"},
+			"github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootDisruption", "github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootMetadata", "github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootOverrides", "github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootPort", "github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootProbes", "github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootRollout", "github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootSecurityContext", "github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootServiceAccount", "github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootShutdown", "github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootStrategy", "github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootTopologySpreadConstraint", "github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootVolume", "github.com/logancloud/logan-app-operator/pkg/apis/app/v1.Hpa", "github.com/logancloud/logan-app-operator/pkg/apis/app/v1.PersistentVolumeClaimMount", "k8s.io/api/core/v1.EnvFromSource", "k8s.io/api/core/v1.EnvVar", "k8s.io/api/core/v1.LocalObjectReference", "k8s.io/api/core/v1.NodeAffinity", "k8s.io/api/core/v1.ResourceRequirements", "k8s.io/api/core/v1.Toleration"},
 	}
 }
 
@@ -335,6 +689,13 @@ func schema_pkg_apis_app_v1_BootStatus(ref common.ReferenceCallback) common.Open
 							Format:      "",
 						},
 					},
+					"ingresses": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ingresses is the Ingress or Route's name of the boot, split by ,",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"workload": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Workload is the wordload type for the boot,can be `Deployment` or `StatefulSet`",
@@ -384,9 +745,44 @@ func schema_pkg_apis_app_v1_BootStatus(ref common.ReferenceCallback) common.Open
 							Format:      "",
 						},
 					},
+					"imageDigest": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImageDigest is the digest of the image the boot's pods are pinned to",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"observedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ObservedGeneration is the most recent generation observed by the operator.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"conditions": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-patch-merge-key": "type",
+								"x-kubernetes-patch-strategy":  "merge",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions represent the latest available observations of the boot's current state.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootCondition"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"github.com/logancloud/logan-app-operator/pkg/apis/app/v1.BootCondition"},
 	}
 }
 
@@ -29,10 +29,14 @@ import (
 var log = logf.Log.WithName("logan_controller_bootrevision")
 var kindType = "BootRevision"
 
-// Add creates a new BootRevision Controller and adds it to the Manager. The Manager will set fields on the Controller
-// and Start it when the Manager is Started.
+// Add creates a new BootRevision Controller and the revision recorders of the boot types, and adds them to the
+// Manager. The Manager will set fields on the Controllers and Start them when the Manager is Started.
 func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+	err := add(mgr, newReconciler(mgr))
+	if err != nil {
+		return err
+	}
+	return addRecorders(mgr)
 }
 
 // newReconciler returns a new reconcile.Reconciler
@@ -0,0 +1,317 @@
+package bootrevision
+
+import (
+	"context"
+	"fmt"
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	"github.com/logancloud/logan-app-operator/pkg/controller/javaboot"
+	"github.com/logancloud/logan-app-operator/pkg/controller/nodejsboot"
+	"github.com/logancloud/logan-app-operator/pkg/controller/phpboot"
+	"github.com/logancloud/logan-app-operator/pkg/controller/pythonboot"
+	"github.com/logancloud/logan-app-operator/pkg/controller/webboot"
+	"github.com/logancloud/logan-app-operator/pkg/logan"
+	loganMetrics "github.com/logancloud/logan-app-operator/pkg/logan/metrics"
+	"github.com/logancloud/logan-app-operator/pkg/logan/operator"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sort"
+	"strconv"
+	"time"
+)
+
+var recorderKindType = "BootRevisionRecorder"
+
+// recordedBoots are the boot types whose revisions are recorded, with the function creating the object of the type
+var recordedBoots = map[string]func() runtime.Object{
+	logan.BootJava:   func() runtime.Object { return &appv1.JavaBoot{} },
+	logan.BootPhp:    func() runtime.Object { return &appv1.PhpBoot{} },
+	logan.BootPython: func() runtime.Object { return &appv1.PythonBoot{} },
+	logan.BootNodeJS: func() runtime.Object { return &appv1.NodeJSBoot{} },
+	logan.BootWeb:    func() runtime.Object { return &appv1.WebBoot{} },
+}
+
+// changeAnnotationKeys are the Boot's annotations about its change, which are recorded in the new revision once,
+// and removed from the Boot
+var changeAnnotationKeys = []string{
+	keys.BootChangeCauseAnnotationKey,
+	keys.BootChangedByAnnotationKey,
+	keys.BootChangedByGroupsAnnotationKey,
+	keys.BootChangeOperationAnnotationKey,
+}
+
+// addRecorders adds a revision recorder Controller for every boot type to mgr, which records the revisions of
+// the Boots committed to the apiserver.
+func addRecorders(mgr manager.Manager) error {
+	for bootType, newBoot := range recordedBoots {
+		r := &RecordBootRevision{
+			client:   util.NewClient(mgr.GetClient()),
+			scheme:   mgr.GetScheme(),
+			recorder: mgr.GetEventRecorderFor("bootRevision-recorder"),
+			bootType: bootType,
+			newBoot:  newBoot,
+		}
+
+		c, err := controller.New(fmt.Sprintf("bootrevision-%s-recorder", bootType), mgr,
+			controller.Options{Reconciler: r, MaxConcurrentReconciles: logan.MaxConcurrentReconciles})
+		if err != nil {
+			return err
+		}
+
+		err = c.Watch(&source.Kind{Type: newBoot()}, &handler.EnqueueRequestForObject{})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// blank assignment to verify that RecordBootRevision implements reconcile.Reconciler
+var _ reconcile.Reconciler = &RecordBootRevision{}
+
+// RecordBootRevision records the BootRevisions of a boot type
+type RecordBootRevision struct {
+	client   util.K8SClient
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+	bootType string
+	newBoot  func() runtime.Object
+}
+
+// Reconcile records a new revision when the Boot's BootHash is different from the latest revision's, with the
+// changes from the latest revision in its status.
+// It is idempotent: the revision's name is built from its ID, so the same revision is created only once.
+// When a new revision is recorded, the previous one is Cancelled or Complete, and the history is pruned
+// to MaxHistory. The Boot's change cause and author annotations are recorded in the new revision, and removed from
+// the Boot.
+func (r *RecordBootRevision) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	logger := log.WithValues("boot", request, "bootType", r.bootType)
+
+	if operator.Ignore(request.Namespace) {
+		return reconcile.Result{}, nil
+	}
+
+	reconcileStartTS := time.Now()
+	defer func() {
+		loganMetrics.UpdateReconcileTime(recorderKindType, time.Now().Sub(reconcileStartTS))
+	}()
+
+	obj := r.newBoot()
+	err := r.client.Get(context.TODO(), request.NamespacedName, obj)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// The revisions are garbage collected with the Boot
+			return reconcile.Result{}, nil
+		}
+		logger.Error(err, "Failed to get Boot")
+		return reconcile.Result{}, err
+	}
+
+	bootMeta := obj.(metav1.Object)
+	if operator.IsDeletedObject(bootMeta) {
+		return reconcile.Result{}, nil
+	}
+
+	boot := r.defaultedBoot(obj)
+	if boot == nil {
+		return reconcile.Result{}, nil
+	}
+
+	revision, err := r.recordRevision(boot, obj)
+	if err != nil {
+		if errors.IsAlreadyExists(err) || errors.IsConflict(err) {
+			logger.Info("Revision is changed, requeue", "err", err.Error())
+			return reconcile.Result{Requeue: true}, nil
+		}
+		logger.Error(err, "Failed to record revision")
+		return reconcile.Result{}, err
+	}
+
+	// The change is recorded once, clear it for the following changes. The change which keeps the boot's revision,
+	// such as editing only the change cause, is not recorded, and cleared so as not to be recorded in the next one.
+	annotations := bootMeta.GetAnnotations()
+	changed := false
+	for _, key := range changeAnnotationKeys {
+		if value, found := annotations[key]; found {
+			if revision.Annotations[key] != value {
+				logger.Info("Boot's revision is not changed, the change is not recorded", key, value)
+			}
+			delete(annotations, key)
+			changed = true
+		}
+	}
+	if changed {
+		bootMeta.SetAnnotations(annotations)
+		logger.Info("Removing Boot's change annotations")
+		err = r.client.Update(context.TODO(), obj)
+		if err != nil {
+			logger.Info("Failed to remove Boot's change annotations", "err", err.Error())
+			return reconcile.Result{Requeue: true}, nil
+		}
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// defaultedBoot return the boot merged with the default value of the operator's config, the same as the
+// boot's controller. nil if the boot type is not recognized.
+func (r *RecordBootRevision) defaultedBoot(obj runtime.Object) *appv1.Boot {
+	switch typedBoot := obj.DeepCopyObject().(type) {
+	case *appv1.JavaBoot:
+		javaboot.InitHandler(typedBoot, r.scheme, r.client, log, r.recorder).DefaultValue()
+		return typedBoot.DeepCopyBoot()
+	case *appv1.PhpBoot:
+		phpboot.InitHandler(typedBoot, r.scheme, r.client, log, r.recorder).DefaultValue()
+		return typedBoot.DeepCopyBoot()
+	case *appv1.PythonBoot:
+		pythonboot.InitHandler(typedBoot, r.scheme, r.client, log, r.recorder).DefaultValue()
+		return typedBoot.DeepCopyBoot()
+	case *appv1.NodeJSBoot:
+		nodejsboot.InitHandler(typedBoot, r.scheme, r.client, log, r.recorder).DefaultValue()
+		return typedBoot.DeepCopyBoot()
+	case *appv1.WebBoot:
+		webboot.InitHandler(typedBoot, r.scheme, r.client, log, r.recorder).DefaultValue()
+		return typedBoot.DeepCopyBoot()
+	}
+	return nil
+}
+
+// recordRevision will make a new revision record if the boot is changed, owned by the typed boot object.
+// Return the revision of the boot's current spec, the new one or the latest one if the boot is not changed.
+func (r *RecordBootRevision) recordRevision(boot *appv1.Boot, owner runtime.Object) (*appv1.BootRevision, error) {
+	logger := log.WithValues("boot", boot.Name, "namespace", boot.Namespace)
+	c := r.client
+
+	// init a revision
+	revisionBoot := operator.InitBootRevision(boot)
+	hashcode := revisionBoot.BootHash()
+	logger.V(1).Info("RevisionBoot's BootHash", "BootHash", hashcode, "revision", revisionBoot)
+	revisionBoot.Annotations[keys.BootRevisionHashAnnotationKey] = hashcode
+	for _, key := range changeAnnotationKeys {
+		if value := boot.Annotations[key]; value != "" {
+			revisionBoot.Annotations[key] = value
+		}
+	}
+
+	bootLabels := operator.PodLabels(boot)
+	revisionList, err := c.ListRevision(boot.Namespace, bootLabels)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compared to the previous revision
+	latestRevision := revisionList.SelectLatestRevision()
+	newRevisionId := 1
+	if latestRevision != nil {
+		latestHash := latestRevision.Annotations[keys.BootRevisionHashAnnotationKey]
+		logger.V(1).Info("The latest revisionBoot's BootHash", "BootHash", latestHash, "revision", latestRevision)
+		if latestHash == hashcode {
+			//maybe just scale or redeploy
+			logger.V(1).Info("No need to do revision with boot", "revision", revisionBoot)
+			previousRevision := revisionList.SelectPreviousRevision(latestRevision.GetRevisionId())
+			return latestRevision, r.recordChanges(latestRevision, previousRevision)
+		}
+		newRevisionId = latestRevision.GetRevisionId() + 1
+	}
+
+	// Add a new revision to history
+	revisionBoot.Annotations[keys.BootRevisionIdAnnotationKey] = strconv.Itoa(newRevisionId)
+	revisionBoot.Annotations[keys.BootRevisionPhaseAnnotationKey] = operator.RevisionPhaseRunning
+	revisionBoot.Annotations[keys.BootRevisionRetryAnnotationKey] = "0"
+	revisionBoot.Name = revisionBoot.Name + "-" + revisionBoot.Annotations[keys.BootRevisionIdAnnotationKey]
+	revisionBoot.Labels = bootLabels
+	err = controllerutil.SetControllerReference(owner.(metav1.Object), revisionBoot, r.scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Add a new revision to history", "revision", revisionBoot.Name)
+	err = c.Create(context.TODO(), revisionBoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if latestRevision == nil {
+		return revisionBoot, nil
+	}
+
+	err = r.recordChanges(revisionBoot, latestRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update the previous revision's phase
+	latestPhase := latestRevision.Annotations[keys.BootRevisionPhaseAnnotationKey]
+	newPhase := latestPhase
+	if latestPhase == operator.RevisionPhaseRunning || latestPhase == operator.RevisionPhaseCanary {
+		newPhase = operator.RevisionPhaseCancel
+	} else if latestPhase == operator.RevisionPhaseActive {
+		newPhase = operator.RevisionPhaseComplete
+	}
+	if newPhase != latestPhase {
+		latestRevision.Annotations[keys.BootRevisionPhaseAnnotationKey] = newPhase
+		logger.Info("Update the previous revision's phase", "revision", latestRevision.Name,
+			"from", latestPhase, "to", newPhase)
+		err = c.Update(context.TODO(), latestRevision)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// keep max history revision
+	return revisionBoot, r.keepRevisionMaxSizeLimit(revisionList, logan.MaxHistory-1)
+}
+
+// recordChanges will record the revision's changes from the previous revision in its status, if not recorded yet
+func (r *RecordBootRevision) recordChanges(revision, previous *appv1.BootRevision) error {
+	if previous == nil || revision.Status.Changes != nil {
+		return nil
+	}
+
+	changes, err := operator.RevisionChanges(revision, previous)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	revision.Status.Changes = changes
+	log.Info("Record the revision's changes", "revision", revision.Name, "changes", len(changes))
+	return r.client.Status().Update(context.TODO(), revision)
+}
+
+// keepRevisionMaxSizeLimit will keep only the Max size revisions
+func (r *RecordBootRevision) keepRevisionMaxSizeLimit(lst *appv1.BootRevisionList, size int) error {
+	if len(lst.Items) <= size {
+		return nil
+	}
+
+	items := lst.Items
+	sort.Slice(items, func(i, j int) bool {
+		aId := (&items[i]).GetRevisionId()
+		bId := (&items[j]).GetRevisionId()
+		return aId < bId
+	})
+
+	delSize := len(items) - size
+	for _, revision := range items[:delSize] {
+		log.V(1).Info("Delete history revision.", "revision", revision.Name)
+		err := r.client.Delete(context.TODO(), revision.DeepCopyObject())
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package bootrevision
+
+import (
+	"context"
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	"github.com/logancloud/logan-app-operator/pkg/logan"
+	"github.com/logancloud/logan-app-operator/pkg/logan/operator"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"strconv"
+)
+
+var _ = Describe("RecordBootRevision", func() {
+	var r *RecordBootRevision
+	var javaBoot *appv1.JavaBoot
+
+	// listRevisions return the recorded revisions of the boot
+	listRevisions := func() *appv1.BootRevisionList {
+		boot := javaBoot.DeepCopyBoot()
+		lst, err := r.client.ListRevision(boot.Namespace, operator.PodLabels(boot))
+		Expect(err).NotTo(HaveOccurred())
+		return lst
+	}
+
+	// phaseOf return the phase of the revision of the id
+	phaseOf := func(id int) string {
+		revision := listRevisions().SelectRevision(id)
+		Expect(revision).NotTo(BeNil())
+		return revision.Annotations[keys.BootRevisionPhaseAnnotationKey]
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(appv1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+
+		replicas := int32(1)
+		javaBoot = &appv1.JavaBoot{}
+		javaBoot.Name = "recorder"
+		javaBoot.Namespace = "test"
+		javaBoot.UID = types.UID("recorder-uid")
+		javaBoot.Spec.Image = "logan/recorder"
+		javaBoot.Spec.Version = "v1"
+		javaBoot.Spec.Replicas = &replicas
+
+		r = &RecordBootRevision{
+			client:   util.NewClient(fake.NewFakeClientWithScheme(scheme)),
+			scheme:   scheme,
+			recorder: record.NewFakeRecorder(100),
+			bootType: logan.BootJava,
+			newBoot:  recordedBoots[logan.BootJava],
+		}
+	})
+
+	It("test the first revision", func() {
+		javaBoot.Annotations = map[string]string{keys.BootChangeCauseAnnotationKey: "init"}
+		revision, err := r.recordRevision(javaBoot.DeepCopyBoot(), javaBoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revision.GetRevisionId()).To(Equal(1))
+		Expect(revision.Annotations[keys.BootRevisionPhaseAnnotationKey]).To(Equal(operator.RevisionPhaseRunning))
+		Expect(revision.Annotations[keys.BootChangeCauseAnnotationKey]).To(Equal("init"))
+		Expect(revision.Annotations[keys.BootRevisionHashAnnotationKey]).To(
+			Equal(operator.InitBootRevision(javaBoot.DeepCopyBoot()).BootHash()))
+		Expect(revision.OwnerReferences).To(HaveLen(1))
+		Expect(revision.OwnerReferences[0].UID).To(Equal(javaBoot.UID))
+	})
+
+	It("test the unchanged boot keeps the latest revision", func() {
+		_, err := r.recordRevision(javaBoot.DeepCopyBoot(), javaBoot)
+		Expect(err).NotTo(HaveOccurred())
+
+		// scaling and the change cause only do not change the revision
+		replicas := int32(3)
+		javaBoot.Spec.Replicas = &replicas
+		javaBoot.Annotations = map[string]string{keys.BootChangeCauseAnnotationKey: "scale"}
+		revision, err := r.recordRevision(javaBoot.DeepCopyBoot(), javaBoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revision.GetRevisionId()).To(Equal(1))
+		Expect(revision.Annotations[keys.BootChangeCauseAnnotationKey]).To(BeEmpty())
+		Expect(listRevisions().Items).To(HaveLen(1))
+	})
+
+	It("test the changed boot records a new revision", func() {
+		_, err := r.recordRevision(javaBoot.DeepCopyBoot(), javaBoot)
+		Expect(err).NotTo(HaveOccurred())
+
+		javaBoot.Spec.Version = "v2"
+		revision, err := r.recordRevision(javaBoot.DeepCopyBoot(), javaBoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revision.GetRevisionId()).To(Equal(2))
+		Expect(revision.Status.Changes).NotTo(BeEmpty())
+		Expect(listRevisions().Items).To(HaveLen(2))
+		// the previous revision is not Active, it is Cancelled
+		Expect(phaseOf(1)).To(Equal(operator.RevisionPhaseCancel))
+
+		// the Active revision is Complete when the next one is recorded
+		latest := listRevisions().SelectRevision(2)
+		latest.Annotations[keys.BootRevisionPhaseAnnotationKey] = operator.RevisionPhaseActive
+		Expect(r.client.Update(context.TODO(), latest)).To(Succeed())
+
+		javaBoot.Spec.Version = "v3"
+		revision, err = r.recordRevision(javaBoot.DeepCopyBoot(), javaBoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revision.GetRevisionId()).To(Equal(3))
+		Expect(phaseOf(2)).To(Equal(operator.RevisionPhaseComplete))
+	})
+
+	It("test the history is pruned", func() {
+		for i := 1; i <= logan.MaxHistory+2; i++ {
+			javaBoot.Spec.Version = "v" + strconv.Itoa(i)
+			_, err := r.recordRevision(javaBoot.DeepCopyBoot(), javaBoot)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		lst := listRevisions()
+		Expect(lst.Items).To(HaveLen(logan.MaxHistory))
+		Expect(lst.SelectLatestRevision().GetRevisionId()).To(Equal(logan.MaxHistory + 2))
+	})
+})
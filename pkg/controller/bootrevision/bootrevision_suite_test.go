@@ -0,0 +1,13 @@
+package bootrevision
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestBootRevision(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "BootRevision Suite")
+}
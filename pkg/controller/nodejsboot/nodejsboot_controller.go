@@ -13,6 +13,8 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	autoscaling "k8s.io/api/autoscaling/v2beta1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
@@ -91,6 +93,54 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Ingress, or Route on OpenShift
+	err = c.Watch(&source.Kind{Type: operator.NewIngressObject()}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &appv1.NodeJSBoot{},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &policyv1beta1.PodDisruptionBudget{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &appv1.NodeJSBoot{},
+	})
+	if err != nil {
+		return err
+	}
+
+	// ServiceAccount, Role and RoleBinding created by the serviceAccount
+	err = c.Watch(&source.Kind{Type: &corev1.ServiceAccount{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &appv1.NodeJSBoot{},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &rbacv1.Role{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &appv1.NodeJSBoot{},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &rbacv1.RoleBinding{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &appv1.NodeJSBoot{},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Secrets and ConfigMaps referenced by the boots, the boots are restarted when they are changed
+	err = operator.WatchReferences(mgr, c, &appv1.NodeJSBoot{}, &appv1.NodeJSBootList{})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -173,21 +223,29 @@ func (r *ReconcileNodeJSBoot) Reconcile(request reconcile.Request) (reconcile.Re
 	}
 	//}
 
+	// Rollback the Boot to the revision of the rollback annotation
+	if result, requeue, err := bootHandler.ReconcileRollbackUpdate(); requeue {
+		return result, err
+	}
+
 	// 1. Check the existence of components, if not exist, create new one.
 	result, requeue, err := bootHandler.ReconcileCreate()
 	if requeue {
+		r.updateReconcileFailedStatus(bootHandler, nodejsBoot, loganMetrics.RECONCILE_CREATE_STAGE, err)
 		return result, err
 	}
 
 	// 2. Handle the update logic of components
 	result, requeue, err = bootHandler.ReconcileUpdate()
 	if requeue {
+		r.updateReconcileFailedStatus(bootHandler, nodejsBoot, loganMetrics.RECONCILE_UPDATE_STAGE, err)
 		return result, err
 	}
 
 	// 3. Handle the update logic of hpa
 	result, err = bootHandler.ReconcileHpa()
 	if result.Requeue || err != nil {
+		r.updateReconcileFailedStatus(bootHandler, nodejsBoot, loganMetrics.RECONCILE_HPA_STAGE, err)
 		return result, err
 	}
 
@@ -241,6 +299,19 @@ func (r *ReconcileNodeJSBoot) Reconcile(request reconcile.Request) (reconcile.Re
 	return reconcile.Result{}, nil
 }
 
+// updateReconcileFailedStatus will set the ReconcileFailed condition of the Boot when the stage failed
+func (r *ReconcileNodeJSBoot) updateReconcileFailedStatus(bootHandler *operator.BootHandler,
+	nodejsBoot *appv1.NodeJSBoot, stage string, err error) {
+	if !bootHandler.ReconcileFailedStatus(stage, err) {
+		return
+	}
+
+	updateErr := r.client.Status().Update(context.TODO(), nodejsBoot)
+	if updateErr != nil {
+		bootHandler.Logger.Info("Failed to update Boot Status", "err", updateErr.Error())
+	}
+}
+
 // InitHandler will create the Handler for handling logic of Boot
 func InitHandler(nodejsBoot *appv1.NodeJSBoot, scheme *runtime.Scheme,
 	client util.K8SClient, logger logr.Logger, recorder record.EventRecorder) (handler *operator.BootHandler) {
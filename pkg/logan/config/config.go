@@ -2,11 +2,14 @@ package config
 
 import (
 	"bytes"
+	"fmt"
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
 	"github.com/logancloud/logan-app-operator/pkg/logan"
 	"github.com/logancloud/logan-app-operator/pkg/logan/util"
 	"io"
 	"k8s.io/api/core/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
 	"os"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
@@ -43,12 +46,142 @@ type AppSpec struct {
 	Resources    v1.ResourceRequirements `json:"resources"`
 	NodeSelector map[string]string       `json:"nodeSelector"`
 	SubDomain    string                  `json:"subDomain"`
+	// Strategy is the default update strategy of the boot's workload
+	Strategy *appv1.BootStrategy `json:"strategy"`
+	// Disruption is the default PodDisruptionBudget of the boot's pods
+	Disruption *appv1.BootDisruption `json:"disruption"`
+	// Shutdown is the default graceful shutdown of the boot's pods
+	Shutdown *appv1.BootShutdown `json:"shutdown"`
+	// Tolerations are the tolerations of the boot's pods, merged into the boot's tolerations
+	Tolerations []corev1.Toleration `json:"tolerations"`
+	// NodeAffinity is the node affinity of the boot's pods, merged into the boot's node affinity
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity"`
+	// ZoneAntiAffinity is the default zone level anti-affinity of the boot's pods
+	ZoneAntiAffinity appv1.ZoneAntiAffinity `json:"zoneAntiAffinity"`
+	// TopologySpreadConstraints is the default topology spread constraints of the boot's pods
+	TopologySpreadConstraints []appv1.BootTopologySpreadConstraint `json:"topologySpreadConstraints"`
+	// Scheduling is the scheduling policy, which locks the node pools reserved by the config
+	Scheduling *SchedulingPolicy `json:"scheduling"`
+	// Placements are the placement classes selected by the boot's placement, keyed by the class name
+	Placements map[string]*PlacementClass `json:"placements"`
+	// ServiceAccount is the policy of the boot's ServiceAccount, which allows the rules of the boot's Role
+	ServiceAccount *ServiceAccountPolicy `json:"serviceAccount"`
+	// SecurityPolicy is the security policy of the boot's pods, usually set per environment by oEnvs
+	SecurityPolicy *SecurityPolicy `json:"securityPolicy"`
+	// Overrides are the paths of the generated objects which can be patched by the boot's overrides
+	Overrides *OverridePolicy `json:"overrides"`
 
 	PodSpec   *corev1.PodSpec   `json:"podSpec"`
 	Container *corev1.Container `json:"container"`
 	Settings  *SettingsConfig   `json:"settings"`
 }
 
+// SchedulingPolicy define the scheduling keys locked by the config
+type SchedulingPolicy struct {
+	// LockedNodeLabels are the node label keys, which can only be used by the boot's nodeSelector
+	// and nodeAffinity with the values of the config.
+	LockedNodeLabels []string `json:"lockedNodeLabels"`
+	// LockedTaints are the taint keys, which can only be tolerated by the tolerations of the config.
+	LockedTaints []string `json:"lockedTaints"`
+}
+
+// ServiceAccountPolicy define the rules allowed to be granted to the boot's ServiceAccount
+type ServiceAccountPolicy struct {
+	// AllowedRules are the rules the boot's rules must be covered by, no rules are allowed if empty.
+	AllowedRules []rbacv1.PolicyRule `json:"allowedRules"`
+}
+
+// SecurityPolicy define the securityContext the boot's pods must comply with.
+// The app, sidecar and init containers are defaulted to comply with it.
+type SecurityPolicy struct {
+	// RequireNonRoot requires the pod's containers to run as a non-root user
+	RequireNonRoot bool `json:"requireNonRoot"`
+	// RequireReadOnlyRootFilesystem requires the pod's containers to have a read-only root filesystem
+	RequireReadOnlyRootFilesystem bool `json:"requireReadOnlyRootFilesystem"`
+	// AllowedCapabilities are the capabilities the boot's app container can add, none if empty
+	AllowedCapabilities []corev1.Capability `json:"allowedCapabilities"`
+	// DefaultRunAsUser is the UID used if non-root is required and the boot's runAsUser is not specified
+	DefaultRunAsUser *int64 `json:"defaultRunAsUser"`
+}
+
+// OverridePolicy define the paths of the generated objects allowed to be patched by the boot's overrides,
+// such as `spec.template.spec.hostAliases`. A path allows the fields under it, none is allowed if empty.
+// The paths reconciled from the boot's fields can not be allowed, as the operator reverts them.
+type OverridePolicy struct {
+	// Workload are the allowed paths of the Deployment or StatefulSet
+	Workload []string `json:"workload"`
+	// Service are the allowed paths of the app Service
+	Service []string `json:"service"`
+	// Hpa are the allowed paths of the HorizontalPodAutoscaler
+	Hpa []string `json:"hpa"`
+}
+
+// reconciledOverridePaths are the paths of the generated objects reconciled from the boot's fields, the operator
+// reverts the patch of them and rebuilds the object, which restarts the pods again and again.
+// The HorizontalPodAutoscaler is compared with the patched one, none of its paths is reverted.
+var reconciledOverridePaths = map[string][]string{
+	"workload": {
+		"spec.replicas",
+		"spec.selector",
+		"spec.strategy",
+		"spec.updateStrategy",
+		"spec.minReadySeconds",
+		"spec.progressDeadlineSeconds",
+		"spec.template.spec.containers",
+		"spec.template.spec.initContainers",
+		"spec.template.spec.volumes",
+		"spec.template.spec.nodeSelector",
+		"spec.template.spec.tolerations",
+		"spec.template.spec.affinity",
+		"spec.template.spec.terminationGracePeriodSeconds",
+		"spec.template.spec.priorityClassName",
+		"spec.template.spec.serviceAccountName",
+		"spec.template.spec.automountServiceAccountToken",
+		"spec.template.spec.imagePullSecrets",
+		"spec.template.spec.securityContext",
+	},
+	"service": {
+		"spec.ports",
+		"spec.selector",
+		"spec.sessionAffinity",
+	},
+}
+
+// validate return error if any allowed path overlaps the paths reconciled by the operator
+func (policy *OverridePolicy) validate() error {
+	for _, item := range []struct {
+		name    string
+		allowed []string
+	}{
+		{"workload", policy.Workload},
+		{"service", policy.Service},
+		{"hpa", policy.Hpa},
+	} {
+		for _, allowedPath := range item.allowed {
+			for _, reconciledPath := range reconciledOverridePaths[item.name] {
+				if util.PathAllowed(allowedPath, []string{reconciledPath}) ||
+					util.PathAllowed(reconciledPath, []string{allowedPath}) {
+					return fmt.Errorf("the %s override path %s overlaps the path %s reconciled by the operator",
+						item.name, allowedPath, reconciledPath)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// PlacementClass define a named placement of the boot's pods, such as `batch` or `spot`
+type PlacementClass struct {
+	// NodeSelector is merged into the boot's nodeSelector, the class's values win
+	NodeSelector map[string]string `json:"nodeSelector"`
+	// Tolerations are added to the boot's tolerations
+	Tolerations []corev1.Toleration `json:"tolerations"`
+	// NodeAffinity is merged into the boot's node affinity
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity"`
+	// PriorityClassName is used if the boot's priority is empty
+	PriorityClassName string `json:"priorityClassName"`
+}
+
 // SidecarService define the service for Sidecar
 type SidecarService struct {
 	Name string `json:"name"`
@@ -75,6 +208,18 @@ type SettingsConfig struct {
 	Registry         string `json:"registry"`
 	AppHealthPort    int32  `json:"appHealthPort"`
 	PrometheusScrape *bool  `json:"prometheusScrape"`
+	// DomainTemplate is the cluster domain template for the host of the boot's Ingress/Route,
+	// ${SUBDOMAIN} is replaced with the boot's subDomain, ${APP} and ${ENV} are also supported.
+	// e.g. "${SUBDOMAIN}.apps.logan.local". If empty, the boot will not be exposed.
+	DomainTemplate string `json:"domainTemplate"`
+	// ImagePullPolicy is the default imagePullPolicy of the app container, such as `IfNotPresent` for the
+	// environments with immutable tags. If empty, `Always` is used.
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy"`
+	// ImagePullSecrets are the default secrets to pull the images, which must exist in the boot's namespace.
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets"`
+	// ImageDigest is the digest mode: the boot's version is resolved to the image digest from the registry
+	// once it is changed, and the pods are pinned to `image@digest`.
+	ImageDigest *bool `json:"imageDigest"`
 }
 
 // GlobalConfig is the entry for all boot's config
@@ -142,6 +287,15 @@ func NewConfig(content io.Reader) error {
 	gConfig := c
 	gConfig.applyDefaults()
 
+	for key, operator := range gConfig {
+		if operator.AppSpec.Overrides == nil {
+			continue
+		}
+		if err := operator.AppSpec.Overrides.validate(); err != nil {
+			return fmt.Errorf("invalid overrides of %s: %v", key, err)
+		}
+	}
+
 	operator := gConfig[logan.BootJava]
 	JavaConfig = &BootConfig{
 		AppSpec: operator.AppSpec,
@@ -351,6 +505,18 @@ func applyDefault(operatorCfg *OperatorConfig, appSpec *AppSpec, bootType string
 		if envSettings.PrometheusScrape != nil {
 			oSettings.PrometheusScrape = envSettings.PrometheusScrape
 		}
+		if envSettings.DomainTemplate != "" {
+			oSettings.DomainTemplate = envSettings.DomainTemplate
+		}
+		if envSettings.ImagePullPolicy != "" {
+			oSettings.ImagePullPolicy = envSettings.ImagePullPolicy
+		}
+		if len(envSettings.ImagePullSecrets) > 0 {
+			oSettings.ImagePullSecrets = envSettings.ImagePullSecrets
+		}
+		if envSettings.ImageDigest != nil {
+			oSettings.ImageDigest = envSettings.ImageDigest
+		}
 	}
 
 	// 2.2 Global Settings-> App Settings
@@ -367,6 +533,22 @@ func applyDefault(operatorCfg *OperatorConfig, appSpec *AppSpec, bootType string
 		if oSettings.PrometheusScrape != nil {
 			appSpec.Settings.PrometheusScrape = oSettings.PrometheusScrape
 		}
+
+		if oSettings.DomainTemplate != "" {
+			appSpec.Settings.DomainTemplate = oSettings.DomainTemplate
+		}
+
+		if oSettings.ImagePullPolicy != "" {
+			appSpec.Settings.ImagePullPolicy = oSettings.ImagePullPolicy
+		}
+
+		if len(oSettings.ImagePullSecrets) > 0 {
+			appSpec.Settings.ImagePullSecrets = oSettings.ImagePullSecrets
+		}
+
+		if oSettings.ImageDigest != nil {
+			appSpec.Settings.ImageDigest = oSettings.ImageDigest
+		}
 	}
 
 	//2.3 App settings PrometheusScrape set default
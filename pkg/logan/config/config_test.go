@@ -256,6 +256,22 @@ java:
 
 		})
 
+		It("Test app config overrides of the reconciled paths", func() {
+			for _, path := range []string{"spec.template.spec.tolerations", "spec.template.spec", "spec.replicas"} {
+				text := `
+java:
+  oEnvs:
+    app:
+      test:
+        overrides:
+          workload:
+            - ` + path + `
+`
+				err := NewConfigFromString(text)
+				Expect(err).To(HaveOccurred())
+			}
+		})
+
 		It("Test PHP app config sidecar env order", func() {
 			text := `
 php:
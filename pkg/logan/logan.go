@@ -22,6 +22,9 @@ const (
 	oRevisionMaxHistoryKey = "MAX_HISTORY"
 	oBizENVKey             = "BIZ_ENVS"
 
+	defaultServiceAccount = "logan-app-operator"
+	oServiceAccountKey    = "SERVICE_ACCOUNT"
+
 	// BootJava is for JavaBoot type
 	BootJava = "java"
 	// BootPhp is for PhpBoot type
@@ -63,6 +66,12 @@ var MaxHistory int
 // BizEnvs is what ENV needs to be filtered
 var BizEnvs map[string]bool
 
+// OperatorServiceAccount is the operator's ServiceAccount, whose changes of the Boots are not recorded as the authors
+var OperatorServiceAccount string
+
+// RouteEnabled is whether the cluster serves OpenShift's route.openshift.io, boots are exposed by Route instead of Ingress
+var RouteEnabled bool
+
 var log = logf.Log.WithName("logan_util")
 
 func init() {
@@ -131,5 +140,13 @@ func init() {
 		}
 	}
 
+	serviceAccount, found := os.LookupEnv(oServiceAccountKey)
+	if !found || serviceAccount == "" {
+		log.Info("SERVICE_ACCOUNT not set, use default", "SERVICE_ACCOUNT", defaultServiceAccount)
+		OperatorServiceAccount = defaultServiceAccount
+	} else {
+		OperatorServiceAccount = serviceAccount
+	}
+
 	MaxConcurrentReconciles = runtime.NumCPU() * 2
 }
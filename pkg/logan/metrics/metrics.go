@@ -15,12 +15,18 @@ const (
 	// RECONCILE_UPDATE_BOOT_DEFAULTERS_STAGE is main stage to update boot with defaulters
 	RECONCILE_UPDATE_BOOT_DEFAULTERS_STAGE = "reconcile_update_boot_defaulters"
 
+	// RECONCILE_ROLLBACK_STAGE is main stage to rollback boot to a revision
+	RECONCILE_ROLLBACK_STAGE = "reconcile_rollback"
+
 	// RECONCILE_CREATE_STAGE is main stage to create deployment, service, etc.
 	RECONCILE_CREATE_STAGE = "reconcile_create"
 
 	// RECONCILE_UPDATE_STAGE is main stage to update deployment, service, etc.
 	RECONCILE_UPDATE_STAGE = "reconcile_update"
 
+	// RECONCILE_HPA_STAGE is main stage to create, update or delete hpa.
+	RECONCILE_HPA_STAGE = "reconcile_hpa"
+
 	// RECONCILE_UPDATE_BOOT_META_STAGE is main stage to update boot's metadata.
 	RECONCILE_UPDATE_BOOT_META_STAGE = "reconcile_update_boot_meta"
 
@@ -47,6 +53,33 @@ const (
 	// RECONCILE_UPDATE_STATEFULSET_SUBSTAGE is sub stage to update statefulset.
 	RECONCILE_UPDATE_STATEFULSET_SUBSTAGE = "update_statefulset"
 
+	// RECONCILE_GET_CANARY_SUBSTAGE is sub stage to get canary deployment.
+	RECONCILE_GET_CANARY_SUBSTAGE = "get_canary"
+
+	// RECONCILE_CREATE_CANARY_SUBSTAGE is sub stage to create canary deployment.
+	RECONCILE_CREATE_CANARY_SUBSTAGE = "create_canary"
+
+	// RECONCILE_UPDATE_CANARY_SUBSTAGE is sub stage to update canary deployment.
+	RECONCILE_UPDATE_CANARY_SUBSTAGE = "update_canary"
+
+	// RECONCILE_DELETE_CANARY_SUBSTAGE is sub stage to delete canary deployment.
+	RECONCILE_DELETE_CANARY_SUBSTAGE = "delete_canary"
+
+	// RECONCILE_GET_COLOR_SUBSTAGE is sub stage to get blue/green deployment.
+	RECONCILE_GET_COLOR_SUBSTAGE = "get_color"
+
+	// RECONCILE_CREATE_COLOR_SUBSTAGE is sub stage to create blue/green deployment.
+	RECONCILE_CREATE_COLOR_SUBSTAGE = "create_color"
+
+	// RECONCILE_UPDATE_COLOR_SUBSTAGE is sub stage to update blue/green deployment.
+	RECONCILE_UPDATE_COLOR_SUBSTAGE = "update_color"
+
+	// RECONCILE_DELETE_COLOR_SUBSTAGE is sub stage to delete blue/green deployment.
+	RECONCILE_DELETE_COLOR_SUBSTAGE = "delete_color"
+
+	// RECONCILE_SWITCH_SERVICE_SUBSTAGE is sub stage to switch the services' selector.
+	RECONCILE_SWITCH_SERVICE_SUBSTAGE = "switch_service"
+
 	// RECONCILE_CREATE_SERVICE_SUBSTAGE is sub stage to create service.
 	RECONCILE_CREATE_SERVICE_SUBSTAGE = "create_service"
 
@@ -68,9 +101,72 @@ const (
 	// RECONCILE_DELETE_OTHER_SERVICE_SUBSTAGE is sub stage to delete other service.
 	RECONCILE_DELETE_OTHER_SERVICE_SUBSTAGE = "delete_other_service"
 
+	// RECONCILE_GET_INGRESS_SUBSTAGE is sub stage to get ingress or route.
+	RECONCILE_GET_INGRESS_SUBSTAGE = "get_ingress"
+
+	// RECONCILE_CREATE_INGRESS_SUBSTAGE is sub stage to create ingress or route.
+	RECONCILE_CREATE_INGRESS_SUBSTAGE = "create_ingress"
+
+	// RECONCILE_UPDATE_INGRESS_SUBSTAGE is sub stage to update ingress or route.
+	RECONCILE_UPDATE_INGRESS_SUBSTAGE = "update_ingress"
+
+	// RECONCILE_DELETE_INGRESS_SUBSTAGE is sub stage to delete ingress or route.
+	RECONCILE_DELETE_INGRESS_SUBSTAGE = "delete_ingress"
+
+	// RECONCILE_GET_PDB_SUBSTAGE is sub stage to get PodDisruptionBudget.
+	RECONCILE_GET_PDB_SUBSTAGE = "get_pdb"
+
+	// RECONCILE_CREATE_PDB_SUBSTAGE is sub stage to create PodDisruptionBudget.
+	RECONCILE_CREATE_PDB_SUBSTAGE = "create_pdb"
+
+	// RECONCILE_UPDATE_PDB_SUBSTAGE is sub stage to update PodDisruptionBudget.
+	RECONCILE_UPDATE_PDB_SUBSTAGE = "update_pdb"
+
+	// RECONCILE_DELETE_PDB_SUBSTAGE is sub stage to delete PodDisruptionBudget.
+	RECONCILE_DELETE_PDB_SUBSTAGE = "delete_pdb"
+
+	// RECONCILE_GET_SERVICEACCOUNT_SUBSTAGE is sub stage to get ServiceAccount.
+	RECONCILE_GET_SERVICEACCOUNT_SUBSTAGE = "get_serviceaccount"
+
+	// RECONCILE_CREATE_SERVICEACCOUNT_SUBSTAGE is sub stage to create ServiceAccount.
+	RECONCILE_CREATE_SERVICEACCOUNT_SUBSTAGE = "create_serviceaccount"
+
+	// RECONCILE_DELETE_SERVICEACCOUNT_SUBSTAGE is sub stage to delete ServiceAccount.
+	RECONCILE_DELETE_SERVICEACCOUNT_SUBSTAGE = "delete_serviceaccount"
+
+	// RECONCILE_GET_ROLE_SUBSTAGE is sub stage to get Role.
+	RECONCILE_GET_ROLE_SUBSTAGE = "get_role"
+
+	// RECONCILE_CREATE_ROLE_SUBSTAGE is sub stage to create Role.
+	RECONCILE_CREATE_ROLE_SUBSTAGE = "create_role"
+
+	// RECONCILE_UPDATE_ROLE_SUBSTAGE is sub stage to update Role.
+	RECONCILE_UPDATE_ROLE_SUBSTAGE = "update_role"
+
+	// RECONCILE_DELETE_ROLE_SUBSTAGE is sub stage to delete Role.
+	RECONCILE_DELETE_ROLE_SUBSTAGE = "delete_role"
+
+	// RECONCILE_GET_ROLEBINDING_SUBSTAGE is sub stage to get RoleBinding.
+	RECONCILE_GET_ROLEBINDING_SUBSTAGE = "get_rolebinding"
+
+	// RECONCILE_CREATE_ROLEBINDING_SUBSTAGE is sub stage to create RoleBinding.
+	RECONCILE_CREATE_ROLEBINDING_SUBSTAGE = "create_rolebinding"
+
+	// RECONCILE_UPDATE_ROLEBINDING_SUBSTAGE is sub stage to update RoleBinding.
+	RECONCILE_UPDATE_ROLEBINDING_SUBSTAGE = "update_rolebinding"
+
+	// RECONCILE_DELETE_ROLEBINDING_SUBSTAGE is sub stage to delete RoleBinding.
+	RECONCILE_DELETE_ROLEBINDING_SUBSTAGE = "delete_rolebinding"
+
+	// RECONCILE_APPLY_OVERRIDE_SUBSTAGE is sub stage to apply the boot's overrides.
+	RECONCILE_APPLY_OVERRIDE_SUBSTAGE = "apply_override"
+
 	// RECONCILE_LIST_PODS_SUBSTAGE is sub stage to list pods.
 	RECONCILE_LIST_PODS_SUBSTAGE = "list_pods"
 
+	// RECONCILE_LIST_REVISIONS_SUBSTAGE is sub stage to list boot revisions.
+	RECONCILE_LIST_REVISIONS_SUBSTAGE = "list_revisions"
+
 	// RECONCILE_UPDATE_BOOT_META_SUBSTAGE is sub stage to update boot metadata.
 	RECONCILE_UPDATE_BOOT_META_SUBSTAGE = "update_boot_meta"
 
@@ -92,12 +188,28 @@ var (
 		Name: "logan_controller_runtime_reconcile_time_seconds",
 		Help: "Length of time per logan reconciliation per controller",
 	}, []string{"kind"})
+
+	// BootRollbacks is a prometheus counter metrics which holds the total
+	// number of boots rolled back to a revision
+	BootRollbacks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "logan_boot_rollbacks_total",
+		Help: "Total number of boots rolled back to a revision per controller",
+	}, []string{"kind", "boot"})
+
+	// RevisionFailures is a prometheus counter metrics which holds the total
+	// number of boot revisions failed to become Active
+	RevisionFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "logan_boot_revision_failures_total",
+		Help: "Total number of boot revisions failed to become Active per controller",
+	}, []string{"kind", "boot"})
 )
 
 func init() {
 	metrics.Registry.MustRegister(
 		ReconcileErrors,
 		ReconcileTime,
+		BootRollbacks,
+		RevisionFailures,
 	)
 }
 
@@ -111,6 +223,16 @@ func UpdateReconcileErrors(kind string, stage string, subStage string, boot stri
 	ReconcileErrors.WithLabelValues(kind, stage, subStage, boot).Inc()
 }
 
+// UpdateBootRollbacks will update rollback metrics for each boot rolled back to a revision
+func UpdateBootRollbacks(kind string, boot string) {
+	BootRollbacks.WithLabelValues(kind, boot).Inc()
+}
+
+// UpdateRevisionFailures will update revision failure metrics for each revision failed to become Active
+func UpdateRevisionFailures(kind string, boot string) {
+	RevisionFailures.WithLabelValues(kind, boot).Inc()
+}
+
 // UpdateMainStageErrors will update reconcile error metrics only for main stage
 func UpdateMainStageErrors(kind string, stage string, boot string) {
 	ReconcileErrors.WithLabelValues(kind, stage, "", boot).Inc()
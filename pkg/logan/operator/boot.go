@@ -34,6 +34,37 @@ func WorkloadName(boot *appv1.Boot) string {
 	//return boot.Name + "-" + boot.BootType
 }
 
+// CanaryWorkloadName return name for the created canary Deploy
+func CanaryWorkloadName(boot *appv1.Boot) string {
+	return WorkloadName(boot) + "-" + keys.TrackCanary
+}
+
+// GreenWorkloadName return name for the created green Deploy, the blue one is the Deploy of WorkloadName
+func GreenWorkloadName(boot *appv1.Boot) string {
+	return WorkloadName(boot) + "-" + keys.ColorGreen
+}
+
+// IngressName return name for the created Ingress or Route
+func IngressName(boot *appv1.Boot) string {
+	return boot.Name
+}
+
+// IngressHost return the host of the boot's Ingress or Route, built from the subDomain and the domainTemplate.
+// If the domainTemplate has no ${SUBDOMAIN}, the subDomain is prepended to it.
+func IngressHost(boot *appv1.Boot, appSpec *config.AppSpec) string {
+	template := appSpec.Settings.DomainTemplate
+	if boot.Spec.SubDomain == "" || template == "" {
+		return ""
+	}
+
+	if !strings.Contains(template, "${SUBDOMAIN}") {
+		template = "${SUBDOMAIN}." + strings.TrimPrefix(template, ".")
+	}
+	host := strings.ReplaceAll(template, "${SUBDOMAIN}", boot.Spec.SubDomain)
+	host, _ = Decode(boot, host)
+	return strings.ToLower(host)
+}
+
 // AppContainerHealthPort return the health port for the created Pod's app container
 func AppContainerHealthPort(boot *appv1.Boot, appSpec *config.AppSpec) intstr.IntOrString {
 	healthPort := int32(boot.Spec.Port)
@@ -43,14 +74,138 @@ func AppContainerHealthPort(boot *appv1.Boot, appSpec *config.AppSpec) intstr.In
 	return intstr.IntOrString{Type: intstr.Int, IntVal: int32(healthPort)}
 }
 
-// AppContainerImageName return image name for the created Pod's app container
+// AppContainerImageName return image name for the created Pod's app container, pinned to the boot's image digest if recorded
 func AppContainerImageName(boot *appv1.Boot, appSpec *config.AppSpec) string {
+	if boot.Spec.ImageDigest != "" {
+		return AppContainerImageRepository(boot, appSpec) + "@" + boot.Spec.ImageDigest
+	}
+
+	return AppContainerImageRepository(boot, appSpec) + ":" + boot.Spec.Version
+}
+
+// AppContainerImageRepository return the image of the app container without the tag or digest
+func AppContainerImageRepository(boot *appv1.Boot, appSpec *config.AppSpec) string {
 	registry := appSpec.Settings.Registry
 	if registry != "" {
-		return registry + "/" + boot.Spec.Image + ":" + boot.Spec.Version
+		return registry + "/" + boot.Spec.Image
+	}
+
+	return boot.Spec.Image
+}
+
+// ImageDigestEnabled return whether the config's settings enable the digest mode
+func ImageDigestEnabled(appSpec *config.AppSpec) bool {
+	return appSpec.Settings != nil && appSpec.Settings.ImageDigest != nil && *appSpec.Settings.ImageDigest
+}
+
+// AppContainerImagePullPolicy return the imagePullPolicy for the created Pod's app container,
+// fallback to the config's settings and `Always`
+func AppContainerImagePullPolicy(boot *appv1.Boot, appSpec *config.AppSpec) corev1.PullPolicy {
+	if boot.Spec.ImagePullPolicy != "" {
+		return boot.Spec.ImagePullPolicy
+	}
+	if appSpec.Settings.ImagePullPolicy != "" {
+		return appSpec.Settings.ImagePullPolicy
 	}
+	return defaultImagePullPolicy
+}
+
+// PodImagePullSecrets return the imagePullSecrets for the created Pod: the secrets of the config's podSpec,
+// and the boot's secrets, fallback to the config's settings.
+func PodImagePullSecrets(boot *appv1.Boot, appSpec *config.AppSpec, cfgSecrets []corev1.LocalObjectReference) []corev1.LocalObjectReference {
+	secrets := boot.Spec.ImagePullSecrets
+	if len(secrets) == 0 {
+		secrets = appSpec.Settings.ImagePullSecrets
+	}
+
+	var ret []corev1.LocalObjectReference
+	names := make(map[string]bool)
+	for _, secretList := range [][]corev1.LocalObjectReference{cfgSecrets, secrets} {
+		for _, secret := range secretList {
+			if names[secret.Name] {
+				continue
+			}
+			names[secret.Name] = true
+			ret = append(ret, secret)
+		}
+	}
+	return ret
+}
+
+// portProtocol return the protocol of the port, default is TCP
+func portProtocol(port appv1.BootPort) corev1.Protocol {
+	if port.Protocol == "" {
+		return corev1.ProtocolTCP
+	}
+	return port.Protocol
+}
+
+// portExposed return whether the port is exposed by the app Service, default is true
+func portExposed(port appv1.BootPort) bool {
+	return port.Expose == nil || *port.Expose
+}
+
+// ContainerPorts return the ports for the created Pod's app container, the primary Port is named HttpPortName
+func ContainerPorts(boot *appv1.Boot) []corev1.ContainerPort {
+	ports := []corev1.ContainerPort{{
+		Name:          HttpPortName,
+		ContainerPort: boot.Spec.Port,
+		Protocol:      corev1.ProtocolTCP,
+	}}
+
+	for _, port := range boot.Spec.Ports {
+		ports = append(ports, corev1.ContainerPort{
+			Name:          port.Name,
+			ContainerPort: port.ContainerPort,
+			Protocol:      portProtocol(port),
+		})
+	}
+	return ports
+}
+
+// AppServicePorts return the ports for the created app Service, include the primary Port and the exposed ports
+func AppServicePorts(boot *appv1.Boot) []corev1.ServicePort {
+	ports := []corev1.ServicePort{{
+		Name:       HttpPortName,
+		Protocol:   corev1.ProtocolTCP,
+		Port:       boot.Spec.Port,
+		TargetPort: intstr.IntOrString{Type: intstr.Int, IntVal: boot.Spec.Port},
+	}}
+
+	for _, port := range boot.Spec.Ports {
+		if !portExposed(port) {
+			continue
+		}
+		ports = append(ports, corev1.ServicePort{
+			Name:       port.Name,
+			Protocol:   portProtocol(port),
+			Port:       port.ContainerPort,
+			TargetPort: intstr.IntOrString{Type: intstr.Int, IntVal: port.ContainerPort},
+		})
+	}
+	return ports
+}
+
+// MetricsPort return the port for prometheus to scrape, the primary Port if metricsPort is not found
+func MetricsPort(boot *appv1.Boot) int32 {
+	for _, port := range boot.Spec.Ports {
+		if port.Name == boot.Spec.MetricsPort {
+			return port.ContainerPort
+		}
+	}
+	return boot.Spec.Port
+}
 
-	return boot.Spec.Image + ":" + boot.Spec.Version
+// AppProtocols return the application protocols of the exposed ports, in the format of `name=appProtocol`
+// split by , empty if no exposed port has appProtocol
+func AppProtocols(boot *appv1.Boot) string {
+	var protocols []string
+	for _, port := range boot.Spec.Ports {
+		if port.AppProtocol != "" && portExposed(port) {
+			protocols = append(protocols, port.Name+"="+port.AppProtocol)
+		}
+	}
+	return strings.Join(protocols, ",")
 }
 
 // PodLabels return labels for the created Pod
@@ -59,6 +214,27 @@ func PodLabels(boot *appv1.Boot) map[string]string {
 	return map[string]string{"app": "havok", keys.BootNameKey: boot.Name, keys.BootTypeKey: boot.BootType}
 }
 
+// CanaryPodLabels return labels for the canary Deploy's pods, the app Service selects them by the PodLabels
+func CanaryPodLabels(boot *appv1.Boot) map[string]string {
+	labels := PodLabels(boot)
+	labels[keys.TrackKey] = keys.TrackCanary
+	return labels
+}
+
+// StablePodLabels return labels for the stable Deploy's pods while rolled out by canary
+func StablePodLabels(boot *appv1.Boot) map[string]string {
+	labels := PodLabels(boot)
+	labels[keys.TrackKey] = keys.TrackStable
+	return labels
+}
+
+// ColorPodLabels return labels for the blue/green Deploy's pods
+func ColorPodLabels(boot *appv1.Boot, color string) map[string]string {
+	labels := PodLabels(boot)
+	labels[keys.ColorKey] = color
+	return labels
+}
+
 // SideCarServiceName return the name for sidecar service
 func SideCarServiceName(boot *appv1.Boot, port corev1.ContainerPort) string {
 	return boot.Name + "-" + port.Name
@@ -82,6 +258,18 @@ func allowPrometheusScrape(boot *appv1.Boot, appSpec *config.AppSpec) bool {
 	return *appSpec.Settings.PrometheusScrape
 }
 
+// AppServiceAnnotation return the annotations for the created app Service, the metrics port is scraped
+func AppServiceAnnotation(boot *appv1.Boot, prometheusScrape bool) map[string]string {
+	annotations := ServiceAnnotation(prometheusScrape, int(MetricsPort(boot)))
+	if appProtocols := AppProtocols(boot); appProtocols != "" {
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[keys.AppProtocolsAnnotationKey] = appProtocols
+	}
+	return annotations
+}
+
 // ServiceAnnotation return the annotations for the created Service
 func ServiceAnnotation(prometheusScrape bool, port int) map[string]string {
 	if prometheusScrape == true {
@@ -106,7 +294,7 @@ func TransferServiceNames(services []corev1.Service) string {
 }
 
 // Decode will decode the origin string, with the fields of Boot
-// Currently key only supports ${APP} and ${ENV} and ${PORT}
+// Currently key only supports ${APP} and ${ENV} and ${PORT}, ${PORT} is always the primary Port
 func Decode(boot *appv1.Boot, origin string) (string, bool) {
 	ret := origin
 	replaced := false
@@ -373,6 +561,82 @@ func ConvertVolume(pvcs []appv1.PersistentVolumeClaimMount) []corev1.Volume {
 	return vols
 }
 
+// ConvertBootVolumeMount Convert the BootVolume to VolumeMount
+func ConvertBootVolumeMount(bootVols []appv1.BootVolume) []corev1.VolumeMount {
+	if len(bootVols) == 0 {
+		return nil
+	}
+	vols := make([]corev1.VolumeMount, 0)
+	for _, bootVol := range bootVols {
+		vol := corev1.VolumeMount{
+			Name:      bootVol.Name,
+			ReadOnly:  bootVol.ReadOnly,
+			MountPath: bootVol.MountPath,
+			SubPath:   bootVol.SubPath,
+		}
+		vols = append(vols, vol)
+	}
+	return vols
+}
+
+// ConvertBootVolume Convert the BootVolume to Volume
+func ConvertBootVolume(bootVols []appv1.BootVolume) []corev1.Volume {
+	if len(bootVols) == 0 {
+		return nil
+	}
+	vols := make([]corev1.Volume, 0)
+	for _, bootVol := range bootVols {
+		vol := corev1.Volume{
+			Name: bootVol.Name,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: bootVol.ConfigMap.DeepCopy(),
+				Secret:    bootVol.Secret.DeepCopy(),
+				EmptyDir:  bootVol.EmptyDir.DeepCopy(),
+				Projected: bootVol.Projected.DeepCopy(),
+			},
+		}
+		defaultVolumeSource(&vol.VolumeSource)
+		vols = append(vols, vol)
+	}
+	return vols
+}
+
+// defaultVolumeSource set the defaultMode as the apiserver does, so that the volumes can be compared with the workload's
+func defaultVolumeSource(source *corev1.VolumeSource) {
+	if source.ConfigMap != nil && source.ConfigMap.DefaultMode == nil {
+		mode := corev1.ConfigMapVolumeSourceDefaultMode
+		source.ConfigMap.DefaultMode = &mode
+	}
+	if source.Secret != nil && source.Secret.DefaultMode == nil {
+		mode := corev1.SecretVolumeSourceDefaultMode
+		source.Secret.DefaultMode = &mode
+	}
+	if source.Projected != nil && source.Projected.DefaultMode == nil {
+		mode := corev1.ProjectedVolumeSourceDefaultMode
+		source.Projected.DefaultMode = &mode
+	}
+}
+
+// BootVolumesEq return true if the boot's volumes are equal to the volumes with the same names in vols.
+func BootVolumesEq(vols []corev1.Volume, bootVols []corev1.Volume) bool {
+	for _, bootVol := range bootVols {
+		found := false
+		for _, vol := range vols {
+			if vol.Name == bootVol.Name {
+				found = true
+				if !reflect.DeepEqual(vol.VolumeSource, bootVol.VolumeSource) {
+					return false
+				}
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 // GetProfileBootConfig gets the Boot's config by profile annotation
 func GetProfileBootConfig(boot *appv1.Boot, logger logr.Logger) (*config.BootConfig, error) {
 	if boot.Annotations != nil {
@@ -21,10 +21,27 @@ import (
 
 const (
 	defaultAppName               = "app"
-	defaultImagePullPolicy       = "Always"
+	defaultImagePullPolicy       = corev1.PullAlways
 	defaultRevisionHistoryLimits = int(5)
 	defaultWeight                = 100
 
+	defaultLivenessInitialDelay        = 120
+	defaultReadinessInitialDelay       = 60
+	defaultProbeFailureThreshold       = 10
+	defaultPythonProbeFailureThreshold = 15
+	defaultProbePeriodSeconds          = 10
+	defaultProbeSuccessThreshold       = 1
+	defaultProbeTimeoutSeconds         = 5
+	defaultGrpcHealthProbe             = "/bin/grpc_health_probe"
+
+	defaultTerminationGracePeriodSeconds = 30
+	defaultShell                         = "/bin/sh"
+
+	defaultMaxSurge                = "25%"
+	defaultMaxUnavailable          = "25%"
+	defaultProgressDeadlineSeconds = 600
+	defaultScaleDownDelaySeconds   = 30
+
 	eventTypeNormal  = "Normal"
 	eventTypeWarning = "Warning"
 )
@@ -43,6 +60,9 @@ type BootHandler struct {
 	Client   util.K8SClient
 	Logger   logr.Logger
 	Recorder record.EventRecorder
+
+	// cachedConfigHash is the hash of the referenced Secrets and ConfigMaps, computed once per reconcile
+	cachedConfigHash *string
 }
 
 // UpdateAnnotation handle the logic for annotation value, return true if updated
@@ -85,6 +105,7 @@ func (handler *BootHandler) NewContainers() []corev1.Container {
 			sideCarContainer := c.DeepCopy()
 			// Replace Envs
 			DecodeEnvs(boot, sideCarContainer.Env)
+			handler.applyContainerSecurityPolicy(sideCarContainer)
 
 			containers = append(containers, *sideCarContainer)
 		}
@@ -102,10 +123,19 @@ func (handler *BootHandler) NewPodAnnotations() map[string]string {
 			annotations[keys.BootRestartedAtAnnotationKey] = restartAnnotationValue
 		}
 	}
+
+	// the hash of the referenced Secrets and ConfigMaps, the pods will be restarted when it is changed.
+	configHash, err := handler.configHash()
+	if err != nil {
+		handler.Logger.Error(err, "Failed to calculate the config hash")
+	} else if configHash != "" {
+		annotations[keys.BootConfigHashAnnotationKey] = configHash
+	}
 	return annotations
 }
 
-// NewAffinity return the pod's Affinity
+// NewAffinity return the pod's Affinity: the preferred hostname anti-affinity, the zone anti-affinity,
+// the topology spread constraints and the boot's node affinity.
 func (handler *BootHandler) NewAffinity() *corev1.Affinity {
 	boot := handler.Boot
 
@@ -113,23 +143,30 @@ func (handler *BootHandler) NewAffinity() *corev1.Affinity {
 		PodAntiAffinity: &corev1.PodAntiAffinity{
 			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
 				{
-					Weight: defaultWeight,
-					PodAffinityTerm: corev1.PodAffinityTerm{
-						LabelSelector: &metav1.LabelSelector{
-							MatchExpressions: []metav1.LabelSelectorRequirement{
-								{
-									Key:      keys.BootNameKey,
-									Operator: metav1.LabelSelectorOpIn,
-									Values:   []string{boot.Name},
-								},
-							},
-						},
-						TopologyKey: "kubernetes.io/hostname",
-					},
+					Weight:          defaultWeight,
+					PodAffinityTerm: podAntiAffinityTerm(boot, hostnameTopologyKey),
 				},
 			},
 		}}
 
+	// The spread constraints are implemented by pod anti-affinity
+	required, preferred := topologySpreadKeys(boot)
+	for _, key := range required {
+		affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+			affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			podAntiAffinityTerm(boot, key))
+	}
+	for _, key := range preferred {
+		affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			corev1.WeightedPodAffinityTerm{
+				Weight:          defaultWeight,
+				PodAffinityTerm: podAntiAffinityTerm(boot, key),
+			})
+	}
+
+	affinity.NodeAffinity = handler.podNodeAffinity()
+
 	return affinity
 }
 
@@ -149,12 +186,20 @@ func (handler *BootHandler) rebuildPodSpec(podTemplateSpec *corev1.PodTemplateSp
 
 		initContainers := podTemplateSpec.Spec.InitContainers
 		if initContainers != nil && len(initContainers) > 0 {
-			for _, c := range initContainers {
+			for i, c := range initContainers {
 				DecodeEnvs(boot, c.Env)
+				handler.applyContainerSecurityPolicy(&initContainers[i])
 			}
 		}
 	}
 
+	// imagePullSecrets: the config's and the boot's
+	podTemplateSpec.Spec.ImagePullSecrets = PodImagePullSecrets(boot, bootCfg.AppSpec,
+		podTemplateSpec.Spec.ImagePullSecrets)
+
+	// securityContext: the config's, overridden by the boot's, then defaulted by the security policy
+	podTemplateSpec.Spec.SecurityContext = handler.NewPodSecurityContext(podTemplateSpec.Spec.SecurityContext)
+
 	//add app pvc
 	if boot.Spec.Pvc != nil && len(boot.Spec.Pvc) > 0 {
 		vols := ConvertVolume(boot.Spec.Pvc)
@@ -166,6 +211,18 @@ func (handler *BootHandler) rebuildPodSpec(podTemplateSpec *corev1.PodTemplateSp
 		}
 	}
 
+	//add app volumes
+	if len(boot.Spec.Volumes) > 0 {
+		podTemplateSpec.Spec.Volumes = append(podTemplateSpec.Spec.Volumes, ConvertBootVolume(boot.Spec.Volumes)...)
+	}
+
+	// the boot's termination grace period overrides the config's
+	shutdown := handler.bootShutdown()
+	if shutdown != nil && shutdown.TerminationGracePeriodSeconds != nil {
+		terminationGracePeriod := *shutdown.TerminationGracePeriodSeconds
+		podTemplateSpec.Spec.TerminationGracePeriodSeconds = &terminationGracePeriod
+	}
+
 	// decode
 	volumes := podTemplateSpec.Spec.Volumes
 	if volumes != nil && len(volumes) > 0 {
@@ -208,10 +265,13 @@ func (handler *BootHandler) NewStatefulSet() *appsv1.StatefulSet {
 					Annotations: annotations,
 				},
 				Spec: corev1.PodSpec{
-					Affinity:          affinity,
-					Containers:        containers,
-					NodeSelector:      boot.Spec.NodeSelector,
-					PriorityClassName: boot.Spec.Priority,
+					Affinity:                     affinity,
+					Containers:                   containers,
+					NodeSelector:                 handler.podNodeSelector(),
+					Tolerations:                  handler.podTolerations(),
+					PriorityClassName:            handler.podPriority(),
+					ServiceAccountName:           ServiceAccountName(boot),
+					AutomountServiceAccountToken: AutomountServiceAccountToken(boot),
 				},
 			},
 			ServiceName:         boot.Name,
@@ -219,15 +279,12 @@ func (handler *BootHandler) NewStatefulSet() *appsv1.StatefulSet {
 		},
 	}
 
-	partition := int32(0)
-	sts.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
-		Type: appsv1.RollingUpdateStatefulSetStrategyType,
-		RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
-			Partition: &partition,
-		},
-	}
+	sts.Spec.UpdateStrategy = handler.NewStatefulSetUpdateStrategy()
 
 	handler.rebuildPodSpec(&sts.Spec.Template)
+	applyMetadata(&sts.ObjectMeta, boot.Spec.WorkloadMetadata)
+	applyMetadata(&sts.Spec.Template.ObjectMeta, boot.Spec.PodMetadata)
+	handler.applyOverride(sts, handler.workloadOverride(), handler.overridePolicy().Workload)
 
 	_ = controllerutil.SetControllerReference(handler.OperatorBoot, sts, handler.Scheme)
 
@@ -242,6 +299,14 @@ func (handler *BootHandler) NewDeployment() *appsv1.Deployment {
 	podLabels := PodLabels(boot)
 	deployLabels := WorkloadLabels(boot)
 
+	// the Deployment's selector can not be changed, only its pods are labeled with the blue color or stable track
+	templateLabels := podLabels
+	if handler.blueGreenEnabled() {
+		templateLabels = ColorPodLabels(boot, keys.ColorBlue)
+	} else if handler.canaryEnabled() {
+		templateLabels = StablePodLabels(boot)
+	}
+
 	containers := handler.NewContainers()
 	annotations := handler.NewPodAnnotations()
 
@@ -265,36 +330,110 @@ func (handler *BootHandler) NewDeployment() *appsv1.Deployment {
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels:      podLabels,
+					Labels:      templateLabels,
 					Annotations: annotations,
 				},
 				Spec: corev1.PodSpec{
-					Affinity:          affinity,
-					Containers:        containers,
-					NodeSelector:      boot.Spec.NodeSelector,
-					PriorityClassName: boot.Spec.Priority,
+					Affinity:                     affinity,
+					Containers:                   containers,
+					NodeSelector:                 handler.podNodeSelector(),
+					Tolerations:                  handler.podTolerations(),
+					PriorityClassName:            handler.podPriority(),
+					ServiceAccountName:           ServiceAccountName(boot),
+					AutomountServiceAccountToken: AutomountServiceAccountToken(boot),
 				},
 			},
-			Strategy: appsv1.DeploymentStrategy{},
 		},
 	}
 
-	// Avoid when boot has more than 4 pods, more than one pod will be RollingUpdate.
-	if boot.BootType == logan.BootJava {
-		maxUnavailable := intstr.FromString("1%")
-		dep.Spec.Strategy = appsv1.DeploymentStrategy{
+	dep.Spec.Strategy, dep.Spec.MinReadySeconds, dep.Spec.ProgressDeadlineSeconds = handler.NewDeploymentStrategy()
+
+	handler.rebuildPodSpec(&dep.Spec.Template)
+	applyMetadata(&dep.ObjectMeta, boot.Spec.WorkloadMetadata)
+	applyMetadata(&dep.Spec.Template.ObjectMeta, boot.Spec.PodMetadata)
+	handler.applyOverride(dep, handler.workloadOverride(), handler.overridePolicy().Workload)
+
+	_ = controllerutil.SetControllerReference(handler.OperatorBoot, dep, handler.Scheme)
+
+	return dep
+}
+
+// bootStrategy return the boot's strategy, fallback to the config's
+func (handler *BootHandler) bootStrategy() *appv1.BootStrategy {
+	if handler.Boot.Spec.Strategy != nil {
+		return handler.Boot.Spec.Strategy
+	}
+	return handler.Config.AppSpec.Strategy
+}
+
+// NewDeploymentStrategy return the Deployment's strategy, minReadySeconds and progressDeadlineSeconds.
+// The unspecified fields are set to the kubernetes' default value, so that they can be compared with the
+// Deployment's spec stored in the cluster.
+func (handler *BootHandler) NewDeploymentStrategy() (appsv1.DeploymentStrategy, int32, *int32) {
+	boot := handler.Boot
+	strategy := handler.bootStrategy()
+
+	maxSurge := intstr.FromString(defaultMaxSurge)
+	maxUnavailable := intstr.FromString(defaultMaxUnavailable)
+	progressDeadlineSeconds := int32(defaultProgressDeadlineSeconds)
+	minReadySeconds := int32(0)
+
+	if strategy == nil {
+		// Avoid when boot has more than 4 pods, more than one pod will be RollingUpdate.
+		if boot.BootType == logan.BootJava {
+			maxUnavailable = intstr.FromString("1%")
+		}
+
+		return appsv1.DeploymentStrategy{
 			Type: appsv1.RollingUpdateDeploymentStrategyType,
 			RollingUpdate: &appsv1.RollingUpdateDeployment{
+				MaxSurge:       &maxSurge,
 				MaxUnavailable: &maxUnavailable,
 			},
-		}
+		}, minReadySeconds, &progressDeadlineSeconds
 	}
 
-	handler.rebuildPodSpec(&dep.Spec.Template)
+	if strategy.ProgressDeadlineSeconds != nil {
+		progressDeadlineSeconds = *strategy.ProgressDeadlineSeconds
+	}
+	minReadySeconds = strategy.MinReadySeconds
 
-	_ = controllerutil.SetControllerReference(handler.OperatorBoot, dep, handler.Scheme)
+	if strategy.Type == appv1.RecreateStrategy {
+		return appsv1.DeploymentStrategy{
+			Type: appsv1.RecreateDeploymentStrategyType,
+		}, minReadySeconds, &progressDeadlineSeconds
+	}
 
-	return dep
+	if strategy.MaxSurge != nil {
+		maxSurge = *strategy.MaxSurge
+	}
+	if strategy.MaxUnavailable != nil {
+		maxUnavailable = *strategy.MaxUnavailable
+	}
+
+	return appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{
+			MaxSurge:       &maxSurge,
+			MaxUnavailable: &maxUnavailable,
+		},
+	}, minReadySeconds, &progressDeadlineSeconds
+}
+
+// NewStatefulSetUpdateStrategy return the StatefulSet's update strategy
+func (handler *BootHandler) NewStatefulSetUpdateStrategy() appsv1.StatefulSetUpdateStrategy {
+	partition := int32(0)
+	strategy := handler.bootStrategy()
+	if strategy != nil && strategy.Partition != nil {
+		partition = *strategy.Partition
+	}
+
+	return appsv1.StatefulSetUpdateStrategy{
+		Type: appsv1.RollingUpdateStatefulSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+			Partition: &partition,
+		},
+	}
 }
 
 // NewAppContainer return a new created App Container instance
@@ -303,28 +442,32 @@ func (handler *BootHandler) NewAppContainer() *corev1.Container {
 	imageName := AppContainerImageName(boot, handler.Config.AppSpec)
 
 	appContainer := corev1.Container{
-		Image: imageName,
-		Name:  defaultAppName,
-		Ports: []corev1.ContainerPort{{
-			ContainerPort: boot.Spec.Port,
-			Name:          HttpPortName,
-		}},
+		Image:           imageName,
+		Name:            defaultAppName,
+		Ports:           ContainerPorts(boot),
 		Env:             boot.Spec.Env,
-		ImagePullPolicy: defaultImagePullPolicy,
+		EnvFrom:         boot.Spec.EnvFrom,
+		ImagePullPolicy: AppContainerImagePullPolicy(boot, handler.Config.AppSpec),
 		Resources:       boot.Spec.Resources,
 	}
 
-	// If Spec's health is empty string, disable the health check and readiness.
-	if boot.Spec.Health != nil && *boot.Spec.Health != "" {
-		liveness, readiness := handler.GetHealthProbe()
-		appContainer.LivenessProbe = liveness
-		appContainer.ReadinessProbe = readiness
-	}
+	// If Spec's health is empty string and no probes specified, disable the health check and readiness.
+	liveness, readiness := handler.GetHealthProbe()
+	appContainer.LivenessProbe = liveness
+	appContainer.ReadinessProbe = readiness
 
 	if boot.Spec.Command != nil && len(boot.Spec.Command) > 0 {
 		appContainer.Command = boot.Spec.Command
 	}
 
+	if len(boot.Spec.Args) > 0 {
+		appContainer.Args = boot.Spec.Args
+	}
+
+	if boot.Spec.WorkingDir != "" {
+		appContainer.WorkingDir = boot.Spec.WorkingDir
+	}
+
 	specContainer := handler.Config.AppSpec.Container
 	if specContainer != nil {
 		err := util.MergeOverride(&appContainer, *specContainer)
@@ -333,6 +476,19 @@ func (handler *BootHandler) NewAppContainer() *corev1.Container {
 		}
 	}
 
+	// securityContext: the boot's, then defaulted by the security policy
+	handler.applyAppSecurityContext(&appContainer)
+
+	// the boot's shutdown overrides the preStop hook of the config's container
+	preStop := handler.NewPreStopHandler()
+	if preStop != nil {
+		lifecycle := &corev1.Lifecycle{PreStop: preStop}
+		if appContainer.Lifecycle != nil {
+			lifecycle.PostStart = appContainer.Lifecycle.PostStart
+		}
+		appContainer.Lifecycle = lifecycle
+	}
+
 	// add pvc
 	if boot.Spec.Pvc != nil && len(boot.Spec.Pvc) > 0 {
 		if appContainer.VolumeMounts == nil {
@@ -345,56 +501,137 @@ func (handler *BootHandler) NewAppContainer() *corev1.Container {
 		}
 	}
 
+	// add volumes
+	if len(boot.Spec.Volumes) > 0 {
+		appContainer.VolumeMounts = append(appContainer.VolumeMounts, ConvertBootVolumeMount(boot.Spec.Volumes)...)
+	}
+
 	return &appContainer
 }
 
-// GetHealthProbe return the livenessProbe and readinessProbe for the created container
+// GetHealthProbe return the livenessProbe and readinessProbe for the created container.
+// The structured spec.probes take precedence over the Health and Readiness shorthand.
 func (handler *BootHandler) GetHealthProbe() (*corev1.Probe, *corev1.Probe) {
 	boot := handler.Boot
-	healthPort := AppContainerHealthPort(boot, handler.Config.AppSpec)
 
-	// havok issue #95
-	failureThreshold := int32(10)
-	if boot.BootType == logan.BootPython {
-		failureThreshold = int32(15)
+	healthPath := ""
+	if boot.Spec.Health != nil {
+		healthPath = *boot.Spec.Health
 	}
 
-	livenessProbe := &corev1.Probe{
-		FailureThreshold: failureThreshold,
-		Handler: corev1.Handler{
-			HTTPGet: &corev1.HTTPGetAction{
-				Path:   *boot.Spec.Health,
-				Port:   healthPort,
-				Scheme: corev1.URISchemeHTTP,
-			},
-		},
-		InitialDelaySeconds: 120,
-		PeriodSeconds:       10,
-		SuccessThreshold:    1,
-		TimeoutSeconds:      5,
+	// if boot.Spec.Health is empty, ignore the Readiness
+	readinessPath := healthPath
+	if healthPath != "" && boot.Spec.Readiness != nil && *boot.Spec.Readiness != "" {
+		readinessPath = *boot.Spec.Readiness
 	}
 
-	readinessPath := *boot.Spec.Health
-	if boot.Spec.Readiness != nil && *boot.Spec.Readiness != "" {
-		readinessPath = *boot.Spec.Readiness
+	var livenessSpec, readinessSpec, startupSpec *appv1.BootProbe
+	if boot.Spec.Probes != nil {
+		livenessSpec = boot.Spec.Probes.Liveness
+		readinessSpec = boot.Spec.Probes.Readiness
+		startupSpec = boot.Spec.Probes.Startup
+	}
+
+	var livenessProbe, readinessProbe *corev1.Probe
+	if livenessSpec != nil {
+		livenessProbe = handler.newProbe(livenessSpec, healthPath, defaultLivenessInitialDelay)
+	} else if healthPath != "" {
+		livenessProbe = handler.newProbe(&appv1.BootProbe{}, healthPath, defaultLivenessInitialDelay)
 	}
 
-	readinessProbe := &corev1.Probe{
-		FailureThreshold: failureThreshold,
-		Handler: corev1.Handler{
+	if readinessSpec != nil {
+		readinessProbe = handler.newProbe(readinessSpec, readinessPath, defaultReadinessInitialDelay)
+	} else if readinessPath != "" {
+		readinessProbe = handler.newProbe(&appv1.BootProbe{}, readinessPath, defaultReadinessInitialDelay)
+	}
+
+	// startupProbe is not supported, the startup only delays the liveness probe until its budget is used up.
+	if startupSpec != nil && livenessProbe != nil {
+		startup := handler.newProbe(startupSpec, healthPath, 0)
+		budget := startup.InitialDelaySeconds + startup.PeriodSeconds*startup.FailureThreshold
+		if livenessProbe.InitialDelaySeconds < budget {
+			livenessProbe.InitialDelaySeconds = budget
+		}
+	}
+
+	return livenessProbe, readinessProbe
+}
+
+// newProbe return a probe from the boot's probe spec, path and initialDelay are used if not specified
+func (handler *BootHandler) newProbe(probeSpec *appv1.BootProbe, path string, initialDelay int32) *corev1.Probe {
+	boot := handler.Boot
+
+	port := AppContainerHealthPort(boot, handler.Config.AppSpec)
+	if probeSpec.Port != nil {
+		port = intstr.FromInt(int(*probeSpec.Port))
+	}
+
+	// havok issue #95
+	failureThreshold := int32(defaultProbeFailureThreshold)
+	if boot.BootType == logan.BootPython {
+		failureThreshold = int32(defaultPythonProbeFailureThreshold)
+	}
+
+	probe := &corev1.Probe{
+		FailureThreshold:    int32Value(probeSpec.FailureThreshold, failureThreshold),
+		InitialDelaySeconds: int32Value(probeSpec.InitialDelaySeconds, initialDelay),
+		PeriodSeconds:       int32Value(probeSpec.PeriodSeconds, defaultProbePeriodSeconds),
+		SuccessThreshold:    int32Value(probeSpec.SuccessThreshold, defaultProbeSuccessThreshold),
+		TimeoutSeconds:      int32Value(probeSpec.TimeoutSeconds, defaultProbeTimeoutSeconds),
+	}
+
+	switch probeSpec.Type {
+	case appv1.ProbeTCP:
+		probe.Handler = corev1.Handler{
+			TCPSocket: &corev1.TCPSocketAction{
+				Port: port,
+			},
+		}
+	case appv1.ProbeExec:
+		probe.Handler = corev1.Handler{
+			Exec: &corev1.ExecAction{
+				Command: probeSpec.Command,
+			},
+		}
+	case appv1.ProbeGRPC:
+		command := []string{defaultGrpcHealthProbe, "-addr=:" + port.String()}
+		if probeSpec.Service != "" {
+			command = append(command, "-service="+probeSpec.Service)
+		}
+		probe.Handler = corev1.Handler{
+			Exec: &corev1.ExecAction{
+				Command: command,
+			},
+		}
+	default:
+		if probeSpec.Path != "" {
+			path = probeSpec.Path
+		}
+		if path == "" {
+			path = "/"
+		}
+		scheme := probeSpec.Scheme
+		if scheme == "" {
+			scheme = corev1.URISchemeHTTP
+		}
+		probe.Handler = corev1.Handler{
 			HTTPGet: &corev1.HTTPGetAction{
-				Path:   readinessPath,
-				Port:   healthPort,
-				Scheme: corev1.URISchemeHTTP,
+				Path:   path,
+				Port:   port,
+				Scheme: scheme,
 			},
-		},
-		InitialDelaySeconds: 60,
-		PeriodSeconds:       10,
-		SuccessThreshold:    1,
-		TimeoutSeconds:      5,
+		}
 	}
 
-	return livenessProbe, readinessProbe
+	return probe
+}
+
+// int32Value return the value of the pointer, or the default value if nil
+func int32Value(v *int32, defaultValue int32) int32 {
+	if v == nil {
+		return defaultValue
+	}
+	return *v
 }
 
 // NewServices returns a new created Service instance
@@ -404,6 +641,8 @@ func (handler *BootHandler) NewServices(podSpec *corev1.PodTemplateSpec) []*core
 	// app Service
 	prometheusScrape := allowPrometheusScrape(boot, handler.Config.AppSpec)
 	bootSvc := handler.createService(int(boot.Spec.Port), boot.Name, prometheusScrape, corev1.ServiceTypeClusterIP)
+	bootSvc.Spec.Ports = AppServicePorts(boot)
+	bootSvc.Annotations = AppServiceAnnotation(boot, prometheusScrape)
 	allSvcs := []*corev1.Service{bootSvc}
 
 	// only dev environment and nodePort true, create nodePort service
@@ -425,6 +664,11 @@ func (handler *BootHandler) NewServices(podSpec *corev1.PodTemplateSpec) []*core
 		}
 	}
 
+	for _, svc := range allSvcs {
+		applyMetadata(&svc.ObjectMeta, boot.Spec.ServiceMetadata)
+	}
+	handler.applyOverride(bootSvc, handler.serviceOverride(), handler.overridePolicy().Service)
+
 	return allSvcs
 }
 
@@ -0,0 +1,473 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	loganMetrics "github.com/logancloud/logan-app-operator/pkg/logan/metrics"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"strconv"
+	"time"
+)
+
+// blueGreenEnabled return whether the boot's new version is rolled out by blue/green
+func (handler *BootHandler) blueGreenEnabled() bool {
+	boot := handler.Boot
+	rollout := boot.Spec.Rollout
+	return rollout != nil && rollout.Mode == appv1.BlueGreenRollout &&
+		(boot.Spec.Workload == appv1.Deployment || boot.Spec.Workload == "")
+}
+
+// NewColorDeployment return a new created Boot's Deployment object of the color.
+// The blue Deployment is the Deployment of WorkloadName, its selector can not be changed, so only its pods are
+// labeled with the color.
+func (handler *BootHandler) NewColorDeployment(color string, replicas int32) *appsv1.Deployment {
+	boot := handler.Boot
+	podLabels := ColorPodLabels(boot, color)
+
+	dep := handler.NewDeployment()
+	dep.Spec.Replicas = &replicas
+	dep.Spec.Template.Labels = podLabels
+	applyMetadata(&dep.Spec.Template.ObjectMeta, boot.Spec.PodMetadata)
+	if color == keys.ColorGreen {
+		dep.Name = GreenWorkloadName(boot)
+		dep.Spec.Selector = &metav1.LabelSelector{
+			MatchLabels: podLabels,
+		}
+	}
+
+	return dep
+}
+
+// getAppService return the boot's app Service
+func (handler *BootHandler) getAppService() (*corev1.Service, error) {
+	boot := handler.Boot
+	svc := &corev1.Service{}
+	err := handler.Client.Get(context.TODO(), types.NamespacedName{Name: boot.Name, Namespace: boot.Namespace}, svc)
+	return svc, err
+}
+
+// serviceColor return the color selected by the Service, empty if the Service does not select by color
+func serviceColor(svc *corev1.Service) string {
+	if svc == nil || svc.Spec.Selector == nil {
+		return ""
+	}
+	return svc.Spec.Selector[keys.ColorKey]
+}
+
+// activeWorkloadName return the name of the Deployment selected by the app Service
+func (handler *BootHandler) activeWorkloadName() string {
+	boot := handler.Boot
+	if boot.Spec.Workload == appv1.StatefulSet {
+		return WorkloadName(boot)
+	}
+
+	svc, err := handler.getAppService()
+	if err == nil && serviceColor(svc) == keys.ColorGreen {
+		return GreenWorkloadName(boot)
+	}
+	return WorkloadName(boot)
+}
+
+// getGreenDeployment return the boot's green Deployment, nil if not found
+func (handler *BootHandler) getGreenDeployment() (*appsv1.Deployment, error) {
+	boot := handler.Boot
+	green := &appsv1.Deployment{}
+	err := handler.Client.Get(context.TODO(),
+		types.NamespacedName{Name: GreenWorkloadName(boot), Namespace: boot.Namespace}, green)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return green, nil
+}
+
+// blueGreenScaleDownAfter return the remaining time before the idle color is scaled down, 0 if it is time.
+func (handler *BootHandler) blueGreenScaleDownAfter(svc *corev1.Service) time.Duration {
+	delay := int32(defaultScaleDownDelaySeconds)
+	if rollout := handler.Boot.Spec.Rollout; rollout != nil && rollout.ScaleDownDelaySeconds != nil {
+		delay = *rollout.ScaleDownDelaySeconds
+	}
+
+	switchedAt, err := time.Parse(time.RFC3339, svc.Annotations[keys.BlueGreenSwitchedAtAnnotationKey])
+	if err != nil {
+		return 0
+	}
+
+	remaining := time.Duration(delay)*time.Second - time.Since(switchedAt)
+	if remaining <= 0 {
+		return 0
+	}
+	return remaining
+}
+
+// blueGreenRequeueAfter return when to check the scale down of the idle color again, 0 if no need.
+func (handler *BootHandler) blueGreenRequeueAfter() time.Duration {
+	if !handler.blueGreenEnabled() {
+		return 0
+	}
+	svc, err := handler.getAppService()
+	if err != nil {
+		return 0
+	}
+	return handler.blueGreenScaleDownAfter(svc)
+}
+
+// blueGreenInProgress return whether the active color does not run the boot's version yet
+func (handler *BootHandler) blueGreenInProgress() bool {
+	if !handler.blueGreenEnabled() {
+		return false
+	}
+
+	boot := handler.Boot
+	active := &appsv1.Deployment{}
+	err := handler.Client.Get(context.TODO(),
+		types.NamespacedName{Name: handler.activeWorkloadName(), Namespace: boot.Namespace}, active)
+	if err != nil {
+		return false
+	}
+
+	outdated, err := handler.workloadOutdated(&active.Spec.Template)
+	return err == nil && outdated
+}
+
+// deploymentHash return the hash of the boot's version the Deployment was rolled to, empty if not recorded
+func deploymentHash(dep *appsv1.Deployment) string {
+	return dep.Annotations[keys.BootRevisionHashAnnotationKey]
+}
+
+// setDeploymentHash will record the hash of the boot's version the Deployment is rolled to, the same as the hash of
+// the boot's revision
+func (handler *BootHandler) setDeploymentHash(dep *appsv1.Deployment) {
+	if dep.Annotations == nil {
+		dep.Annotations = make(map[string]string)
+	}
+	dep.Annotations[keys.BootRevisionHashAnnotationKey] = InitBootRevision(handler.Boot).BootHash()
+}
+
+// blueGreenRollback return whether switching from the active Deployment to the idle one is a rollback, that is the
+// idle's version is recorded in the revision history before the active's latest revision, and the id of the active's
+// latest revision. 0 if the revisions are not found.
+func blueGreenRollback(revisionLst *appv1.BootRevisionList, idle *appsv1.Deployment, active *appsv1.Deployment) (bool, int) {
+	idleHash := deploymentHash(idle)
+	activeHash := deploymentHash(active)
+	if idleHash == "" || activeHash == "" || idleHash == activeHash {
+		return false, 0
+	}
+
+	idleId, activeId := 0, 0
+	for i := range revisionLst.Items {
+		revision := &revisionLst.Items[i]
+		revisionId := revision.GetRevisionId()
+		switch revision.Annotations[keys.BootRevisionHashAnnotationKey] {
+		case idleHash:
+			if idleId == 0 || revisionId < idleId {
+				idleId = revisionId
+			}
+		case activeHash:
+			if revisionId > activeId {
+				activeId = revisionId
+			}
+		}
+	}
+	return idleId > 0 && idleId < activeId, activeId
+}
+
+// reconcileBlueGreenDeploy handle the blue/green of Deployment. The new version is rolled to the idle color with
+// all the replicas, the Services are switched to it when all its pods are ready, and the previous color is scaled
+// down after the delay. Switching to a version recorded in the revision history before the active one is a rollback.
+// A non-nil rolloutState is returned for the blue Deployment while blue/green is enabled.
+func (handler *BootHandler) reconcileBlueGreenDeploy(blue *appsv1.Deployment) (*rolloutState, reconcile.Result, bool, error) {
+	logger := handler.Logger
+	boot := handler.Boot
+	c := handler.Client
+
+	green, err := handler.getGreenDeployment()
+	if err != nil {
+		logger.Error(err, "Failed to get green Deployment")
+		loganMetrics.UpdateReconcileErrors(boot.Kind,
+			loganMetrics.RECONCILE_UPDATE_STAGE,
+			loganMetrics.RECONCILE_GET_COLOR_SUBSTAGE,
+			boot.Name)
+		return nil, reconcile.Result{Requeue: true}, true, err
+	}
+
+	appSvc, err := handler.getAppService()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// The app Service is not created yet, check it later.
+			return nil, reconcile.Result{}, false, nil
+		}
+		logger.Error(err, "Failed to get Service")
+		loganMetrics.UpdateReconcileErrors(boot.Kind,
+			loganMetrics.RECONCILE_UPDATE_STAGE,
+			loganMetrics.RECONCILE_GET_SERVICE_SUBSTAGE,
+			boot.Name)
+		return nil, reconcile.Result{Requeue: true}, true, err
+	}
+
+	active := serviceColor(appSvc)
+	total := *boot.Spec.Replicas
+
+	// 1. Disabled: the blue Deployment is rolled by the normal update logic, switch back to it when it is
+	// ready, then delete the green Deployment.
+	if !handler.blueGreenEnabled() {
+		if active == keys.ColorGreen {
+			blueOutdated, err := handler.workloadOutdated(&blue.Spec.Template)
+			if err != nil {
+				return nil, reconcile.Result{Requeue: true}, true, err
+			}
+			if blueOutdated || *blue.Spec.Replicas != total || !deploymentRolledOut(blue) {
+				return nil, reconcile.Result{}, false, nil
+			}
+			result, requeue, err := handler.switchServices("", false)
+			return nil, result, requeue, err
+		}
+
+		if green != nil {
+			result, requeue, err := handler.deleteColorDeployment(green)
+			return nil, result, requeue, err
+		}
+
+		if active != "" {
+			result, requeue, err := handler.switchServices("", false)
+			return nil, result, requeue, err
+		}
+		return nil, reconcile.Result{}, false, nil
+	}
+
+	// 2. Label the blue pods with the color, before the Services select by the color.
+	if active == "" {
+		if blue.Spec.Template.Labels[keys.ColorKey] != keys.ColorBlue {
+			logger.Info("Updating Deployment", "type", "color", "deploy", blue.Name, "new", keys.ColorBlue)
+			blue.Spec.Template.Labels = ColorPodLabels(boot, keys.ColorBlue)
+			applyMetadata(&blue.Spec.Template.ObjectMeta, boot.Spec.PodMetadata)
+			result, requeue, err := handler.updateColorDeployment(blue)
+			return nil, result, requeue, err
+		}
+		if !deploymentRolledOut(blue) {
+			return &rolloutState{hold: true}, reconcile.Result{}, false, nil
+		}
+		result, requeue, err := handler.switchServices(keys.ColorBlue, false)
+		return nil, result, requeue, err
+	}
+
+	activeDep, idleDep, idleColor := blue, green, keys.ColorGreen
+	if active == keys.ColorGreen {
+		activeDep, idleDep, idleColor = green, blue, keys.ColorBlue
+	}
+	if activeDep == nil {
+		// The green Deployment is deleted by others, switch back to the blue one.
+		result, requeue, err := handler.switchServices(keys.ColorBlue, false)
+		return nil, result, requeue, err
+	}
+
+	// The blue Deployment is updated only by the blue/green logic.
+	blueState := &rolloutState{hold: true}
+	if active == keys.ColorGreen {
+		blueState.replicas = blue.Spec.Replicas
+	}
+
+	activeOutdated, err := handler.workloadOutdated(&activeDep.Spec.Template)
+	if err != nil {
+		return nil, reconcile.Result{Requeue: true}, true, err
+	}
+
+	// 3. The active color runs the boot's version: keep all the replicas on it, and scale down the idle color
+	// after the delay.
+	if !activeOutdated {
+		if activeDep != blue && *activeDep.Spec.Replicas != total {
+			logger.Info("Updating Deployment", "type", "replicas", "deploy", activeDep.Name,
+				"old", activeDep.Spec.Replicas, "new", total)
+			activeDep.Spec.Replicas = &total
+			result, requeue, err := handler.updateColorDeployment(activeDep)
+			return nil, result, requeue, err
+		}
+
+		if idleDep != nil && *idleDep.Spec.Replicas > 0 && handler.blueGreenScaleDownAfter(appSvc) == 0 {
+			zero := int32(0)
+			logger.Info("Updating Deployment", "type", "replicas", "deploy", idleDep.Name,
+				"old", idleDep.Spec.Replicas, "new", zero)
+			idleDep.Spec.Replicas = &zero
+			result, requeue, err := handler.updateColorDeployment(idleDep)
+			if err == nil {
+				handler.RecordEvent(keys.ScaledDownBlueGreen, fmt.Sprintf("Scaled down Deployment: %s", idleDep.Name), nil)
+			}
+			return nil, result, requeue, err
+		}
+
+		return blueState, reconcile.Result{}, false, nil
+	}
+
+	// 4. Roll the idle color to the boot's version with all the replicas
+	if idleDep == nil {
+		idleDep = handler.NewColorDeployment(idleColor, total)
+		handler.setDeploymentHash(idleDep)
+		logger.Info("Creating Deployment", "deploy", idleDep.Name, "color", idleColor)
+		err = c.Create(context.TODO(), idleDep)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to create Deployment: %s", idleDep.Name)
+			logger.Error(err, msg)
+			loganMetrics.UpdateReconcileErrors(boot.Kind,
+				loganMetrics.RECONCILE_UPDATE_STAGE,
+				loganMetrics.RECONCILE_CREATE_COLOR_SUBSTAGE,
+				boot.Name)
+			handler.RecordEvent(keys.FailedBlueGreen, msg, err)
+			return nil, reconcile.Result{Requeue: true}, true, err
+		}
+		handler.RecordEvent(keys.CreatedDeployment, fmt.Sprintf("Created Deployment: %s", idleDep.Name), nil)
+		return nil, reconcile.Result{Requeue: true}, true, nil
+	}
+
+	idleOutdated, err := handler.workloadOutdated(&idleDep.Spec.Template)
+	if err != nil {
+		return nil, reconcile.Result{Requeue: true}, true, err
+	}
+	if idleOutdated || *idleDep.Spec.Replicas != total || idleDep.Spec.Template.Labels[keys.ColorKey] != idleColor {
+		logger.Info("Updating Deployment", "type", "color", "deploy", idleDep.Name,
+			"outdated", idleOutdated, "old", idleDep.Spec.Replicas, "new", total)
+		if idleOutdated || idleDep.Spec.Template.Labels[keys.ColorKey] != idleColor {
+			idleDep.Spec.Template = handler.NewColorDeployment(idleColor, total).Spec.Template
+			handler.setDeploymentHash(idleDep)
+		}
+		idleDep.Spec.Replicas = &total
+		result, requeue, err := handler.updateColorDeployment(idleDep)
+		return nil, result, requeue, err
+	}
+
+	if !deploymentRolledOut(idleDep) {
+		// wait for all the pods of the idle color are ready
+		return blueState, reconcile.Result{}, false, nil
+	}
+
+	// 5. Switch the Services to the idle color
+	rollback, activeRevisionId := false, 0
+	revisionLst, err := c.ListRevision(boot.Namespace, PodLabels(boot))
+	if err != nil {
+		logger.Info("Failed to list the revisions", "err", err.Error())
+	} else {
+		rollback, activeRevisionId = blueGreenRollback(revisionLst, idleDep, activeDep)
+	}
+	result, requeue, err := handler.switchServices(idleColor, rollback)
+	if err != nil {
+		return nil, result, requeue, err
+	}
+
+	if rollback {
+		handler.RecordEvent(keys.RolledBackBlueGreen,
+			fmt.Sprintf("Rolled back Services from Deployment: %s to Deployment: %s", activeDep.Name, idleDep.Name), nil)
+		err = handler.updateLatestRevisionAnnotation(map[string]string{
+			keys.BootRevisionRollbackAnnotationKey: strconv.Itoa(activeRevisionId),
+		})
+	} else {
+		handler.RecordEvent(keys.SwitchedBlueGreen,
+			fmt.Sprintf("Switched Services from Deployment: %s to Deployment: %s", activeDep.Name, idleDep.Name), nil)
+	}
+	if err != nil {
+		logger.Info("Failed to update the latest revision", "err", err.Error())
+	}
+
+	return nil, result, requeue, nil
+}
+
+// switchServices will switch the selector of the boot's Services to the color, empty color to select all the pods.
+func (handler *BootHandler) switchServices(color string, rollback bool) (reconcile.Result, bool, error) {
+	logger := handler.Logger
+	boot := handler.Boot
+	c := handler.Client
+
+	svcList, err := handler.listRuntimeService()
+	if err != nil {
+		loganMetrics.UpdateReconcileErrors(boot.Kind,
+			loganMetrics.RECONCILE_UPDATE_STAGE,
+			loganMetrics.RECONCILE_LIST_SERVICES_SUBSTAGE,
+			boot.Name)
+		return reconcile.Result{Requeue: true}, true, err
+	}
+
+	selector := PodLabels(boot)
+	if color != "" {
+		selector = ColorPodLabels(boot, color)
+	}
+
+	for i := range svcList.Items {
+		svc := &svcList.Items[i]
+		if svc.Spec.Selector == nil || svc.Spec.Selector[keys.BootNameKey] != boot.Name {
+			continue
+		}
+
+		logger.Info("Updating Service", "type", "selector", "service", svc.Name,
+			"old", svc.Spec.Selector, "new", selector, "rollback", rollback)
+		svc.Spec.Selector = selector
+		if svc.Name == boot.Name {
+			if svc.Annotations == nil {
+				svc.Annotations = make(map[string]string)
+			}
+			svc.Annotations[keys.BlueGreenSwitchedAtAnnotationKey] = time.Now().Format(time.RFC3339)
+		}
+
+		err = c.Update(context.TODO(), svc)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to switch Service: %s", svc.Name)
+			logger.Error(err, msg)
+			loganMetrics.UpdateReconcileErrors(boot.Kind,
+				loganMetrics.RECONCILE_UPDATE_STAGE,
+				loganMetrics.RECONCILE_SWITCH_SERVICE_SUBSTAGE,
+				boot.Name)
+			handler.RecordEvent(keys.FailedBlueGreen, msg, err)
+			return reconcile.Result{Requeue: true}, true, err
+		}
+	}
+
+	return reconcile.Result{Requeue: true}, true, nil
+}
+
+// updateColorDeployment will update the blue/green Deployment
+func (handler *BootHandler) updateColorDeployment(dep *appsv1.Deployment) (reconcile.Result, bool, error) {
+	logger := handler.Logger
+	boot := handler.Boot
+
+	err := handler.Client.Update(context.TODO(), dep)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to update Deployment: %s", dep.Name)
+		logger.Info(msg, "err", err.Error())
+		loganMetrics.UpdateReconcileErrors(boot.Kind,
+			loganMetrics.RECONCILE_UPDATE_STAGE,
+			loganMetrics.RECONCILE_UPDATE_COLOR_SUBSTAGE,
+			boot.Name)
+		handler.RecordEvent(keys.FailedUpdateDeployment, msg, err)
+		return reconcile.Result{Requeue: true}, true, err
+	}
+
+	handler.RecordEvent(keys.UpdatedDeployment, fmt.Sprintf("Updated Deployment: %s", dep.Name), nil)
+	return reconcile.Result{Requeue: true}, true, nil
+}
+
+// deleteColorDeployment will delete the green Deployment
+func (handler *BootHandler) deleteColorDeployment(dep *appsv1.Deployment) (reconcile.Result, bool, error) {
+	logger := handler.Logger
+	boot := handler.Boot
+
+	logger.Info("Deleting Deployment", "deploy", dep.Name)
+	err := handler.Client.Delete(context.TODO(), dep)
+	if err != nil && !errors.IsNotFound(err) {
+		msg := fmt.Sprintf("Failed to delete Deployment: %s", dep.Name)
+		logger.Error(err, msg)
+		loganMetrics.UpdateReconcileErrors(boot.Kind,
+			loganMetrics.RECONCILE_UPDATE_STAGE,
+			loganMetrics.RECONCILE_DELETE_COLOR_SUBSTAGE,
+			boot.Name)
+		handler.RecordEvent(keys.FailedBlueGreen, msg, err)
+		return reconcile.Result{Requeue: true}, true, err
+	}
+
+	return reconcile.Result{Requeue: true}, true, nil
+}
@@ -0,0 +1,107 @@
+package operator
+
+import (
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"strconv"
+	"time"
+)
+
+var _ = Describe("BlueGreen", func() {
+	DescribeTable("blueGreenEnabled",
+		func(mode appv1.RolloutMode, workload appv1.Workload, enabled bool) {
+			boot := newTestBoot("bluegreen")
+			boot.Spec.Rollout = &appv1.BootRollout{Mode: mode}
+			boot.Spec.Workload = workload
+			Expect(newTestHandler(boot, nil).blueGreenEnabled()).To(Equal(enabled))
+		},
+		Entry("default workload", appv1.BlueGreenRollout, appv1.Workload(""), true),
+		Entry("Deployment", appv1.BlueGreenRollout, appv1.Deployment, true),
+		Entry("StatefulSet", appv1.BlueGreenRollout, appv1.StatefulSet, false),
+		Entry("canary", appv1.CanaryRollout, appv1.Deployment, false),
+	)
+
+	Context("With the revision history", func() {
+		// revisions return the revision list of the hashes, the ids start from 1
+		revisions := func(hashes ...string) *appv1.BootRevisionList {
+			lst := &appv1.BootRevisionList{}
+			for i, hash := range hashes {
+				revision := appv1.BootRevision{}
+				revision.Annotations = map[string]string{
+					keys.BootRevisionIdAnnotationKey:   strconv.Itoa(i + 1),
+					keys.BootRevisionHashAnnotationKey: hash,
+				}
+				lst.Items = append(lst.Items, revision)
+			}
+			return lst
+		}
+		deployment := func(hash string) *appsv1.Deployment {
+			dep := &appsv1.Deployment{}
+			if hash != "" {
+				dep.Annotations = map[string]string{keys.BootRevisionHashAnnotationKey: hash}
+			}
+			return dep
+		}
+
+		DescribeTable("blueGreenRollback compares the versions of the colors in the history",
+			func(lst *appv1.BootRevisionList, idleHash, activeHash string, rollback bool, activeId int) {
+				isRollback, id := blueGreenRollback(lst, deployment(idleHash), deployment(activeHash))
+				Expect(isRollback).To(Equal(rollback))
+				Expect(id).To(Equal(activeId))
+			},
+			Entry("forward", revisions("a", "b"), "b", "a", false, 1),
+			Entry("forward to the version not recorded yet", revisions("a"), "b", "a", false, 1),
+			Entry("rollback", revisions("a", "b"), "a", "b", true, 2),
+			Entry("rollback recorded as a new revision", revisions("a", "b", "a"), "a", "b", true, 2),
+			Entry("back to a version recorded before the active one", revisions("a", "b", "c", "b"), "b", "c", true, 3),
+			Entry("same version", revisions("a"), "a", "a", false, 0),
+			Entry("not stamped", revisions("a", "b"), "", "b", false, 0),
+		)
+	})
+
+	It("test the Deployment is stamped with the hash of the boot's revision", func() {
+		boot := newTestBoot("bluegreen")
+		handler := newTestHandler(boot, nil)
+		dep := &appsv1.Deployment{}
+		handler.setDeploymentHash(dep)
+		Expect(deploymentHash(dep)).To(Equal(InitBootRevision(boot).BootHash()))
+
+		boot.Spec.Version = "v2"
+		Expect(deploymentHash(dep)).NotTo(Equal(InitBootRevision(boot).BootHash()))
+	})
+
+	It("test the active workload is selected by the app Service", func() {
+		boot := newTestBoot("bluegreen")
+		svc := &corev1.Service{}
+		svc.Name = boot.Name
+		svc.Namespace = boot.Namespace
+		svc.Spec.Selector = ColorPodLabels(boot, keys.ColorGreen)
+
+		handler := newTestHandler(boot, nil, svc)
+		Expect(handler.activeWorkloadName()).To(Equal(GreenWorkloadName(boot)))
+
+		handler = newTestHandler(boot, nil)
+		Expect(handler.activeWorkloadName()).To(Equal(WorkloadName(boot)))
+	})
+
+	It("test the idle color is scaled down after the delay", func() {
+		delay := int32(60)
+		boot := newTestBoot("bluegreen")
+		boot.Spec.Rollout = &appv1.BootRollout{Mode: appv1.BlueGreenRollout, ScaleDownDelaySeconds: &delay}
+		handler := newTestHandler(boot, nil)
+
+		svc := &corev1.Service{}
+		svc.Annotations = map[string]string{
+			keys.BlueGreenSwitchedAtAnnotationKey: time.Now().Add(-10 * time.Second).Format(time.RFC3339),
+		}
+		Expect(handler.blueGreenScaleDownAfter(svc)).To(BeNumerically("~", 50*time.Second, 2*time.Second))
+
+		svc.Annotations[keys.BlueGreenSwitchedAtAnnotationKey] = time.Now().Add(-time.Minute).Format(time.RFC3339)
+		Expect(handler.blueGreenScaleDownAfter(svc)).To(BeZero())
+	})
+})
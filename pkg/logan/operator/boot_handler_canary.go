@@ -0,0 +1,476 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	loganMetrics "github.com/logancloud/logan-app-operator/pkg/logan/metrics"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"strconv"
+	"time"
+)
+
+// canaryEnabled return whether the boot's new version is rolled out by canary
+func (handler *BootHandler) canaryEnabled() bool {
+	rollout := handler.Boot.Spec.Rollout
+	return rollout != nil && rollout.Mode == appv1.CanaryRollout && len(rollout.Steps) > 0
+}
+
+// canaryAborted return whether the canary is aborted by the Boot's annotation
+func (handler *BootHandler) canaryAborted() bool {
+	annotations := handler.Boot.Annotations
+	if annotations == nil {
+		return false
+	}
+	aborted, _ := strconv.ParseBool(annotations[keys.CanaryAbortAnnotationKey])
+	return aborted
+}
+
+// canaryReplicas return the number of replicas running the new version for the weight, at least one replica.
+func canaryReplicas(total int32, weight int32) int32 {
+	if total <= 0 {
+		return 0
+	}
+	replicas := (total*weight + 99) / 100
+	if replicas < 1 {
+		replicas = 1
+	}
+	if replicas > total {
+		replicas = total
+	}
+	return replicas
+}
+
+// currentCanaryStep return the current step of the canary recorded on the workload, and the time it started
+func (handler *BootHandler) currentCanaryStep(obj metav1.Object) (int, time.Time) {
+	annotations := obj.GetAnnotations()
+	step, _ := strconv.Atoi(annotations[keys.CanaryStepAnnotationKey])
+	// steps may be removed from the spec during the canary
+	if last := len(handler.Boot.Spec.Rollout.Steps) - 1; step > last {
+		step = last
+	}
+	if step < 0 {
+		step = 0
+	}
+
+	stepTime, err := time.Parse(time.RFC3339, annotations[keys.CanaryStepTimeAnnotationKey])
+	if err != nil {
+		stepTime = time.Time{}
+	}
+	return step, stepTime
+}
+
+// nextCanaryStep return the step the canary should be in, and whether it is promoted from the current step.
+// The canary is promoted when the Boot's promote annotation changed, or the step's pause is over after the new
+// replicas are ready.
+func (handler *BootHandler) nextCanaryStep(obj metav1.Object, ready bool) (int, bool) {
+	step, stepTime := handler.currentCanaryStep(obj)
+
+	promote := handler.Boot.Annotations[keys.CanaryPromoteAnnotationKey]
+	if promote != "" && promote != obj.GetAnnotations()[keys.CanaryPromoteAnnotationKey] {
+		return step + 1, true
+	}
+
+	pause := handler.Boot.Spec.Rollout.Steps[step].PauseSeconds
+	if pause == nil || !ready {
+		return step, false
+	}
+
+	if time.Since(stepTime) >= time.Duration(*pause)*time.Second {
+		return step + 1, true
+	}
+	return step, false
+}
+
+// canaryFinished return whether the canary passed its last step, the step recorded on the workload is beyond the
+// steps of the spec
+func (handler *BootHandler) canaryFinished(obj metav1.Object) bool {
+	step, err := strconv.Atoi(obj.GetAnnotations()[keys.CanaryStepAnnotationKey])
+	return err == nil && step >= len(handler.Boot.Spec.Rollout.Steps)
+}
+
+// canaryRequeueAfter return the remaining pause of the canary's current step, 0 if the step is not paused by time
+// or the pause is over
+func (handler *BootHandler) canaryRequeueAfter(obj metav1.Object) time.Duration {
+	if handler.canaryFinished(obj) {
+		return 0
+	}
+	step, stepTime := handler.currentCanaryStep(obj)
+	pause := handler.Boot.Spec.Rollout.Steps[step].PauseSeconds
+	if pause == nil {
+		return 0
+	}
+
+	// the pause is over, the canary is promoted when the new replicas are ready
+	remaining := time.Duration(*pause)*time.Second - time.Since(stepTime)
+	if remaining <= 0 {
+		return 0
+	}
+	return remaining
+}
+
+// setCanaryStep will record the step of the canary on the workload
+func (handler *BootHandler) setCanaryStep(obj metav1.Object, step int) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	annotations[keys.CanaryStepAnnotationKey] = strconv.Itoa(step)
+	annotations[keys.CanaryStepTimeAnnotationKey] = time.Now().Format(time.RFC3339)
+	if promote, ok := handler.Boot.Annotations[keys.CanaryPromoteAnnotationKey]; ok {
+		annotations[keys.CanaryPromoteAnnotationKey] = promote
+	}
+	obj.SetAnnotations(annotations)
+}
+
+// clearCanaryStep will remove the step of the canary from the workload, return true if removed
+func clearCanaryStep(obj metav1.Object) bool {
+	annotations := obj.GetAnnotations()
+	if _, ok := annotations[keys.CanaryStepAnnotationKey]; !ok {
+		return false
+	}
+
+	delete(annotations, keys.CanaryStepAnnotationKey)
+	delete(annotations, keys.CanaryStepTimeAnnotationKey)
+	delete(annotations, keys.CanaryPromoteAnnotationKey)
+	obj.SetAnnotations(annotations)
+	return true
+}
+
+// deploymentRolledOut return whether all the replicas of the Deployment are updated and available
+func deploymentRolledOut(dep *appsv1.Deployment) bool {
+	replicas := *dep.Spec.Replicas
+	return dep.Status.ObservedGeneration >= dep.Generation &&
+		dep.Status.Replicas == replicas &&
+		dep.Status.UpdatedReplicas == replicas &&
+		dep.Status.AvailableReplicas == replicas
+}
+
+// NewCanaryDeployment return a new created Boot's canary Deployment object.
+// The canary's pods have an additional track label, so the stable Deployment's pods are not selected by the
+// canary, both are selected by the app Service.
+func (handler *BootHandler) NewCanaryDeployment(replicas int32) *appsv1.Deployment {
+	boot := handler.Boot
+	podLabels := CanaryPodLabels(boot)
+
+	dep := handler.NewDeployment()
+	dep.Name = CanaryWorkloadName(boot)
+	dep.Spec.Replicas = &replicas
+	dep.Spec.Selector = &metav1.LabelSelector{
+		MatchLabels: podLabels,
+	}
+	dep.Spec.Template.Labels = podLabels
+	applyMetadata(&dep.Spec.Template.ObjectMeta, boot.Spec.PodMetadata)
+
+	return dep
+}
+
+// getCanaryDeployment return the boot's canary Deployment, nil if not found
+func (handler *BootHandler) getCanaryDeployment() (*appsv1.Deployment, error) {
+	boot := handler.Boot
+	c := handler.Client
+
+	canary := &appsv1.Deployment{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: CanaryWorkloadName(boot), Namespace: boot.Namespace}, canary)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return canary, nil
+}
+
+// getCanaryObject return the workload which records the step of the canary in progress, nil if no canary.
+func (handler *BootHandler) getCanaryObject() (metav1.Object, error) {
+	boot := handler.Boot
+	c := handler.Client
+
+	if boot.Spec.Workload == appv1.StatefulSet {
+		sts := &appsv1.StatefulSet{}
+		err := c.Get(context.TODO(), types.NamespacedName{Name: WorkloadName(boot), Namespace: boot.Namespace}, sts)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := sts.Annotations[keys.CanaryStepAnnotationKey]; !ok {
+			return nil, nil
+		}
+		return sts, nil
+	}
+
+	canary, err := handler.getCanaryDeployment()
+	if err != nil || canary == nil {
+		return nil, err
+	}
+	return canary, nil
+}
+
+// workloadOutdated return whether the pod template need to be rolled to the boot's new version
+func (handler *BootHandler) workloadOutdated(podTemplate *corev1.PodTemplateSpec) (bool, error) {
+	_, rebootUpdated, err := handler.reconcilePodTemplateSpecUpdate(podTemplate.DeepCopy())
+	return rebootUpdated, err
+}
+
+// reconcileCanaryDeploy handle the canary of Deployment. The new version runs in the canary Deployment
+// next to the stable one, the replicas are shifted to it step by step, and the stable Deployment is rolled to
+// the new version after the last step. A non-nil rolloutState is returned while the canary is in progress.
+func (handler *BootHandler) reconcileCanaryDeploy(stable *appsv1.Deployment) (*rolloutState, reconcile.Result, bool, error) {
+	logger := handler.Logger
+	boot := handler.Boot
+	c := handler.Client
+
+	canary, err := handler.getCanaryDeployment()
+	if err != nil {
+		logger.Error(err, "Failed to get canary Deployment")
+		loganMetrics.UpdateReconcileErrors(boot.Kind,
+			loganMetrics.RECONCILE_UPDATE_STAGE,
+			loganMetrics.RECONCILE_GET_CANARY_SUBSTAGE,
+			boot.Name)
+		return nil, reconcile.Result{Requeue: true}, true, err
+	}
+
+	outdated := false
+	if handler.canaryEnabled() {
+		outdated, err = handler.workloadOutdated(&stable.Spec.Template)
+		if err != nil {
+			return nil, reconcile.Result{Requeue: true}, true, err
+		}
+	}
+
+	// 1. No canary: the stable is of the new version, or the canary is disabled.
+	// The canary is deleted after the stable finished rolling out, so that the capacity is not reduced.
+	if !outdated {
+		if canary != nil && deploymentRolledOut(stable) {
+			result, requeue, err := handler.deleteCanaryDeployment(canary, keys.DeletedCanary)
+			return nil, result, requeue, err
+		}
+		return nil, reconcile.Result{}, false, nil
+	}
+
+	// 2. Aborted: delete the canary, the stable keeps the current version.
+	if handler.canaryAborted() {
+		err = handler.updateLatestRevisionPhase(RevisionPhaseCancel)
+		if err != nil {
+			logger.Info("Failed to cancel the latest revision", "err", err.Error())
+		}
+		if canary != nil {
+			result, requeue, err := handler.deleteCanaryDeployment(canary, keys.AbortedCanary)
+			return nil, result, requeue, err
+		}
+		return &rolloutState{hold: true}, reconcile.Result{}, false, nil
+	}
+
+	steps := boot.Spec.Rollout.Steps
+	total := *boot.Spec.Replicas
+
+	// 3. Start the canary from the first step
+	if canary == nil {
+		canary = handler.NewCanaryDeployment(canaryReplicas(total, steps[0].Weight))
+		handler.setCanaryStep(canary, 0)
+		logger.Info("Creating canary Deployment", "deploy", canary.Name, "replicas", canary.Spec.Replicas)
+		err = c.Create(context.TODO(), canary)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to create canary Deployment: %s", canary.Name)
+			logger.Error(err, msg)
+			loganMetrics.UpdateReconcileErrors(boot.Kind,
+				loganMetrics.RECONCILE_UPDATE_STAGE,
+				loganMetrics.RECONCILE_CREATE_CANARY_SUBSTAGE,
+				boot.Name)
+			handler.RecordEvent(keys.FailedCanary, msg, err)
+			return nil, reconcile.Result{Requeue: true}, true, err
+		}
+
+		handler.RecordEvent(keys.StartedCanary,
+			fmt.Sprintf("Started canary: %s, step 1/%d, weight %d%%", canary.Name, len(steps), steps[0].Weight), nil)
+		err = handler.updateLatestRevisionPhase(RevisionPhaseCanary)
+		if err != nil {
+			logger.Info("Failed to update the latest revision's phase", "err", err.Error())
+		}
+		return nil, reconcile.Result{Requeue: true}, true, nil
+	}
+
+	// 4. Promote the canary.
+	// After the last step, the stable is rolled to the new version with all the replicas, keep the canary until
+	// it is done.
+	if handler.canaryFinished(canary) {
+		return nil, reconcile.Result{}, false, nil
+	}
+
+	reason := "Updating canary Deployment"
+	updated := false
+	step, promoted := handler.nextCanaryStep(canary, deploymentRolledOut(canary))
+	if step >= len(steps) {
+		logger.Info(reason, "type", "step", "deploy", canary.Name, "old", len(steps)-1, "new", "finished")
+		handler.setCanaryStep(canary, len(steps))
+		result, requeue, err := handler.updateCanaryDeployment(canary)
+		if err == nil {
+			handler.RecordEvent(keys.PromotedCanary,
+				fmt.Sprintf("Promoted canary: %s, rolling out Deployment: %s", canary.Name, stable.Name), nil)
+		}
+		return nil, result, requeue, err
+	}
+	if promoted {
+		logger.Info(reason, "type", "step", "deploy", canary.Name, "old", step, "new", step+1)
+		handler.setCanaryStep(canary, step)
+		handler.RecordEvent(keys.PromotedCanary,
+			fmt.Sprintf("Promoted canary: %s, step %d/%d, weight %d%%", canary.Name, step+1, len(steps), steps[step].Weight), nil)
+		updated = true
+	}
+
+	// 5. Check the canary's replicas and pod template
+	replicas := canaryReplicas(total, steps[step].Weight)
+	if *canary.Spec.Replicas != replicas {
+		logger.Info(reason, "type", "replicas", "deploy", canary.Name,
+			"old", canary.Spec.Replicas, "new", replicas)
+		canary.Spec.Replicas = &replicas
+		updated = true
+	}
+
+	canaryOutdated, err := handler.workloadOutdated(&canary.Spec.Template)
+	if err != nil {
+		return nil, reconcile.Result{Requeue: true}, true, err
+	}
+	if canaryOutdated {
+		logger.Info(reason, "type", "template", "deploy", canary.Name)
+		canary.Spec.Template = handler.NewCanaryDeployment(replicas).Spec.Template
+		updated = true
+	}
+
+	if updated {
+		result, requeue, err := handler.updateCanaryDeployment(canary)
+		return nil, result, requeue, err
+	}
+
+	stableReplicas := total - replicas
+	return &rolloutState{hold: true, replicas: &stableReplicas}, reconcile.Result{}, false, nil
+}
+
+// updateCanaryDeployment will update the canary Deployment
+func (handler *BootHandler) updateCanaryDeployment(canary *appsv1.Deployment) (reconcile.Result, bool, error) {
+	logger := handler.Logger
+	boot := handler.Boot
+	c := handler.Client
+
+	err := c.Update(context.TODO(), canary)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to update canary Deployment: %s", canary.Name)
+		logger.Info(msg, "err", err.Error())
+		loganMetrics.UpdateReconcileErrors(boot.Kind,
+			loganMetrics.RECONCILE_UPDATE_STAGE,
+			loganMetrics.RECONCILE_UPDATE_CANARY_SUBSTAGE,
+			boot.Name)
+		handler.RecordEvent(keys.FailedCanary, msg, err)
+		return reconcile.Result{Requeue: true}, true, err
+	}
+	handler.RecordEvent(keys.UpdatedDeployment, fmt.Sprintf("Updated Deployment: %s", canary.Name), nil)
+	return reconcile.Result{Requeue: true}, true, nil
+}
+
+// deleteCanaryDeployment will delete the canary Deployment
+func (handler *BootHandler) deleteCanaryDeployment(canary *appsv1.Deployment, reason string) (reconcile.Result, bool, error) {
+	logger := handler.Logger
+	boot := handler.Boot
+	c := handler.Client
+
+	logger.Info("Deleting canary Deployment", "deploy", canary.Name)
+	err := c.Delete(context.TODO(), canary)
+	if err != nil && !errors.IsNotFound(err) {
+		msg := fmt.Sprintf("Failed to delete canary Deployment: %s", canary.Name)
+		logger.Error(err, msg)
+		loganMetrics.UpdateReconcileErrors(boot.Kind,
+			loganMetrics.RECONCILE_UPDATE_STAGE,
+			loganMetrics.RECONCILE_DELETE_CANARY_SUBSTAGE,
+			boot.Name)
+		handler.RecordEvent(keys.FailedCanary, msg, err)
+		return reconcile.Result{Requeue: true}, true, err
+	}
+
+	handler.RecordEvent(reason, fmt.Sprintf("Deleted canary Deployment: %s", canary.Name), nil)
+	return reconcile.Result{Requeue: true}, true, nil
+}
+
+// reconcileCanaryStatefulSet handle the canary of StatefulSet. The new version is rolled to the ordinals above
+// the partition, the partition is lowered step by step. The step is recorded on the StatefulSet itself.
+// A non-nil rolloutState is returned while the canary is in progress, and true if the StatefulSet is changed.
+func (handler *BootHandler) reconcileCanaryStatefulSet(sts *appsv1.StatefulSet) (*rolloutState, bool, error) {
+	logger := handler.Logger
+	boot := handler.Boot
+
+	_, inProgress := sts.Annotations[keys.CanaryStepAnnotationKey]
+	if !handler.canaryEnabled() {
+		return nil, clearCanaryStep(sts), nil
+	}
+
+	outdated, err := handler.workloadOutdated(&sts.Spec.Template)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// 1. Aborted: no more ordinals are rolled, the ordinals already rolled keep the new version until the
+	// Boot is changed back.
+	if handler.canaryAborted() {
+		if inProgress || outdated {
+			err = handler.updateLatestRevisionPhase(RevisionPhaseCancel)
+			if err != nil {
+				logger.Info("Failed to cancel the latest revision", "err", err.Error())
+			}
+			rollingUpdate := sts.Spec.UpdateStrategy.RollingUpdate
+			if inProgress && rollingUpdate != nil && rollingUpdate.Partition != nil &&
+				*rollingUpdate.Partition < *sts.Spec.Replicas {
+				handler.RecordEvent(keys.AbortedCanary, fmt.Sprintf("Aborted canary: %s", sts.Name), nil)
+			}
+			partition := *sts.Spec.Replicas
+			return &rolloutState{hold: true, partition: &partition}, false, nil
+		}
+		return nil, false, nil
+	}
+
+	steps := boot.Spec.Rollout.Steps
+	total := *sts.Spec.Replicas
+
+	// 2. Start the canary from the first step, the new pod template is applied by the pod spec check
+	if outdated {
+		handler.setCanaryStep(sts, 0)
+		handler.RecordEvent(keys.StartedCanary,
+			fmt.Sprintf("Started canary: %s, step 1/%d, weight %d%%", sts.Name, len(steps), steps[0].Weight), nil)
+		err = handler.updateLatestRevisionPhase(RevisionPhaseCanary)
+		if err != nil {
+			logger.Info("Failed to update the latest revision's phase", "err", err.Error())
+		}
+		partition := total - canaryReplicas(total, steps[0].Weight)
+		return &rolloutState{partition: &partition}, true, nil
+	}
+
+	if !inProgress {
+		return nil, false, nil
+	}
+
+	// 3. Promote the canary
+	step, _ := handler.currentCanaryStep(sts)
+	ready := sts.Status.ObservedGeneration >= sts.Generation &&
+		sts.Status.ReadyReplicas == total &&
+		sts.Status.UpdatedReplicas >= canaryReplicas(total, steps[step].Weight)
+	step, promoted := handler.nextCanaryStep(sts, ready)
+	if step >= len(steps) {
+		handler.RecordEvent(keys.PromotedCanary,
+			fmt.Sprintf("Promoted canary: %s, rolling out all the ordinals", sts.Name), nil)
+		return nil, clearCanaryStep(sts), nil
+	}
+	if promoted {
+		logger.Info("Updating StatefulSet", "type", "canary step", "statefulset", sts.Name,
+			"old", step, "new", step+1)
+		handler.setCanaryStep(sts, step)
+		handler.RecordEvent(keys.PromotedCanary,
+			fmt.Sprintf("Promoted canary: %s, step %d/%d, weight %d%%", sts.Name, step+1, len(steps), steps[step].Weight), nil)
+	}
+
+	partition := total - canaryReplicas(total, steps[step].Weight)
+	return &rolloutState{partition: &partition}, promoted, nil
+}
@@ -0,0 +1,116 @@
+package operator
+
+import (
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	"strconv"
+	"time"
+)
+
+var _ = Describe("Canary", func() {
+	DescribeTable("canaryReplicas",
+		func(total, weight, expected int32) {
+			Expect(canaryReplicas(total, weight)).To(Equal(expected))
+		},
+		Entry("no replicas", int32(0), int32(50), int32(0)),
+		Entry("at least one", int32(4), int32(1), int32(1)),
+		Entry("rounded up", int32(3), int32(50), int32(2)),
+		Entry("all", int32(4), int32(100), int32(4)),
+		Entry("at most all", int32(4), int32(200), int32(4)),
+	)
+
+	Context("With the canary steps", func() {
+		var handler *BootHandler
+		var canary *appsv1.Deployment
+
+		// stepAt record the step on the canary, started the seconds ago
+		stepAt := func(step int, seconds int) {
+			canary.Annotations = map[string]string{
+				keys.CanaryStepAnnotationKey:     strconv.Itoa(step),
+				keys.CanaryStepTimeAnnotationKey: time.Now().Add(-time.Duration(seconds) * time.Second).Format(time.RFC3339),
+			}
+		}
+
+		BeforeEach(func() {
+			pause := int32(60)
+			boot := newTestBoot("canary")
+			boot.Spec.Rollout = &appv1.BootRollout{
+				Mode: appv1.CanaryRollout,
+				Steps: []appv1.RolloutStep{
+					{Weight: 20, PauseSeconds: &pause},
+					{Weight: 50},
+				},
+			}
+			handler = newTestHandler(boot, nil)
+			canary = &appsv1.Deployment{}
+		})
+
+		It("test the step is kept during the pause", func() {
+			stepAt(0, 10)
+			step, promoted := handler.nextCanaryStep(canary, true)
+			Expect(step).To(Equal(0))
+			Expect(promoted).To(BeFalse())
+			Expect(handler.canaryRequeueAfter(canary)).To(BeNumerically("~", 50*time.Second, 2*time.Second))
+		})
+
+		It("test the step is kept until the replicas are ready", func() {
+			stepAt(0, 120)
+			step, promoted := handler.nextCanaryStep(canary, false)
+			Expect(step).To(Equal(0))
+			Expect(promoted).To(BeFalse())
+			Expect(handler.canaryRequeueAfter(canary)).To(BeZero())
+		})
+
+		It("test the step is promoted after the pause", func() {
+			stepAt(0, 120)
+			step, promoted := handler.nextCanaryStep(canary, true)
+			Expect(step).To(Equal(1))
+			Expect(promoted).To(BeTrue())
+		})
+
+		It("test the step without pause is promoted by the annotation only", func() {
+			stepAt(1, 3600)
+			step, promoted := handler.nextCanaryStep(canary, true)
+			Expect(step).To(Equal(1))
+			Expect(promoted).To(BeFalse())
+
+			handler.Boot.Annotations = map[string]string{keys.CanaryPromoteAnnotationKey: "1"}
+			step, promoted = handler.nextCanaryStep(canary, false)
+			Expect(step).To(Equal(2))
+			Expect(promoted).To(BeTrue())
+
+			// the same promote annotation is recorded on the canary, and does not promote again
+			handler.setCanaryStep(canary, 1)
+			step, promoted = handler.nextCanaryStep(canary, false)
+			Expect(step).To(Equal(1))
+			Expect(promoted).To(BeFalse())
+		})
+
+		It("test the canary is finished once after the last step", func() {
+			stepAt(1, 0)
+			Expect(handler.canaryFinished(canary)).To(BeFalse())
+
+			handler.setCanaryStep(canary, len(handler.Boot.Spec.Rollout.Steps))
+			Expect(handler.canaryFinished(canary)).To(BeTrue())
+			Expect(handler.canaryRequeueAfter(canary)).To(BeZero())
+			step, _ := handler.currentCanaryStep(canary)
+			Expect(step).To(Equal(1))
+
+			Expect(clearCanaryStep(canary)).To(BeTrue())
+			Expect(handler.canaryFinished(canary)).To(BeFalse())
+			Expect(clearCanaryStep(canary)).To(BeFalse())
+		})
+
+		It("test the removed steps", func() {
+			stepAt(1, 0)
+			handler.Boot.Spec.Rollout.Steps = handler.Boot.Spec.Rollout.Steps[:1]
+			step, _ := handler.currentCanaryStep(canary)
+			Expect(step).To(Equal(0))
+			Expect(handler.canaryFinished(canary)).To(BeTrue())
+		})
+	})
+})
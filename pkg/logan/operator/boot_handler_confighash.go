@@ -0,0 +1,350 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util/hash"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
+	"hash/fnv"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sort"
+)
+
+const (
+	// SecretRefsIndexField is the index field of the boots by the referenced Secrets
+	SecretRefsIndexField = "spec.secretRefs"
+	// ConfigMapRefsIndexField is the index field of the boots by the referenced ConfigMaps
+	ConfigMapRefsIndexField = "spec.configMapRefs"
+)
+
+var refLog = logf.Log.WithName("logan_reference_mapper")
+
+// AutoRestartEnabled return whether the pods should be restarted when the referenced Secrets or ConfigMaps changed
+func AutoRestartEnabled(spec *appv1.BootSpec) bool {
+	return spec.AutoRestart == nil || *spec.AutoRestart
+}
+
+// ReferencedSecrets return the sorted names of the Secrets referenced by the boot's pods and tlsSecret
+func ReferencedSecrets(spec *appv1.BootSpec) []string {
+	names := podSecretNames(spec)
+	if spec.TLSSecret != "" {
+		names[spec.TLSSecret] = true
+	}
+	return sortedNames(names)
+}
+
+// podSecretNames return the names of the Secrets referenced by the boot's env, envFrom and volumes
+func podSecretNames(spec *appv1.BootSpec) map[string]bool {
+	names := make(map[string]bool)
+	for _, env := range spec.Env {
+		if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+			names[env.ValueFrom.SecretKeyRef.Name] = true
+		}
+	}
+	for _, envFrom := range spec.EnvFrom {
+		if envFrom.SecretRef != nil {
+			names[envFrom.SecretRef.Name] = true
+		}
+	}
+	for _, vol := range spec.Volumes {
+		if vol.Secret != nil {
+			names[vol.Secret.SecretName] = true
+		}
+		if vol.Projected != nil {
+			for _, source := range vol.Projected.Sources {
+				if source.Secret != nil {
+					names[source.Secret.Name] = true
+				}
+			}
+		}
+	}
+	return names
+}
+
+// ReferencedConfigMaps return the sorted names of the ConfigMaps referenced by the boot's env, envFrom and volumes
+func ReferencedConfigMaps(spec *appv1.BootSpec) []string {
+	names := make(map[string]bool)
+	for _, env := range spec.Env {
+		if env.ValueFrom != nil && env.ValueFrom.ConfigMapKeyRef != nil {
+			names[env.ValueFrom.ConfigMapKeyRef.Name] = true
+		}
+	}
+	for _, envFrom := range spec.EnvFrom {
+		if envFrom.ConfigMapRef != nil {
+			names[envFrom.ConfigMapRef.Name] = true
+		}
+	}
+	for _, vol := range spec.Volumes {
+		if vol.ConfigMap != nil {
+			names[vol.ConfigMap.Name] = true
+		}
+		if vol.Projected != nil {
+			for _, source := range vol.Projected.Sources {
+				if source.ConfigMap != nil {
+					names[source.ConfigMap.Name] = true
+				}
+			}
+		}
+	}
+	return sortedNames(names)
+}
+
+func sortedNames(names map[string]bool) []string {
+	ret := make([]string, 0, len(names))
+	for name := range names {
+		if name != "" {
+			ret = append(ret, name)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// SecretRefsIndexValues return the index values of SecretRefsIndexField, only the TLS secret if the boot opts out
+// of AutoRestart, whose content is copied into the Route and must be kept in sync
+func SecretRefsIndexValues(spec *appv1.BootSpec) []string {
+	if !AutoRestartEnabled(spec) {
+		if spec.TLSSecret == "" {
+			return nil
+		}
+		return []string{spec.TLSSecret}
+	}
+	return ReferencedSecrets(spec)
+}
+
+// ConfigMapRefsIndexValues return the index values of ConfigMapRefsIndexField, empty if the boot opts out of AutoRestart
+func ConfigMapRefsIndexValues(spec *appv1.BootSpec) []string {
+	if !AutoRestartEnabled(spec) {
+		return nil
+	}
+	return ReferencedConfigMaps(spec)
+}
+
+// WatchReferences index the boots of the kind by the referenced Secrets and ConfigMaps, and watch the referenced ones,
+// the boots are restarted when they are changed. boot is the boot's kind, such as JavaBoot, and list is its list type.
+func WatchReferences(mgr manager.Manager, c controller.Controller, boot runtime.Object, list runtime.Object) error {
+	err := mgr.GetFieldIndexer().IndexField(boot, SecretRefsIndexField, func(obj runtime.Object) []string {
+		return SecretRefsIndexValues(bootSpecOf(obj))
+	})
+	if err != nil {
+		return err
+	}
+
+	err = mgr.GetFieldIndexer().IndexField(boot, ConfigMapRefsIndexField, func(obj runtime.Object) []string {
+		return ConfigMapRefsIndexValues(bootSpecOf(obj))
+	})
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}},
+		&handler.EnqueueRequestsFromMapFunc{ToRequests: referenceMapper(mgr.GetClient(), list, SecretRefsIndexField)},
+		referencePredicate(mgr.GetClient(), list, SecretRefsIndexField))
+	if err != nil {
+		return err
+	}
+
+	return c.Watch(&source.Kind{Type: &corev1.ConfigMap{}},
+		&handler.EnqueueRequestsFromMapFunc{ToRequests: referenceMapper(mgr.GetClient(), list, ConfigMapRefsIndexField)},
+		referencePredicate(mgr.GetClient(), list, ConfigMapRefsIndexField))
+}
+
+// bootSpecOf return the spec of the typed boot object
+func bootSpecOf(obj runtime.Object) *appv1.BootSpec {
+	switch typedBoot := obj.(type) {
+	case *appv1.JavaBoot:
+		return &typedBoot.Spec
+	case *appv1.PhpBoot:
+		return &typedBoot.Spec
+	case *appv1.PythonBoot:
+		return &typedBoot.Spec
+	case *appv1.NodeJSBoot:
+		return &typedBoot.Spec
+	case *appv1.WebBoot:
+		return &typedBoot.Spec
+	}
+	return &appv1.BootSpec{}
+}
+
+// referencePredicate pass only the events of the Secrets or ConfigMaps referenced by the boots of the index, and
+// skip the updates which do not change their content
+func referencePredicate(c client.Client, list runtime.Object, indexField string) predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return len(referencingBoots(c, list, indexField, e.Meta)) > 0
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return contentChanged(e.ObjectOld, e.ObjectNew) &&
+				len(referencingBoots(c, list, indexField, e.MetaNew)) > 0
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return len(referencingBoots(c, list, indexField, e.Meta)) > 0
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return len(referencingBoots(c, list, indexField, e.Meta)) > 0
+		},
+	}
+}
+
+// contentChanged return whether the data of the Secret or ConfigMap is changed
+func contentChanged(oldObj, newObj runtime.Object) bool {
+	switch oldRef := oldObj.(type) {
+	case *corev1.Secret:
+		newRef, ok := newObj.(*corev1.Secret)
+		return !ok || !reflect.DeepEqual(oldRef.Data, newRef.Data)
+	case *corev1.ConfigMap:
+		newRef, ok := newObj.(*corev1.ConfigMap)
+		return !ok || !reflect.DeepEqual(oldRef.Data, newRef.Data) ||
+			!reflect.DeepEqual(oldRef.BinaryData, newRef.BinaryData)
+	}
+	return true
+}
+
+// referenceMapper return a ToRequestsFunc, which maps the Secret or ConfigMap to the boots referencing it by the index.
+func referenceMapper(c client.Client, list runtime.Object, indexField string) handler.ToRequestsFunc {
+	return func(a handler.MapObject) []reconcile.Request {
+		return referencingBoots(c, list, indexField, a.Meta)
+	}
+}
+
+// referencingBoots return the requests of the boots referencing the Secret or ConfigMap by the index.
+// list is the list type of the boot's kind, such as JavaBootList.
+func referencingBoots(c client.Client, list runtime.Object, indexField string, obj metav1.Object) []reconcile.Request {
+	bootList := list.DeepCopyObject()
+	err := c.List(context.TODO(), bootList,
+		client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{indexField: obj.GetName()})
+	if err != nil {
+		refLog.Error(err, "Failed to list boots by index", "index", indexField,
+			"namespace", obj.GetNamespace(), "name", obj.GetName())
+		return nil
+	}
+
+	items, err := meta.ExtractList(bootList)
+	if err != nil {
+		refLog.Error(err, "Failed to extract boots", "index", indexField)
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(items))
+	for _, item := range items {
+		accessor, err := meta.Accessor(item)
+		if err != nil {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: accessor.GetNamespace(),
+			Name:      accessor.GetName(),
+		}})
+	}
+	return requests
+}
+
+// configHash return the hash of the content of the Secrets and ConfigMaps referenced by the boot.
+// Return empty if the boot opts out of AutoRestart or references nothing, the missing ones are skipped.
+// The hash is computed once and reused in the reconcile.
+func (handler *BootHandler) configHash() (string, error) {
+	if handler.cachedConfigHash != nil {
+		return *handler.cachedConfigHash, nil
+	}
+
+	configHash, err := handler.computeConfigHash()
+	if err != nil {
+		return "", err
+	}
+	handler.cachedConfigHash = &configHash
+	return configHash, nil
+}
+
+// computeConfigHash read the Secrets and ConfigMaps referenced by the boot, and return the hash of their content
+func (handler *BootHandler) computeConfigHash() (string, error) {
+	boot := handler.Boot
+	c := handler.Client
+	if !AutoRestartEnabled(&boot.Spec) {
+		return "", nil
+	}
+
+	// The TLS secret is only copied into the Route, the pods are not restarted by its change
+	secretNames := sortedNames(podSecretNames(&boot.Spec))
+	configMapNames := ReferencedConfigMaps(&boot.Spec)
+	if len(secretNames) == 0 && len(configMapNames) == 0 {
+		return "", nil
+	}
+
+	contents := make(map[string]interface{})
+	for _, name := range configMapNames {
+		cm := &corev1.ConfigMap{}
+		err := c.Get(context.TODO(), types.NamespacedName{Namespace: boot.Namespace, Name: name}, cm)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return "", err
+		}
+		contents["configmap/"+name] = []interface{}{cm.Data, cm.BinaryData}
+	}
+
+	for _, name := range secretNames {
+		secret := &corev1.Secret{}
+		err := c.Get(context.TODO(), types.NamespacedName{Namespace: boot.Namespace, Name: name}, secret)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return "", err
+		}
+		contents["secret/"+name] = secret.Data
+	}
+
+	hasher := fnv.New32a()
+	hash.DeepHashObject(hasher, contents)
+	return rand.SafeEncodeString(fmt.Sprint(hasher.Sum32())), nil
+}
+
+// reconcileConfigHash set the config hash of the pod template's annotations, return true if it is changed.
+func (handler *BootHandler) reconcileConfigHash(podSpec *corev1.PodTemplateSpec) (bool, error) {
+	logger := handler.Logger
+
+	configHash, err := handler.configHash()
+	if err != nil {
+		return false, err
+	}
+
+	workloadHash, found := podSpec.Annotations[keys.BootConfigHashAnnotationKey]
+	if configHash == "" {
+		if !found {
+			return false, nil
+		}
+		logger.Info("Updating Workload PodTemplateSpec", "type", "configHash", "old", workloadHash, "new", "")
+		delete(podSpec.Annotations, keys.BootConfigHashAnnotationKey)
+		return true, nil
+	}
+
+	if workloadHash == configHash {
+		return false, nil
+	}
+
+	logger.Info("Updating Workload PodTemplateSpec", "type", "configHash", "old", workloadHash, "new", configHash)
+	if podSpec.Annotations == nil {
+		podSpec.Annotations = make(map[string]string)
+	}
+	podSpec.Annotations[keys.BootConfigHashAnnotationKey] = configHash
+	return true, nil
+}
@@ -8,8 +8,8 @@ import (
 	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/intstr"
 	"reflect"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -81,6 +81,9 @@ func (handler *BootHandler) ReconcileCreate() (reconcile.Result, bool, error) {
 // 1.1. Check Workload's fields: "replicas", image, env, port, resources, health, nodeSelector
 // 2. Check Service's existence: error -> requeue=true
 // 2.1 Check Service's fields:
+// 3. Check Ingress(or Route on OpenShift)'s existence and fields by the subDomain: error -> requeue=true
+// 4. Check PodDisruptionBudget's existence and fields by the disruption: error -> requeue=true
+// 5. Check ServiceAccount, Role and RoleBinding's existence and fields by the serviceAccount: error -> requeue=true
 func (handler *BootHandler) ReconcileUpdate() (reconcile.Result, bool, error) {
 	boot := handler.Boot
 	logger := handler.Logger
@@ -113,6 +116,24 @@ func (handler *BootHandler) ReconcileUpdate() (reconcile.Result, bool, error) {
 		return result, true, err
 	}
 
+	//3 Ingress
+	result, requeue, err = handler.reconcileUpdateIngress()
+	if requeue {
+		return result, true, err
+	}
+
+	//4 PodDisruptionBudget
+	result, requeue, err = handler.reconcileUpdatePdb()
+	if requeue {
+		return result, true, err
+	}
+
+	//5 ServiceAccount, Role and RoleBinding
+	result, requeue, err = handler.reconcileUpdateServiceAccount()
+	if requeue {
+		return result, true, err
+	}
+
 	return reconcile.Result{}, false, nil
 }
 
@@ -136,38 +157,65 @@ func (handler *BootHandler) reconcileUpdateService(svc *corev1.Service, podSpec
 		updated = true
 	}
 
-	// 2. Check port
-	svcPort := svc.Spec.Ports[0].Port
-	bootPort := boot.Spec.Port
-	// Port changed
-	if !(svcPort == bootPort) {
-		logger.Info(reason, "type", "port", "service", svc.Name, "old", svcPort, "new", bootPort)
-		svc.Spec.Ports[0].Port = bootPort
-		svc.Spec.Ports[0].TargetPort = intstr.IntOrString{Type: intstr.Int, IntVal: int32(bootPort)}
+	// 2. Check ports: the primary port and the exposed ports
+	bootPorts := AppServicePorts(boot)
+	if !reflect.DeepEqual(svc.Spec.Ports, bootPorts) {
+		logger.Info(reason, "type", "ports", "service", svc.Name, "old", svc.Spec.Ports, "new", bootPorts)
+		svc.Spec.Ports = bootPorts
 
 		updated = true
 	}
 
-	// 3. Check annotation
-	// Annotation is removed
+	// 3. Check annotation: prometheus annotations point at the metrics port, app-protocols of the ports.
+	// Only these annotations are managed, the others of the Service are kept.
 	prometheusScrape := allowPrometheusScrape(boot, handler.Config.AppSpec)
-	if svc.Annotations == nil && prometheusScrape == true {
-		svc.Annotations = ServiceAnnotation(prometheusScrape, int(boot.Spec.Port))
+	bootAnnotations := AppServiceAnnotation(boot, prometheusScrape)
+	for _, key := range []string{
+		keys.PrometheusPathAnnotationKey,
+		keys.PrometheusPortAnnotationKey,
+		keys.PrometheusSchemeAnnotationKey,
+		keys.PrometheusScrapeAnnotationKey,
+		keys.AppProtocolsAnnotationKey,
+	} {
+		svcValue, svcFound := svc.Annotations[key]
+		bootValue, bootFound := bootAnnotations[key]
+		if svcFound == bootFound && svcValue == bootValue {
+			continue
+		}
+
+		logger.Info(reason, "type", "annotation", "service", svc.Name, "key", key, "old", svcValue, "new", bootValue)
+		if bootFound {
+			if svc.Annotations == nil {
+				svc.Annotations = make(map[string]string)
+			}
+			svc.Annotations[key] = bootValue
+		} else {
+			delete(svc.Annotations, key)
+		}
 		updated = true
 	}
 
-	// Annotation port is changed
-	if svc.Annotations != nil {
-		svcAnnoPort := svc.Annotations[keys.PrometheusPortAnnotationKey]
-		if svcAnnoPort != strconv.Itoa(int(boot.Spec.Port)) {
-			svc.Annotations[keys.PrometheusPortAnnotationKey] = strconv.Itoa(int(boot.Spec.Port))
-			updated = true
-		}
+	// 3.1 Check the labels and annotations of serviceMetadata
+	if applyMetadata(&svc.ObjectMeta, boot.Spec.ServiceMetadata) {
+		logger.Info(reason, "type", "serviceMetadata", "service", svc.Name)
+		updated = true
 	}
 
-	//PrometheusScrape is changed
-	if svc.Annotations != nil && prometheusScrape == false {
-		svc.Annotations = ServiceAnnotation(prometheusScrape, int(boot.Spec.Port))
+	// 3.2 Check the hash of the service overrides, the spec is rebuilt with the patch.
+	// The allocated clusterIP and nodePorts are kept.
+	if overridesChanged(svc, handler.serviceOverride()) {
+		expected := handler.NewServices(podSpec)[0]
+		logger.Info(reason, "type", "overrides", "service", svc.Name, "old", svc.Spec, "new", expected.Spec)
+		expected.Spec.ClusterIP = svc.Spec.ClusterIP
+		for i, port := range expected.Spec.Ports {
+			for _, svcPort := range svc.Spec.Ports {
+				if port.Name == svcPort.Name && port.NodePort == 0 && expected.Spec.Type != corev1.ServiceTypeClusterIP {
+					expected.Spec.Ports[i].NodePort = svcPort.NodePort
+				}
+			}
+		}
+		svc.Spec = expected.Spec
+		setOverridesHash(svc, OverridesHash(handler.serviceOverride()))
 		updated = true
 	}
 
@@ -219,7 +267,10 @@ func (handler *BootHandler) reconcileUpdateService(svc *corev1.Service, podSpec
 	return reconcile.Result{}, false, nil
 }
 
-func (handler *BootHandler) checkVolumeMountUpdate(deleted, added, modified []corev1.VolumeMount) (bool, error) {
+// checkVolumeMountUpdate return true if the changed VolumeMounts belong to the boot: boot's private or shared pvc,
+// or the boot's volumes. volumes is the volumes of the running workload.
+func (handler *BootHandler) checkVolumeMountUpdate(volumes []corev1.Volume,
+	deleted, added, modified []corev1.VolumeMount) (bool, error) {
 	c := handler.Client
 	boot := handler.Boot
 
@@ -255,6 +306,36 @@ func (handler *BootHandler) checkVolumeMountUpdate(deleted, added, modified []co
 		return false, nil
 	}
 
+	// The added or modified mounts of the boot's volumes
+	for _, vols := range [][]corev1.VolumeMount{added, modified} {
+		for _, vol := range vols {
+			for _, bootVol := range boot.Spec.Volumes {
+				if vol.Name == bootVol.Name {
+					return true, nil
+				}
+			}
+		}
+	}
+
+	// The deleted mounts of the boot's volumes: the workload's volume is not pvc and not from settings
+	configVols := make(map[string]bool)
+	if handler.Config.AppSpec.PodSpec != nil {
+		for _, vol := range handler.Config.AppSpec.PodSpec.Volumes {
+			name, _ := Decode(boot, vol.Name)
+			configVols[name] = true
+		}
+	}
+	for _, vol := range deleted {
+		if configVols[vol.Name] {
+			continue
+		}
+		for _, workloadVol := range volumes {
+			if workloadVol.Name == vol.Name && workloadVol.PersistentVolumeClaim == nil {
+				return true, nil
+			}
+		}
+	}
+
 	allVols := make([]corev1.VolumeMount, 0)
 	allVols = append(allVols, deleted...)
 	allVols = append(allVols, added...)
@@ -350,6 +431,11 @@ func (handler *BootHandler) reconcileUpdateOtherService(podSpec *corev1.PodTempl
 						modify = true
 					}
 				}
+
+				// 5. Check the labels and annotations of serviceMetadata
+				if applyMetadata(&runtimeSvc.ObjectMeta, boot.Spec.ServiceMetadata) {
+					modify = true
+				}
 			}
 		}
 
@@ -455,7 +541,7 @@ func (handler *BootHandler) ReconcileUpdateBootMeta() (reconcile.Result, bool, b
 	c := handler.Client
 
 	// 1. Update Workload's metadata/annotations if needed
-	workloadName := WorkloadName(boot)
+	workloadName := handler.activeWorkloadName()
 	replicas, currentReplicas, _ := handler.getWorkloadStatus()
 
 	// 2. Update Service's metadata/annotations if needed
@@ -495,18 +581,55 @@ func (handler *BootHandler) ReconcileUpdateBootMeta() (reconcile.Result, bool, b
 
 	// 3.2 Update Boot's annotation revision
 	//   select latest revision. set it
+	//   skip the phase until the revision of the boot's current spec is recorded
 	revisionLst, _ := c.ListRevision(boot.Namespace, podLabels)
 	latestRevision := revisionLst.SelectLatestRevision()
+	revisionPending := latestRevision != nil && !revisionOfBoot(latestRevision, boot)
+	if revisionPending {
+		logger.V(1).Info("Latest revision is not recorded from the Boot yet", "revision", latestRevision.Name)
+		latestRevision = nil
+	}
 
 	// 3.2.1 Update Boot's revison's annotation
 	//    set the latest revison's phase to active
+	//    the phase is Canary while the canary in progress, and stays Cancelled while the canary is aborted
+	var canaryObj metav1.Object
+	if handler.canaryEnabled() {
+		canaryObj, err = handler.getCanaryObject()
+		if err != nil {
+			logger.Info("Failed to get canary", "err", err.Error())
+		}
+	}
 	revisionAnnotationMap := map[string]string{}
-	if runningCount == *boot.Spec.Replicas && runningCount == currentReplicas {
+	if handler.canaryEnabled() && handler.canaryAborted() && latestRevision != nil &&
+		latestRevision.Annotations[keys.BootRevisionPhaseAnnotationKey] == RevisionPhaseCancel {
+		revisionAnnotationMap[keys.BootRevisionPhaseAnnotationKey] = RevisionPhaseCancel
+	} else if canaryObj != nil {
+		revisionAnnotationMap[keys.BootRevisionPhaseAnnotationKey] = RevisionPhaseCanary
+	} else if handler.blueGreenInProgress() {
+		revisionAnnotationMap[keys.BootRevisionPhaseAnnotationKey] = RevisionPhaseRunning
+	} else if runningCount == *boot.Spec.Replicas && runningCount == currentReplicas {
 		revisionAnnotationMap[keys.BootRevisionPhaseAnnotationKey] = RevisionPhaseActive
 	} else {
 		revisionAnnotationMap[keys.BootRevisionPhaseAnnotationKey] = RevisionPhaseRunning
 	}
 
+	// 3.2.2 Mark the running revision Failed if it fails to become Active, the phase Failed is kept
+	failure := ""
+	if latestRevision != nil {
+		phase := latestRevision.Annotations[keys.BootRevisionPhaseAnnotationKey]
+		if phase == RevisionPhaseFailed {
+			revisionAnnotationMap[keys.BootRevisionPhaseAnnotationKey] = RevisionPhaseFailed
+		} else if phase == RevisionPhaseRunning &&
+			revisionAnnotationMap[keys.BootRevisionPhaseAnnotationKey] == RevisionPhaseRunning {
+			failure = handler.revisionFailure(latestRevision, podList.Items)
+			if failure != "" {
+				revisionAnnotationMap[keys.BootRevisionPhaseAnnotationKey] = RevisionPhaseFailed
+				revisionAnnotationMap[keys.BootRevisionFailureAnnotationKey] = failure
+			}
+		}
+	}
+
 	if latestRevision != nil {
 		revisionUpdated := updateRevisionAnnotation(latestRevision, revisionAnnotationMap)
 		if revisionUpdated {
@@ -521,10 +644,25 @@ func (handler *BootHandler) ReconcileUpdateBootMeta() (reconcile.Result, bool, b
 			}
 			handler.RecordEvent(keys.UpdatedBootMeta, "Updated Boot Revision Meta", nil)
 		}
-	} else {
+	} else if !revisionPending {
 		logger.Info("can not find latest Revision", "boot", boot)
 	}
 
+	// 3.2.3 Rollback to the last Complete revision if the strategy's autoRollback is enabled
+	rollbackTo := ""
+	if failure != "" {
+		msg := fmt.Sprintf("Revision %d failed to become Active: %s", latestRevision.GetRevisionId(), failure)
+		logger.Info(msg)
+		loganMetrics.UpdateRevisionFailures(boot.Kind, boot.Name)
+		handler.RecordEvent(keys.FailedRevision, msg, nil)
+
+		if revision := handler.autoRollbackRevision(revisionLst, latestRevision); revision != nil {
+			rollbackTo = strconv.Itoa(revision.GetRevisionId())
+			handler.RecordEvent(keys.AutoRollbackBoot,
+				fmt.Sprintf("Rolling back Boot to the last Complete revision %s", rollbackTo), nil)
+		}
+	}
+
 	//requeue := false
 	//if revisionAnnotationMap[keys.BootRevisionPhaseAnnotationKey] == RevisionPhaseRunning {
 	//	logger.V(1).Info("Revision is running,should requeue", "revision", latestRevision)
@@ -543,9 +681,29 @@ func (handler *BootHandler) ReconcileUpdateBootMeta() (reconcile.Result, bool, b
 	if latestRevision != nil {
 		annotationMap[keys.BootRevisionIdAnnotationKey] = strconv.Itoa(latestRevision.GetRevisionId())
 	}
+	if rollbackTo != "" {
+		annotationMap[keys.BootRollbackToAnnotationKey] = rollbackTo
+	}
 
 	updated := handler.UpdateAnnotation(annotationMap)
 
+	// 5. Requeue when the canary's step is paused by time, the idle color is waiting to be scaled down,
+	// the running revision is waiting for its deadline, or the boot's revision is not recorded yet
+	if canaryObj != nil && !handler.canaryAborted() {
+		if requeueAfter := handler.canaryRequeueAfter(canaryObj); requeueAfter > 0 {
+			return reconcile.Result{RequeueAfter: requeueAfter}, true, updated, nil
+		}
+	}
+	if requeueAfter := handler.blueGreenRequeueAfter(); requeueAfter > 0 {
+		return reconcile.Result{RequeueAfter: requeueAfter}, true, updated, nil
+	}
+	if requeueAfter := handler.revisionRequeueAfter(latestRevision); requeueAfter > 0 {
+		return reconcile.Result{RequeueAfter: requeueAfter}, true, updated, nil
+	}
+	if revisionPending {
+		return reconcile.Result{RequeueAfter: revisionPendingRequeue}, true, updated, nil
+	}
+
 	//if requeue {
 	//	return reconcile.Result{RequeueAfter: time.Second * 10}, true, updated, nil
 	//}
@@ -189,6 +189,45 @@ func (handler *BootHandler) DefaultValue() bool {
 			changed = true
 		}
 	}
+
+	//tolerations: the config's tolerations are always kept
+	if len(appConfigSpec.Tolerations) > 0 {
+		tolerations, merged := MergeTolerations(bootSpec.Tolerations, appConfigSpec.Tolerations)
+		if merged {
+			logger.Info("Defaulters", "type", "tolerations", "spec", bootSpec.Tolerations, "to", tolerations)
+			bootSpec.Tolerations = tolerations
+			changed = true
+		}
+	}
+
+	//nodeAffinity: the config's required terms are ANDed, the preferred terms are kept
+	if appConfigSpec.NodeAffinity != nil {
+		nodeAffinity, merged := MergeNodeAffinity(bootSpec.NodeAffinity, appConfigSpec.NodeAffinity)
+		if merged {
+			logger.Info("Defaulters", "type", "nodeAffinity", "to", nodeAffinity)
+			bootSpec.NodeAffinity = nodeAffinity
+			changed = true
+		}
+	}
+
+	//zoneAntiAffinity
+	if bootSpec.ZoneAntiAffinity == "" && appConfigSpec.ZoneAntiAffinity != "" {
+		logger.Info("Defaulters", "type", "zoneAntiAffinity",
+			"spec", bootSpec.ZoneAntiAffinity, "default", appConfigSpec.ZoneAntiAffinity)
+		bootSpec.ZoneAntiAffinity = appConfigSpec.ZoneAntiAffinity
+		changed = true
+	}
+
+	//topologySpreadConstraints
+	if len(bootSpec.TopologySpreadConstraints) == 0 && len(appConfigSpec.TopologySpreadConstraints) > 0 {
+		logger.Info("Defaulters", "type", "topologySpreadConstraints",
+			"spec", bootSpec.TopologySpreadConstraints, "default", appConfigSpec.TopologySpreadConstraints)
+		bootSpec.TopologySpreadConstraints = make([]appv1.BootTopologySpreadConstraint,
+			len(appConfigSpec.TopologySpreadConstraints))
+		copy(bootSpec.TopologySpreadConstraints, appConfigSpec.TopologySpreadConstraints)
+		changed = true
+	}
+
 	envChanged := handler.DefaultEnvValue()
 	pvcChanged := handler.DefaultPvcValue()
 	workloadChanged := handler.DefaultWorkload()
@@ -197,6 +236,26 @@ func (handler *BootHandler) DefaultValue() bool {
 	return changed || envChanged || pvcChanged || workloadChanged || hpaChanged
 }
 
+// DefaultedBoot return a copy of the boot merged with the default value of the handler's config, the same as
+// DefaultValue does to the handler's boot
+func (handler *BootHandler) DefaultedBoot(boot *appv1.Boot) *appv1.Boot {
+	defaulted := boot.DeepCopy()
+	bootHandler := &BootHandler{
+		OperatorBoot: defaulted,
+		OperatorSpec: &defaulted.Spec,
+		OperatorMeta: &defaulted.ObjectMeta,
+
+		Boot:   boot.DeepCopy(),
+		Config: handler.Config,
+
+		Scheme: handler.Scheme,
+		Client: handler.Client,
+		Logger: handler.Logger,
+	}
+	bootHandler.DefaultValue()
+	return defaulted
+}
+
 // DefaultHpa will handle the HPA changed.
 // Return true if should be updated, false if should not be updated
 func (handler *BootHandler) DefaultHpa() bool {
@@ -256,7 +315,7 @@ func (handler *BootHandler) DefaultWorkload() bool {
 	return changed || updated
 }
 
-// DefaultPvcValue will handle the pvc changed.
+// DefaultPvcValue will handle the pvc and volumes changed.
 // Return true if should be updated, false if should not be updated
 func (handler *BootHandler) DefaultPvcValue() bool {
 	logger := handler.Logger
@@ -296,6 +355,10 @@ func (handler *BootHandler) DefaultPvcValue() bool {
 			vols = append(vols, ConvertVolumeMount(bootSpec.Pvc)...)
 		}
 
+		if len(bootSpec.Volumes) > 0 {
+			vols = append(vols, ConvertBootVolumeMount(bootSpec.Volumes)...)
+		}
+
 		if len(vols) > 0 {
 			DecodeVolumeMounts(boot, vols)
 			volStr, err := MarshalVolumeMountVars(vols)
@@ -319,8 +382,10 @@ func (handler *BootHandler) DefaultPvcValue() bool {
 			logger.Error(err, "Decoding annotation's pvc error.")
 			return false
 		}
-		if PvcVarsEq(previousPvc, bootSpec.Pvc) {
-			return false
+		// The boot's volumes are only kept in the deploy vols, which is refreshed even if the pvc is not changed.
+		// UpdateAnnotation only reports the changed annotations.
+		if !PvcVarsEq(previousPvc, bootSpec.Pvc) {
+			logger.Info("Boot's pvc changed", "old", previousPvc, "new", bootSpec.Pvc)
 		}
 		updatePvcMeta()
 	}
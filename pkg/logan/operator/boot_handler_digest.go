@@ -0,0 +1,105 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	"github.com/logancloud/logan-app-operator/pkg/logan/registry"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ResolveImageDigest record the image digest of the boot's version, used by the mutation webhook.
+// oldSpec is the spec before the update, nil when creating. If the version is changed without the digest,
+// the recorded digest is stale and cleared; if they are changed together, such as rollback to a revision,
+// the digest is kept. In the digest mode, an empty digest is resolved by the resolver with the credentials of
+// the pods' image pull secrets, a nil resolver skips the resolution.
+// Return true if the boot's digest is changed.
+func (handler *BootHandler) ResolveImageDigest(resolver registry.Resolver, oldSpec *appv1.BootSpec) (bool, error) {
+	logger := handler.Logger
+	bootSpec := handler.OperatorSpec
+	if bootSpec == nil || IsDeletedObject(handler.OperatorMeta) {
+		return false, nil
+	}
+
+	changed := false
+	if oldSpec != nil && oldSpec.ImageDigest != "" &&
+		bootSpec.Version != oldSpec.Version && bootSpec.ImageDigest == oldSpec.ImageDigest {
+		logger.Info("Clearing stale image digest", "version", bootSpec.Version, "digest", bootSpec.ImageDigest)
+		bootSpec.ImageDigest = ""
+		changed = true
+	}
+
+	if resolver == nil || bootSpec.ImageDigest != "" || !ImageDigestEnabled(handler.Config.AppSpec) {
+		return changed, nil
+	}
+
+	boot := handler.Boot.DeepCopy()
+	boot.Spec = *bootSpec
+	image := AppContainerImageRepository(boot, handler.Config.AppSpec)
+	keychain, err := handler.imagePullKeychain(boot)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to read image pull secrets: %s:%s", image, bootSpec.Version)
+		logger.Error(err, msg)
+		handler.RecordEvent(keys.FailedResolveImageDigest, msg, err)
+		return changed, err
+	}
+
+	digest, err := resolver.Resolve(image, bootSpec.Version, keychain)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to resolve image digest: %s:%s", image, bootSpec.Version)
+		logger.Error(err, msg)
+		handler.RecordEvent(keys.FailedResolveImageDigest, msg, err)
+		return changed, err
+	}
+
+	logger.Info("Resolved image digest", "image", image, "version", bootSpec.Version, "digest", digest)
+	bootSpec.ImageDigest = digest
+	handler.RecordEvent(keys.ResolvedImageDigest,
+		fmt.Sprintf("Resolved image %s:%s to %s", image, bootSpec.Version, digest), nil)
+	return true, nil
+}
+
+// imagePullKeychain return the registry credentials of the image pull secrets of the boot's pods,
+// the missing secrets and the secrets of other types are skipped
+func (handler *BootHandler) imagePullKeychain(boot *appv1.Boot) (registry.Keychain, error) {
+	var cfgSecrets []corev1.LocalObjectReference
+	if handler.Config.AppSpec.PodSpec != nil {
+		cfgSecrets = handler.Config.AppSpec.PodSpec.ImagePullSecrets
+	}
+
+	keychain := make(registry.Keychain)
+	for _, ref := range PodImagePullSecrets(boot, handler.Config.AppSpec, cfgSecrets) {
+		secret := &corev1.Secret{}
+		err := handler.Client.Get(context.TODO(), types.NamespacedName{Namespace: boot.Namespace, Name: ref.Name}, secret)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		var secretKeychain registry.Keychain
+		switch secret.Type {
+		case corev1.SecretTypeDockerConfigJson:
+			secretKeychain, err = registry.ParseDockerConfig(secret.Data[corev1.DockerConfigJsonKey], true)
+		case corev1.SecretTypeDockercfg:
+			secretKeychain, err = registry.ParseDockerConfig(secret.Data[corev1.DockerConfigKey], false)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid image pull secret %s: %v", ref.Name, err)
+		}
+
+		// The former secrets take precedence, as the kubelet tries them in order
+		for host, cred := range secretKeychain {
+			if _, found := keychain[host]; !found {
+				keychain[host] = cred
+			}
+		}
+	}
+	return keychain, nil
+}
@@ -80,10 +80,15 @@ func (handler *BootHandler) ReconcileHpa() (reconcile.Result, error) {
 				changed = true
 			}
 
+			if overridesChanged(hpaFound, handler.hpaOverride()) {
+				changed = true
+			}
+
 			if changed {
 				logger.Info("HorizontalPodAutoscaler is too old, need to update",
 					"old", hpaFound.Spec, "new", expectHpa.Spec)
 				hpaFound.Spec = expectHpa.Spec
+				setOverridesHash(hpaFound, OverridesHash(handler.hpaOverride()))
 				err := c.Update(context.TODO(), hpaFound)
 				if err != nil {
 					logger.Error(err, "Failed to update HorizontalPodAutoscaler.")
@@ -126,5 +131,7 @@ func (handler *BootHandler) NewHpa() *autoscaling.HorizontalPodAutoscaler {
 
 	_ = controllerutil.SetControllerReference(handler.OperatorBoot, hpa, handler.Scheme)
 
+	handler.applyOverride(hpa, handler.hpaOverride(), handler.overridePolicy().Hpa)
+
 	return hpa
 }
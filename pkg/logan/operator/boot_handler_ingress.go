@@ -0,0 +1,342 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"github.com/logancloud/logan-app-operator/pkg/logan"
+	loganMetrics "github.com/logancloud/logan-app-operator/pkg/logan/metrics"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sort"
+)
+
+var (
+	routeGVK     = schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"}
+	routeListGVK = schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "RouteList"}
+)
+
+// NewIngressObject return an empty Ingress, or an empty Route on OpenShift, for watching and getting.
+// Route is handled as unstructured, so that the operator does not depend on OpenShift's api.
+func NewIngressObject() runtime.Object {
+	if logan.RouteEnabled {
+		route := &unstructured.Unstructured{}
+		route.SetGroupVersionKind(routeGVK)
+		return route
+	}
+	return &networkingv1beta1.Ingress{}
+}
+
+// NewIngress return a new created Ingress object of the host
+func (handler *BootHandler) NewIngress(host string) *networkingv1beta1.Ingress {
+	boot := handler.Boot
+
+	ingress := &networkingv1beta1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "networking.k8s.io/v1beta1",
+			Kind:       "Ingress",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      IngressName(boot),
+			Namespace: boot.Namespace,
+			Labels:    ServiceLabels(boot),
+		},
+		Spec: networkingv1beta1.IngressSpec{
+			Rules: []networkingv1beta1.IngressRule{
+				{
+					Host: host,
+					IngressRuleValue: networkingv1beta1.IngressRuleValue{
+						HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+							Paths: []networkingv1beta1.HTTPIngressPath{
+								{
+									Backend: networkingv1beta1.IngressBackend{
+										ServiceName: boot.Name,
+										ServicePort: intstr.FromInt(int(boot.Spec.Port)),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if boot.Spec.TLSSecret != "" {
+		ingress.Spec.TLS = []networkingv1beta1.IngressTLS{
+			{
+				Hosts:      []string{host},
+				SecretName: boot.Spec.TLSSecret,
+			},
+		}
+	}
+
+	// Set Boot instance as the owner and controller
+	_ = controllerutil.SetControllerReference(handler.OperatorBoot, ingress, handler.Scheme)
+
+	return ingress
+}
+
+// NewRoute return a new created OpenShift Route object of the host.
+// Route can not reference a secret, the certificate and key of the TLSSecret are copied into the Route.
+func (handler *BootHandler) NewRoute(host string) (*unstructured.Unstructured, error) {
+	boot := handler.Boot
+
+	spec := map[string]interface{}{
+		"host": host,
+		"to": map[string]interface{}{
+			"kind":   "Service",
+			"name":   boot.Name,
+			"weight": int64(defaultWeight),
+		},
+		"port": map[string]interface{}{
+			"targetPort": HttpPortName,
+		},
+	}
+
+	if boot.Spec.TLSSecret != "" {
+		secret := &corev1.Secret{}
+		err := handler.Client.Get(context.TODO(),
+			types.NamespacedName{Name: boot.Spec.TLSSecret, Namespace: boot.Namespace}, secret)
+		if err != nil {
+			return nil, err
+		}
+
+		spec["tls"] = map[string]interface{}{
+			"termination":                   "edge",
+			"insecureEdgeTerminationPolicy": "Redirect",
+			"certificate":                   string(secret.Data[corev1.TLSCertKey]),
+			"key":                           string(secret.Data[corev1.TLSPrivateKeyKey]),
+		}
+	}
+
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(routeGVK)
+	route.SetName(IngressName(boot))
+	route.SetNamespace(boot.Namespace)
+	route.SetLabels(ServiceLabels(boot))
+	route.Object["spec"] = spec
+
+	// Set Boot instance as the owner and controller
+	_ = controllerutil.SetControllerReference(handler.OperatorBoot, route, handler.Scheme)
+
+	return route, nil
+}
+
+// newIngressObject return the desired Ingress, or Route on OpenShift, of the host
+func (handler *BootHandler) newIngressObject(host string) (runtime.Object, error) {
+	if logan.RouteEnabled {
+		return handler.NewRoute(host)
+	}
+	return handler.NewIngress(host), nil
+}
+
+// reconcileUpdateIngress handle create/update/delete of the boot's Ingress, or Route on OpenShift.
+// The boot is exposed only when both of its subDomain and the domainTemplate of settings are set.
+func (handler *BootHandler) reconcileUpdateIngress() (reconcile.Result, bool, error) {
+	boot := handler.Boot
+	logger := handler.Logger
+	c := handler.Client
+
+	host := IngressHost(boot, handler.Config.AppSpec)
+	ingressName := IngressName(boot)
+
+	found := NewIngressObject()
+	err := c.Get(context.TODO(), types.NamespacedName{Name: ingressName, Namespace: boot.Namespace}, found)
+	if err != nil && !errors.IsNotFound(err) {
+		msg := fmt.Sprintf("Failed to get Ingress: %s", ingressName)
+		logger.Error(err, msg)
+		loganMetrics.UpdateReconcileErrors(boot.Kind,
+			loganMetrics.RECONCILE_UPDATE_STAGE,
+			loganMetrics.RECONCILE_GET_INGRESS_SUBSTAGE,
+			boot.Name)
+		handler.RecordEvent(keys.FailedGetIngress, msg, err)
+		return reconcile.Result{Requeue: true}, true, err
+	}
+	exist := err == nil
+
+	// 1. Not exposed: delete the Ingress created by the operator
+	if host == "" {
+		if !exist || !metav1.IsControlledBy(found.(metav1.Object), handler.OperatorBoot) {
+			return reconcile.Result{}, false, nil
+		}
+
+		logger.Info("Deleting Ingress", "ingress", ingressName)
+		err = c.Delete(context.TODO(), found)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to delete Ingress: %s", ingressName)
+			logger.Error(err, msg)
+			loganMetrics.UpdateReconcileErrors(boot.Kind,
+				loganMetrics.RECONCILE_UPDATE_STAGE,
+				loganMetrics.RECONCILE_DELETE_INGRESS_SUBSTAGE,
+				boot.Name)
+			handler.RecordEvent(keys.FailedDeleteIngress, msg, err)
+			return reconcile.Result{Requeue: true}, true, err
+		}
+		handler.RecordEvent(keys.DeletedIngress, fmt.Sprintf("Deleted Ingress: %s", ingressName), nil)
+		return reconcile.Result{Requeue: true}, true, nil
+	}
+
+	expected, err := handler.newIngressObject(host)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to get TLS secret: %s", boot.Spec.TLSSecret)
+		logger.Error(err, msg)
+		loganMetrics.UpdateReconcileErrors(boot.Kind,
+			loganMetrics.RECONCILE_UPDATE_STAGE,
+			loganMetrics.RECONCILE_UPDATE_INGRESS_SUBSTAGE,
+			boot.Name)
+		handler.RecordEvent(keys.FailedUpdateIngress, msg, err)
+		return reconcile.Result{Requeue: true}, true, err
+	}
+
+	// 2. Not found: create it
+	if !exist {
+		logger.Info("Creating Ingress", "ingress", ingressName, "host", host)
+		err = c.Create(context.TODO(), expected)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to create Ingress: %s", ingressName)
+			logger.Error(err, msg)
+			loganMetrics.UpdateReconcileErrors(boot.Kind,
+				loganMetrics.RECONCILE_UPDATE_STAGE,
+				loganMetrics.RECONCILE_CREATE_INGRESS_SUBSTAGE,
+				boot.Name)
+			handler.RecordEvent(keys.FailedCreateIngress, msg, err)
+			return reconcile.Result{Requeue: true}, true, err
+		}
+		handler.RecordEvent(keys.CreatedIngress, fmt.Sprintf("Created Ingress: %s", ingressName), nil)
+		return reconcile.Result{Requeue: true}, true, nil
+	}
+
+	// 3. An Ingress or Route of the same name not created by the operator is never adopted
+	if !metav1.IsControlledBy(found.(metav1.Object), handler.OperatorBoot) {
+		msg := fmt.Sprintf("Failed to update Ingress: %s, which is not controlled by the boot", ingressName)
+		err = fmt.Errorf("ingress %s is not controlled by the boot %s", ingressName, boot.Name)
+		logger.Error(err, msg)
+		loganMetrics.UpdateReconcileErrors(boot.Kind,
+			loganMetrics.RECONCILE_UPDATE_STAGE,
+			loganMetrics.RECONCILE_UPDATE_INGRESS_SUBSTAGE,
+			boot.Name)
+		handler.RecordEvent(keys.FailedUpdateIngress, msg, err)
+		return reconcile.Result{Requeue: true}, true, err
+	}
+
+	// 4. Check the fields
+	if !handler.updateIngressObject(found, expected) {
+		return reconcile.Result{}, false, nil
+	}
+
+	err = c.Update(context.TODO(), found)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to update Ingress: %s", ingressName)
+		logger.Error(err, msg)
+		loganMetrics.UpdateReconcileErrors(boot.Kind,
+			loganMetrics.RECONCILE_UPDATE_STAGE,
+			loganMetrics.RECONCILE_UPDATE_INGRESS_SUBSTAGE,
+			boot.Name)
+		handler.RecordEvent(keys.FailedUpdateIngress, msg, err)
+		return reconcile.Result{Requeue: true}, true, err
+	}
+	handler.RecordEvent(keys.UpdatedIngress, fmt.Sprintf("Updated Ingress: %s", ingressName), nil)
+
+	return reconcile.Result{Requeue: true}, true, nil
+}
+
+// updateIngressObject update the found Ingress or Route with the expected one, return true if updated
+func (handler *BootHandler) updateIngressObject(found, expected runtime.Object) bool {
+	logger := handler.Logger
+	reason := "Updating Ingress"
+	updated := false
+
+	// Check spec
+	switch foundObj := found.(type) {
+	case *networkingv1beta1.Ingress:
+		expectedObj := expected.(*networkingv1beta1.Ingress)
+		if !reflect.DeepEqual(foundObj.Spec.Rules, expectedObj.Spec.Rules) {
+			logger.Info(reason, "type", "rules", "ingress", foundObj.Name,
+				"old", foundObj.Spec.Rules, "new", expectedObj.Spec.Rules)
+			foundObj.Spec.Rules = expectedObj.Spec.Rules
+			updated = true
+		}
+		if !reflect.DeepEqual(foundObj.Spec.TLS, expectedObj.Spec.TLS) {
+			logger.Info(reason, "type", "tls", "ingress", foundObj.Name,
+				"old", foundObj.Spec.TLS, "new", expectedObj.Spec.TLS)
+			foundObj.Spec.TLS = expectedObj.Spec.TLS
+			updated = true
+		}
+	case *unstructured.Unstructured:
+		// Only compare the fields set by the operator, the others are defaulted by OpenShift.
+		expectedSpec, _, _ := unstructured.NestedMap(expected.(*unstructured.Unstructured).Object, "spec")
+		foundSpec, _, _ := unstructured.NestedMap(foundObj.Object, "spec")
+		if foundSpec == nil {
+			foundSpec = make(map[string]interface{})
+		}
+		for _, field := range []string{"host", "to", "port", "tls"} {
+			expectedField, expectedFound := expectedSpec[field]
+			foundField, fieldFound := foundSpec[field]
+			if expectedFound == fieldFound && reflect.DeepEqual(foundField, expectedField) {
+				continue
+			}
+
+			// Not log the tls field, which contains the key
+			logger.Info(reason, "type", field, "route", foundObj.GetName())
+			if expectedFound {
+				foundSpec[field] = expectedField
+			} else {
+				delete(foundSpec, field)
+			}
+			updated = true
+		}
+		if updated {
+			_ = unstructured.SetNestedMap(foundObj.Object, foundSpec, "spec")
+		}
+	}
+
+	return updated
+}
+
+// listRuntimeIngressNames return the sorted names of the boot's Ingress, or Route on OpenShift
+func (handler *BootHandler) listRuntimeIngressNames() ([]string, error) {
+	boot := handler.Boot
+	c := handler.Client
+
+	opts := []client.ListOption{
+		client.InNamespace(boot.Namespace),
+		client.MatchingLabels(ServiceLabels(boot)),
+	}
+
+	names := make([]string, 0)
+	if logan.RouteEnabled {
+		routeList := &unstructured.UnstructuredList{}
+		routeList.SetGroupVersionKind(routeListGVK)
+		err := c.List(context.TODO(), routeList, opts...)
+		if err != nil {
+			return nil, err
+		}
+		for _, route := range routeList.Items {
+			names = append(names, route.GetName())
+		}
+	} else {
+		ingressList := &networkingv1beta1.IngressList{}
+		err := c.List(context.TODO(), ingressList, opts...)
+		if err != nil {
+			return nil, err
+		}
+		for _, ingress := range ingressList.Items {
+			names = append(names, ingress.Name)
+		}
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,120 @@
+package operator
+
+import (
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sort"
+	"strings"
+)
+
+// reservedLabels are the label keys managed by the operator, used by the selectors of the workloads and Services
+var reservedLabels = map[string]bool{
+	"app":            true,
+	"havok/type":     true,
+	"logan/env":      true,
+	keys.BootNameKey: true,
+	keys.BootTypeKey: true,
+	keys.TrackKey:    true,
+	keys.ColorKey:    true,
+	keys.SharedKey:   true,
+}
+
+// IsReservedLabel return whether the label key is managed by the operator, which can not be set by the boot's metadata
+func IsReservedLabel(key string) bool {
+	return reservedLabels[key] || strings.HasPrefix(key, keys.OperatorAnnotationKeyPrefix)
+}
+
+// IsReservedAnnotation return whether the annotation key is managed by the operator, such as the prometheus annotations,
+// which can not be set by the boot's metadata
+func IsReservedAnnotation(key string) bool {
+	return strings.HasPrefix(key, keys.OperatorAnnotationKeyPrefix) ||
+		strings.HasPrefix(key, keys.PrometheusAnnotationKeyPrefix)
+}
+
+// applyMetadata apply the labels and annotations of the boot's metadata to the object, return true if changed.
+// The applied keys are recorded in the object's annotations, so the keys removed from the boot's metadata are
+// removed from the object, while the labels and annotations added by others are kept.
+func applyMetadata(objMeta *metav1.ObjectMeta, metadata *appv1.BootMetadata) bool {
+	var labels, annotations map[string]string
+	if metadata != nil {
+		labels = metadata.Labels
+		annotations = metadata.Annotations
+	}
+
+	labelValues, labelKeys, labelsChanged := applyMetadataKeys(objMeta.Labels, labels,
+		objMeta.Annotations[keys.BootMetadataLabelsAnnotationKey], IsReservedLabel)
+	annotationValues, annotationKeys, annotationsChanged := applyMetadataKeys(objMeta.Annotations, annotations,
+		objMeta.Annotations[keys.BootMetadataAnnotationsAnnotationKey], IsReservedAnnotation)
+	objMeta.Labels = labelValues
+	objMeta.Annotations = annotationValues
+
+	recordChanged := setMetadataRecord(objMeta, keys.BootMetadataLabelsAnnotationKey, labelKeys)
+	if setMetadataRecord(objMeta, keys.BootMetadataAnnotationsAnnotationKey, annotationKeys) {
+		recordChanged = true
+	}
+
+	return labelsChanged || annotationsChanged || recordChanged
+}
+
+// applyMetadataKeys return a copy of values with the desired keys set and the recorded keys not desired removed,
+// the sorted desired keys joined by ",", and whether the values are changed. The reserved keys are skipped.
+func applyMetadataKeys(values map[string]string, desired map[string]string, recorded string,
+	reserved func(string) bool) (map[string]string, string, bool) {
+	ret := make(map[string]string, len(values)+len(desired))
+	for k, v := range values {
+		ret[k] = v
+	}
+
+	changed := false
+	desiredKeys := make([]string, 0, len(desired))
+	for k, v := range desired {
+		if reserved(k) {
+			continue
+		}
+		desiredKeys = append(desiredKeys, k)
+		if old, found := ret[k]; !found || old != v {
+			ret[k] = v
+			changed = true
+		}
+	}
+	sort.Strings(desiredKeys)
+
+	if recorded != "" {
+		for _, k := range strings.Split(recorded, ",") {
+			if _, found := desired[k]; found || reserved(k) {
+				continue
+			}
+			if _, found := ret[k]; found {
+				delete(ret, k)
+				changed = true
+			}
+		}
+	}
+
+	if len(ret) == 0 && values == nil {
+		ret = nil
+	}
+	return ret, strings.Join(desiredKeys, ","), changed
+}
+
+// setMetadataRecord set the record annotation of the applied keys, removed if empty. Return true if changed.
+func setMetadataRecord(objMeta *metav1.ObjectMeta, recordKey string, appliedKeys string) bool {
+	old, found := objMeta.Annotations[recordKey]
+	if appliedKeys == "" {
+		if !found {
+			return false
+		}
+		delete(objMeta.Annotations, recordKey)
+		return true
+	}
+
+	if found && old == appliedKeys {
+		return false
+	}
+	if objMeta.Annotations == nil {
+		objMeta.Annotations = make(map[string]string)
+	}
+	objMeta.Annotations[recordKey] = appliedKeys
+	return true
+}
@@ -0,0 +1,122 @@
+package operator
+
+import (
+	"fmt"
+	"github.com/logancloud/logan-app-operator/pkg/logan/config"
+	loganMetrics "github.com/logancloud/logan-app-operator/pkg/logan/metrics"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
+	"hash/fnv"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+// OverridesHash return the hash of the override patch, empty if no patch
+func OverridesHash(patch *runtime.RawExtension) string {
+	if patch == nil || len(patch.Raw) == 0 {
+		return ""
+	}
+
+	hasher := fnv.New32a()
+	_, _ = hasher.Write(patch.Raw)
+	return rand.SafeEncodeString(fmt.Sprint(hasher.Sum32()))
+}
+
+// workloadOverride return the override patch of the boot's Deployment or StatefulSet
+func (handler *BootHandler) workloadOverride() *runtime.RawExtension {
+	if overrides := handler.Boot.Spec.Overrides; overrides != nil {
+		return overrides.Workload
+	}
+	return nil
+}
+
+// serviceOverride return the override patch of the boot's app Service
+func (handler *BootHandler) serviceOverride() *runtime.RawExtension {
+	if overrides := handler.Boot.Spec.Overrides; overrides != nil {
+		return overrides.Service
+	}
+	return nil
+}
+
+// hpaOverride return the override patch of the boot's HorizontalPodAutoscaler
+func (handler *BootHandler) hpaOverride() *runtime.RawExtension {
+	if overrides := handler.Boot.Spec.Overrides; overrides != nil {
+		return overrides.Hpa
+	}
+	return nil
+}
+
+// overridePolicy return the paths allowed to be patched by the boot's overrides, none is allowed if the config has no
+// overrides
+func (handler *BootHandler) overridePolicy() *config.OverridePolicy {
+	if handler.Config.AppSpec.Overrides != nil {
+		return handler.Config.AppSpec.Overrides
+	}
+	return &config.OverridePolicy{}
+}
+
+// checkOverride return the error if any path of the override patch is not allowed
+func checkOverride(patch []byte, allowed []string) error {
+	paths, err := util.PatchPaths(patch)
+	if err != nil {
+		return err
+	}
+	for _, p := range paths {
+		if !util.PathAllowed(p, allowed) {
+			return fmt.Errorf("override path %s is not allowed", p)
+		}
+	}
+	return nil
+}
+
+// applyOverride apply the override patch to the generated object, and record the patch's hash in its annotations.
+// obj must be a pointer to a typed api object. The patch is skipped with a FailedOverride event if its paths are not
+// allowed anymore, such as the config is changed, or it fails. Its hash is still recorded to avoid rebuilding the
+// object again and again.
+func (handler *BootHandler) applyOverride(obj metav1.Object, patch *runtime.RawExtension, allowed []string) {
+	boot := handler.Boot
+	hash := OverridesHash(patch)
+	if hash == "" {
+		return
+	}
+
+	err := checkOverride(patch.Raw, allowed)
+	if err == nil {
+		err = util.StrategicMergePatch(obj, patch.Raw)
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Failed to apply overrides: %s", obj.GetName())
+		handler.Logger.Error(err, msg)
+		loganMetrics.UpdateReconcileErrors(boot.Kind,
+			loganMetrics.RECONCILE_UPDATE_STAGE,
+			loganMetrics.RECONCILE_APPLY_OVERRIDE_SUBSTAGE,
+			boot.Name)
+		handler.RecordEvent(keys.FailedOverride, msg, err)
+	}
+
+	setOverridesHash(obj, hash)
+}
+
+// setOverridesHash set the hash of the applied overrides to the object's annotations, removed if empty
+func setOverridesHash(obj metav1.Object, hash string) {
+	annotations := obj.GetAnnotations()
+	if hash == "" {
+		if _, found := annotations[keys.BootOverridesHashAnnotationKey]; found {
+			delete(annotations, keys.BootOverridesHashAnnotationKey)
+			obj.SetAnnotations(annotations)
+		}
+		return
+	}
+
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[keys.BootOverridesHashAnnotationKey] = hash
+	obj.SetAnnotations(annotations)
+}
+
+// overridesChanged return whether the override patch is changed since it was applied to the object
+func overridesChanged(obj metav1.Object, patch *runtime.RawExtension) bool {
+	return obj.GetAnnotations()[keys.BootOverridesHashAnnotationKey] != OverridesHash(patch)
+}
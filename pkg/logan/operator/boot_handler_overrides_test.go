@@ -0,0 +1,68 @@
+package operator
+
+import (
+	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+var _ = Describe("Overrides", func() {
+	allowed := []string{"spec.externalTrafficPolicy", "metadata.annotations"}
+
+	DescribeTable("checkOverride allows only the paths of the config",
+		func(patch string, valid bool) {
+			err := checkOverride([]byte(patch), allowed)
+			if valid {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("allowed", `{"spec":{"externalTrafficPolicy":"Local"}}`, true),
+		Entry("under the allowed path", `{"metadata":{"annotations":{"a":"b"}}}`, true),
+		Entry("not allowed", `{"spec":{"type":"NodePort"}}`, false),
+		Entry("one of the paths not allowed", `{"spec":{"externalTrafficPolicy":"Local","selector":{}}}`, false),
+		Entry("invalid json", `{"spec":`, false),
+	)
+
+	DescribeTable("applyOverride patches the object, or skips the patch with an event",
+		func(patch string, trafficPolicy corev1.ServiceExternalTrafficPolicyType, failed bool) {
+			handler := newTestHandler(newTestBoot("overrides"), nil)
+			recorder := handler.Recorder.(*record.FakeRecorder)
+			svc := &corev1.Service{}
+			svc.Name = "overrides"
+			raw := &runtime.RawExtension{Raw: []byte(patch)}
+
+			handler.applyOverride(svc, raw, allowed)
+			Expect(svc.Spec.ExternalTrafficPolicy).To(Equal(trafficPolicy))
+			// the hash is recorded even if the patch fails, so the object is not rebuilt again and again
+			Expect(svc.Annotations[keys.BootOverridesHashAnnotationKey]).To(Equal(OverridesHash(raw)))
+			Expect(overridesChanged(svc, raw)).To(BeFalse())
+
+			if failed {
+				Expect(recorder.Events).To(Receive(ContainSubstring(keys.FailedOverride)))
+			} else {
+				Expect(recorder.Events).NotTo(Receive())
+			}
+		},
+		Entry("applied", `{"spec":{"externalTrafficPolicy":"Local"}}`,
+			corev1.ServiceExternalTrafficPolicyTypeLocal, false),
+		Entry("path not allowed", `{"spec":{"externalTrafficPolicy":"Local","type":"NodePort"}}`,
+			corev1.ServiceExternalTrafficPolicyType(""), true),
+		Entry("patch fails", `{"spec":{"externalTrafficPolicy":1}}`,
+			corev1.ServiceExternalTrafficPolicyType(""), true),
+	)
+
+	It("test no patch", func() {
+		handler := newTestHandler(newTestBoot("overrides"), nil)
+		svc := &corev1.Service{}
+		handler.applyOverride(svc, nil, allowed)
+		Expect(svc.Annotations).To(BeNil())
+		Expect(overridesChanged(svc, nil)).To(BeFalse())
+		Expect(overridesChanged(svc, &runtime.RawExtension{Raw: []byte(`{}`)})).To(BeTrue())
+	})
+})
@@ -0,0 +1,223 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	loganMetrics "github.com/logancloud/logan-app-operator/pkg/logan/metrics"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// PdbName return name for the created PodDisruptionBudget
+func PdbName(boot *appv1.Boot) string {
+	return boot.Name
+}
+
+// bootDisruption return the boot's disruption, fallback to the config's
+func (handler *BootHandler) bootDisruption() *appv1.BootDisruption {
+	if handler.Boot.Spec.Disruption != nil {
+		return handler.Boot.Spec.Disruption
+	}
+	return handler.Config.AppSpec.Disruption
+}
+
+// minExpectedReplicas return the lowest replicas the boot can run with, the minReplicas when HPA is enabled
+func (handler *BootHandler) minExpectedReplicas() int32 {
+	boot := handler.Boot
+	if boot.Spec.Hpa != nil && boot.Spec.Hpa.Enable {
+		return int32Value(boot.Spec.Hpa.MinReplicas, 1)
+	}
+	return int32Value(boot.Spec.Replicas, 1)
+}
+
+// safeDisruption return the minAvailable and maxUnavailable of the PodDisruptionBudget.
+// If the disruption would block the eviction of all the pods when running with the replicas,
+// maxUnavailable 1 is used instead, so that node drains are never blocked by the boot.
+func safeDisruption(disruption *appv1.BootDisruption, replicas int32) (*intstr.IntOrString, *intstr.IntOrString) {
+	maxUnavailableOne := intstr.FromInt(1)
+	if replicas <= 1 {
+		return nil, &maxUnavailableOne
+	}
+
+	if disruption.MinAvailable != nil {
+		// Round up as the disruption controller does
+		minAvailable, err := intstr.GetValueFromIntOrPercent(disruption.MinAvailable, int(replicas), true)
+		if err != nil || minAvailable >= int(replicas) {
+			return nil, &maxUnavailableOne
+		}
+		value := *disruption.MinAvailable
+		return &value, nil
+	}
+
+	if disruption.MaxUnavailable != nil {
+		maxUnavailable, err := intstr.GetValueFromIntOrPercent(disruption.MaxUnavailable, int(replicas), true)
+		if err != nil || maxUnavailable < 1 {
+			return nil, &maxUnavailableOne
+		}
+		value := *disruption.MaxUnavailable
+		return nil, &value
+	}
+
+	return nil, &maxUnavailableOne
+}
+
+// NewPodDisruptionBudget return a new created PodDisruptionBudget object, nil if the boot has no disruption
+func (handler *BootHandler) NewPodDisruptionBudget() *policyv1beta1.PodDisruptionBudget {
+	boot := handler.Boot
+	disruption := handler.bootDisruption()
+	if disruption == nil {
+		return nil
+	}
+
+	minAvailable, maxUnavailable := safeDisruption(disruption, handler.minExpectedReplicas())
+
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "policy/v1beta1",
+			Kind:       "PodDisruptionBudget",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PdbName(boot),
+			Namespace: boot.Namespace,
+			Labels:    WorkloadLabels(boot),
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MinAvailable:   minAvailable,
+			MaxUnavailable: maxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: PodLabels(boot),
+			},
+		},
+	}
+
+	// Set Boot instance as the owner and controller
+	_ = controllerutil.SetControllerReference(handler.OperatorBoot, pdb, handler.Scheme)
+
+	return pdb
+}
+
+// reconcileUpdatePdb handle create/update/delete of the boot's PodDisruptionBudget
+func (handler *BootHandler) reconcileUpdatePdb() (reconcile.Result, bool, error) {
+	boot := handler.Boot
+	logger := handler.Logger
+	c := handler.Client
+
+	pdbName := PdbName(boot)
+	found := &policyv1beta1.PodDisruptionBudget{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: pdbName, Namespace: boot.Namespace}, found)
+	if err != nil && !errors.IsNotFound(err) {
+		msg := fmt.Sprintf("Failed to get PodDisruptionBudget: %s", pdbName)
+		logger.Error(err, msg)
+		loganMetrics.UpdateReconcileErrors(boot.Kind,
+			loganMetrics.RECONCILE_UPDATE_STAGE,
+			loganMetrics.RECONCILE_GET_PDB_SUBSTAGE,
+			boot.Name)
+		handler.RecordEvent(keys.FailedGetPodDisruptionBudget, msg, err)
+		return reconcile.Result{Requeue: true}, true, err
+	}
+	exist := err == nil
+
+	expected := handler.NewPodDisruptionBudget()
+
+	// 1. No disruption: delete the PodDisruptionBudget created by the operator
+	if expected == nil {
+		if !exist || !metav1.IsControlledBy(found, handler.OperatorBoot) {
+			return reconcile.Result{}, false, nil
+		}
+
+		logger.Info("Deleting PodDisruptionBudget", "pdb", pdbName)
+		err = c.Delete(context.TODO(), found)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to delete PodDisruptionBudget: %s", pdbName)
+			logger.Error(err, msg)
+			loganMetrics.UpdateReconcileErrors(boot.Kind,
+				loganMetrics.RECONCILE_UPDATE_STAGE,
+				loganMetrics.RECONCILE_DELETE_PDB_SUBSTAGE,
+				boot.Name)
+			handler.RecordEvent(keys.FailedDeletePodDisruptionBudget, msg, err)
+			return reconcile.Result{Requeue: true}, true, err
+		}
+		handler.RecordEvent(keys.DeletedPodDisruptionBudget,
+			fmt.Sprintf("Deleted PodDisruptionBudget: %s", pdbName), nil)
+		return reconcile.Result{Requeue: true}, true, nil
+	}
+
+	// 2. Not found: create it
+	if !exist {
+		logger.Info("Creating PodDisruptionBudget", "pdb", pdbName,
+			"minAvailable", expected.Spec.MinAvailable, "maxUnavailable", expected.Spec.MaxUnavailable)
+		err = c.Create(context.TODO(), expected)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to create PodDisruptionBudget: %s", pdbName)
+			logger.Error(err, msg)
+			loganMetrics.UpdateReconcileErrors(boot.Kind,
+				loganMetrics.RECONCILE_UPDATE_STAGE,
+				loganMetrics.RECONCILE_CREATE_PDB_SUBSTAGE,
+				boot.Name)
+			handler.RecordEvent(keys.FailedCreatePodDisruptionBudget, msg, err)
+			return reconcile.Result{Requeue: true}, true, err
+		}
+		handler.RecordEvent(keys.CreatedPodDisruptionBudget,
+			fmt.Sprintf("Created PodDisruptionBudget: %s", pdbName), nil)
+		return reconcile.Result{Requeue: true}, true, nil
+	}
+
+	// 3. Check the fields
+	updated := false
+	reason := "Updating PodDisruptionBudget"
+
+	if len(found.OwnerReferences) == 0 {
+		logger.Info(reason, "type", "ownerReferences", "pdb", pdbName)
+		found.OwnerReferences = expected.OwnerReferences
+		updated = true
+	}
+
+	if !reflect.DeepEqual(found.Spec.MinAvailable, expected.Spec.MinAvailable) {
+		logger.Info(reason, "type", "minAvailable", "pdb", pdbName,
+			"old", found.Spec.MinAvailable, "new", expected.Spec.MinAvailable)
+		found.Spec.MinAvailable = expected.Spec.MinAvailable
+		updated = true
+	}
+
+	if !reflect.DeepEqual(found.Spec.MaxUnavailable, expected.Spec.MaxUnavailable) {
+		logger.Info(reason, "type", "maxUnavailable", "pdb", pdbName,
+			"old", found.Spec.MaxUnavailable, "new", expected.Spec.MaxUnavailable)
+		found.Spec.MaxUnavailable = expected.Spec.MaxUnavailable
+		updated = true
+	}
+
+	if !reflect.DeepEqual(found.Spec.Selector, expected.Spec.Selector) {
+		logger.Info(reason, "type", "selector", "pdb", pdbName,
+			"old", found.Spec.Selector, "new", expected.Spec.Selector)
+		found.Spec.Selector = expected.Spec.Selector
+		updated = true
+	}
+
+	if !updated {
+		return reconcile.Result{}, false, nil
+	}
+
+	// PodDisruptionBudget's spec is mutable since kubernetes 1.15
+	err = c.Update(context.TODO(), found)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to update PodDisruptionBudget: %s", pdbName)
+		logger.Error(err, msg)
+		loganMetrics.UpdateReconcileErrors(boot.Kind,
+			loganMetrics.RECONCILE_UPDATE_STAGE,
+			loganMetrics.RECONCILE_UPDATE_PDB_SUBSTAGE,
+			boot.Name)
+		handler.RecordEvent(keys.FailedUpdatePodDisruptionBudget, msg, err)
+		return reconcile.Result{Requeue: true}, true, err
+	}
+	handler.RecordEvent(keys.UpdatedPodDisruptionBudget, fmt.Sprintf("Updated PodDisruptionBudget: %s", pdbName), nil)
+
+	return reconcile.Result{Requeue: true}, true, nil
+}
@@ -0,0 +1,41 @@
+package operator
+
+import (
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var _ = Describe("PodDisruptionBudget", func() {
+	intOrString := func(s string) *intstr.IntOrString {
+		v := intstr.Parse(s)
+		return &v
+	}
+
+	DescribeTable("safeDisruption never blocks the eviction of all the pods",
+		func(disruption appv1.BootDisruption, replicas int32, minAvailable, maxUnavailable string) {
+			min, max := safeDisruption(&disruption, replicas)
+			if minAvailable == "" {
+				Expect(min).To(BeNil())
+			} else {
+				Expect(min.String()).To(Equal(minAvailable))
+			}
+			if maxUnavailable == "" {
+				Expect(max).To(BeNil())
+			} else {
+				Expect(max.String()).To(Equal(maxUnavailable))
+			}
+		},
+		Entry("single replica", appv1.BootDisruption{MinAvailable: intOrString("1")}, int32(1), "", "1"),
+		Entry("minAvailable", appv1.BootDisruption{MinAvailable: intOrString("2")}, int32(3), "2", ""),
+		Entry("minAvailable of all the replicas", appv1.BootDisruption{MinAvailable: intOrString("3")}, int32(3), "", "1"),
+		Entry("minAvailable percent rounded up to all the replicas",
+			appv1.BootDisruption{MinAvailable: intOrString("90%")}, int32(3), "", "1"),
+		Entry("minAvailable percent", appv1.BootDisruption{MinAvailable: intOrString("50%")}, int32(4), "50%", ""),
+		Entry("maxUnavailable", appv1.BootDisruption{MaxUnavailable: intOrString("25%")}, int32(4), "", "25%"),
+		Entry("maxUnavailable of none", appv1.BootDisruption{MaxUnavailable: intOrString("0")}, int32(4), "", "1"),
+		Entry("empty", appv1.BootDisruption{}, int32(4), "", "1"),
+	)
+})
@@ -0,0 +1,132 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	loganMetrics "github.com/logancloud/logan-app-operator/pkg/logan/metrics"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"strconv"
+	"strings"
+)
+
+const (
+	// rollbackChangeCausePrefix is the prefix of the change cause of the revision created by a rollback
+	rollbackChangeCausePrefix = "rollback from "
+)
+
+// ReconcileRollback restore the boot's spec from the revision of the rollback annotation, while the current
+// replicas and hpa are kept. The annotation is removed, and the change cause "rollback from N" is set to be
+// recorded in the new revision, N is the latest revision rolled back from.
+// Return true if the boot is changed and should be updated.
+func (handler *BootHandler) ReconcileRollback() (bool, error) {
+	logger := handler.Logger
+	boot := handler.Boot
+	bootSpec := handler.OperatorSpec
+	bootMeta := handler.OperatorMeta
+
+	idStr, found := bootMeta.Annotations[keys.BootRollbackToAnnotationKey]
+	if !found {
+		return false, nil
+	}
+
+	revisionLst, err := handler.Client.ListRevision(boot.Namespace, PodLabels(boot))
+	if err != nil {
+		logger.Error(err, "Failed to list revisions")
+		loganMetrics.UpdateReconcileErrors(boot.Kind,
+			loganMetrics.RECONCILE_ROLLBACK_STAGE,
+			loganMetrics.RECONCILE_LIST_REVISIONS_SUBSTAGE,
+			boot.Name)
+		return false, err
+	}
+
+	delete(bootMeta.Annotations, keys.BootRollbackToAnnotationKey)
+
+	id, err := strconv.Atoi(idStr)
+	var revision *appv1.BootRevision
+	if err == nil {
+		revision = revisionLst.SelectRevision(id)
+	}
+	if revision == nil {
+		msg := fmt.Sprintf("Failed to rollback Boot, revision %s not found", idStr)
+		logger.Info(msg)
+		loganMetrics.UpdateMainStageErrors(boot.Kind, loganMetrics.RECONCILE_ROLLBACK_STAGE, boot.Name)
+		handler.RecordEvent(keys.FailedRollbackBoot, msg, nil)
+		return true, nil
+	}
+
+	latestRevision := revisionLst.SelectLatestRevision()
+	if latestRevision == nil {
+		msg := fmt.Sprintf("Failed to rollback Boot to revision %d, the latest revision not found", id)
+		logger.Info(msg)
+		loganMetrics.UpdateMainStageErrors(boot.Kind, loganMetrics.RECONCILE_ROLLBACK_STAGE, boot.Name)
+		handler.RecordEvent(keys.FailedRollbackBoot, msg, nil)
+		return true, nil
+	}
+
+	latestId := latestRevision.GetRevisionId()
+	if latestId == id {
+		handler.RecordEvent(keys.RollbackBoot, fmt.Sprintf("Boot is already at revision %d", id), nil)
+		return true, nil
+	}
+
+	spec := revision.Spec.DeepCopy()
+	spec.Replicas = bootSpec.Replicas
+	spec.Hpa = bootSpec.Hpa
+	*bootSpec = *spec
+
+	handler.UpdateAnnotation(map[string]string{
+		keys.BootChangeCauseAnnotationKey: rollbackChangeCausePrefix + strconv.Itoa(latestId),
+	})
+
+	logger.Info("Rolling back Boot", "from", latestId, "to", id)
+	handler.RecordEvent(keys.RollbackBoot,
+		fmt.Sprintf("Rolling back Boot from revision %d to revision %d", latestId, id), nil)
+	return true, nil
+}
+
+// ReconcileRollbackUpdate rollback the boot by ReconcileRollback, and update the boot if it is changed.
+// Return true if the boot is updated and the reconcile should be requeued.
+func (handler *BootHandler) ReconcileRollbackUpdate() (reconcile.Result, bool, error) {
+	logger := handler.Logger
+	boot := handler.Boot
+
+	rolledBack, err := handler.ReconcileRollback()
+	if err != nil {
+		return reconcile.Result{Requeue: true}, true, err
+	}
+	if !rolledBack {
+		return reconcile.Result{}, false, nil
+	}
+
+	logger.Info("Updating Boot with Rollback")
+	err = handler.Client.Update(context.TODO(), handler.OperatorBoot.(runtime.Object))
+	if err != nil {
+		msg := "Failed to update Boot with Rollback"
+		logger.Info(msg, "boot", handler.OperatorBoot)
+		loganMetrics.UpdateMainStageErrors(boot.Kind,
+			loganMetrics.RECONCILE_ROLLBACK_STAGE,
+			boot.Name)
+		handler.RecordEvent(keys.FailedUpdateBootRollback, msg, err)
+		return reconcile.Result{Requeue: true}, true, nil
+	}
+	loganMetrics.UpdateBootRollbacks(boot.Kind, boot.Name)
+	handler.RecordEvent(keys.UpdatedBootRollback, "Updated Boot with Rollback", nil)
+	return reconcile.Result{Requeue: true}, true, nil
+}
+
+// rolledBackCondition return the message of the RolledBack condition from the boot's latest revision,
+// and whether the latest revision is created by a rollback.
+func rolledBackCondition(latestRevision *appv1.BootRevision) (string, bool) {
+	if latestRevision == nil {
+		return "", false
+	}
+
+	cause := latestRevision.Annotations[keys.BootChangeCauseAnnotationKey]
+	if !strings.HasPrefix(cause, rollbackChangeCausePrefix) {
+		return "", false
+	}
+	return fmt.Sprintf("Revision %d is a %s", latestRevision.GetRevisionId(), cause), true
+}
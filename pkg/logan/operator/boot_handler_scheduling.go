@@ -0,0 +1,283 @@
+package operator
+
+import (
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	"github.com/logancloud/logan-app-operator/pkg/logan/config"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"reflect"
+)
+
+const (
+	hostnameTopologyKey = "kubernetes.io/hostname"
+	// zoneTopologyKey is the zone label of the nodes in the supported kubernetes version
+	zoneTopologyKey = "failure-domain.beta.kubernetes.io/zone"
+)
+
+// podAntiAffinityTerm return the PodAffinityTerm matching the boot's pods in the topology
+func podAntiAffinityTerm(boot *appv1.Boot, topologyKey string) corev1.PodAffinityTerm {
+	return corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      keys.BootNameKey,
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   []string{boot.Name},
+				},
+			},
+		},
+		TopologyKey: topologyKey,
+	}
+}
+
+// topologySpreadKeys return the topology keys of the required and preferred pod anti-affinity,
+// from the boot's zoneAntiAffinity and topologySpreadConstraints. A key is required only by the `Required`
+// zoneAntiAffinity, the spread constraints are always preferred: as the required anti-affinity allows at most one
+// pod in a domain, `DoNotSchedule` would leave the pods pending once the replicas exceed the domains.
+func topologySpreadKeys(boot *appv1.Boot) ([]string, []string) {
+	requiredKeys := make(map[string]bool)
+	orderedKeys := make([]string, 0)
+	addKey := func(key string, required bool) {
+		if _, found := requiredKeys[key]; !found {
+			orderedKeys = append(orderedKeys, key)
+		}
+		requiredKeys[key] = requiredKeys[key] || required
+	}
+
+	switch boot.Spec.ZoneAntiAffinity {
+	case appv1.ZoneAntiAffinityRequired:
+		addKey(zoneTopologyKey, true)
+	case appv1.ZoneAntiAffinityPreferred:
+		addKey(zoneTopologyKey, false)
+	}
+
+	for _, constraint := range boot.Spec.TopologySpreadConstraints {
+		if constraint.TopologyKey == "" {
+			continue
+		}
+		addKey(constraint.TopologyKey, false)
+	}
+
+	required := make([]string, 0)
+	preferred := make([]string, 0)
+	for _, key := range orderedKeys {
+		if requiredKeys[key] {
+			required = append(required, key)
+		} else if key != hostnameTopologyKey {
+			// the hostname is always preferred
+			preferred = append(preferred, key)
+		}
+	}
+	return required, preferred
+}
+
+// placementClass return the boot's placement class of the config, nil if the boot has no placement or not found
+func (handler *BootHandler) placementClass() *config.PlacementClass {
+	placement := handler.Boot.Spec.Placement
+	if placement == "" || handler.Config.AppSpec.Placements == nil {
+		return nil
+	}
+	return handler.Config.AppSpec.Placements[placement]
+}
+
+// podNodeSelector return the nodeSelector of the boot's pods, merged with the placement class's
+func (handler *BootHandler) podNodeSelector() map[string]string {
+	nodeSelector := handler.Boot.Spec.NodeSelector
+	class := handler.placementClass()
+	if class == nil || len(class.NodeSelector) == 0 {
+		return nodeSelector
+	}
+
+	merged := make(map[string]string)
+	for key, value := range nodeSelector {
+		merged[key] = value
+	}
+	for key, value := range class.NodeSelector {
+		merged[key] = value
+	}
+	return merged
+}
+
+// podTolerations return the tolerations of the boot's pods, merged with the placement class's
+func (handler *BootHandler) podTolerations() []corev1.Toleration {
+	var tolerations []corev1.Toleration
+	if len(handler.Boot.Spec.Tolerations) > 0 {
+		tolerations = make([]corev1.Toleration, len(handler.Boot.Spec.Tolerations))
+		copy(tolerations, handler.Boot.Spec.Tolerations)
+	}
+
+	class := handler.placementClass()
+	if class != nil {
+		tolerations, _ = MergeTolerations(tolerations, class.Tolerations)
+	}
+	return tolerations
+}
+
+// podNodeAffinity return the node affinity of the boot's pods, merged with the placement class's
+func (handler *BootHandler) podNodeAffinity() *corev1.NodeAffinity {
+	var nodeAffinity *corev1.NodeAffinity
+	if handler.Boot.Spec.NodeAffinity != nil {
+		nodeAffinity = handler.Boot.Spec.NodeAffinity.DeepCopy()
+	}
+
+	class := handler.placementClass()
+	if class != nil {
+		nodeAffinity, _ = MergeNodeAffinity(nodeAffinity, class.NodeAffinity)
+	}
+	return nodeAffinity
+}
+
+// podPriority return the priorityClassName of the boot's pods, the boot's priority or the placement class's
+func (handler *BootHandler) podPriority() string {
+	if handler.Boot.Spec.Priority != "" {
+		return handler.Boot.Spec.Priority
+	}
+
+	class := handler.placementClass()
+	if class != nil {
+		return class.PriorityClassName
+	}
+	return ""
+}
+
+// expectedScheduling return the tolerations and affinity of the boot's pods,
+// merged with the podSpec of the boot type's config as rebuildPodSpec does.
+func (handler *BootHandler) expectedScheduling() ([]corev1.Toleration, *corev1.Affinity) {
+	podSpec := corev1.PodSpec{
+		Affinity:    handler.NewAffinity(),
+		Tolerations: handler.podTolerations(),
+	}
+
+	cfgPodSpec := handler.Config.AppSpec.PodSpec
+	if cfgPodSpec != nil {
+		toMerge := corev1.PodSpec{
+			Tolerations: cfgPodSpec.Tolerations,
+			Affinity:    cfgPodSpec.Affinity,
+		}
+		err := util.MergeOverride(&podSpec, *toMerge.DeepCopy())
+		if err != nil {
+			handler.Logger.Error(err, "config merge error.", "type", "podSpec")
+		}
+	}
+
+	return podSpec.Tolerations, podSpec.Affinity
+}
+
+// MergeTolerations add the tolerations of the config which are not in the boot's tolerations.
+// Return the merged tolerations and true if changed.
+func MergeTolerations(bootTolerations, cfgTolerations []corev1.Toleration) ([]corev1.Toleration, bool) {
+	changed := false
+	for _, cfgToleration := range cfgTolerations {
+		found := false
+		for _, toleration := range bootTolerations {
+			if reflect.DeepEqual(toleration, cfgToleration) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			bootTolerations = append(bootTolerations, *cfgToleration.DeepCopy())
+			changed = true
+		}
+	}
+	return bootTolerations, changed
+}
+
+// MergeNodeAffinity merge the node affinity of the config into the boot's.
+// The required terms are ANDed: every boot's term must contain the requirements of one of the config's terms,
+// otherwise it is expanded with each of the config's terms. The config's preferred terms are added if missing.
+// Return the merged node affinity and true if changed.
+func MergeNodeAffinity(bootAffinity, cfgAffinity *corev1.NodeAffinity) (*corev1.NodeAffinity, bool) {
+	if cfgAffinity == nil {
+		return bootAffinity, false
+	}
+	if bootAffinity == nil {
+		return cfgAffinity.DeepCopy(), true
+	}
+
+	changed := false
+	cfgRequired := cfgAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if cfgRequired != nil && len(cfgRequired.NodeSelectorTerms) > 0 {
+		bootRequired := bootAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+		if bootRequired == nil || len(bootRequired.NodeSelectorTerms) == 0 {
+			bootAffinity.RequiredDuringSchedulingIgnoredDuringExecution = cfgRequired.DeepCopy()
+			changed = true
+		} else {
+			terms := make([]corev1.NodeSelectorTerm, 0)
+			for _, term := range bootRequired.NodeSelectorTerms {
+				if containsAnyTerm(term, cfgRequired.NodeSelectorTerms) {
+					terms = append(terms, term)
+					continue
+				}
+				for _, cfgTerm := range cfgRequired.NodeSelectorTerms {
+					terms = append(terms, andNodeSelectorTerm(term, cfgTerm))
+				}
+				changed = true
+			}
+			bootRequired.NodeSelectorTerms = terms
+		}
+	}
+
+	for _, cfgPreferred := range cfgAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		found := false
+		for _, preferred := range bootAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			if reflect.DeepEqual(preferred, cfgPreferred) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			bootAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+				bootAffinity.PreferredDuringSchedulingIgnoredDuringExecution, *cfgPreferred.DeepCopy())
+			changed = true
+		}
+	}
+
+	return bootAffinity, changed
+}
+
+// containsAnyTerm return true if the term contains all the requirements of one of the terms
+func containsAnyTerm(term corev1.NodeSelectorTerm, terms []corev1.NodeSelectorTerm) bool {
+	for _, t := range terms {
+		if containsRequirements(term.MatchExpressions, t.MatchExpressions) &&
+			containsRequirements(term.MatchFields, t.MatchFields) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsRequirements return true if all the requirements of sub are in reqs
+func containsRequirements(reqs, sub []corev1.NodeSelectorRequirement) bool {
+	for _, s := range sub {
+		found := false
+		for _, r := range reqs {
+			if reflect.DeepEqual(r, s) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// andNodeSelectorTerm return a new term with the requirements of both terms
+func andNodeSelectorTerm(term, other corev1.NodeSelectorTerm) corev1.NodeSelectorTerm {
+	ret := *term.DeepCopy()
+	for _, req := range other.MatchExpressions {
+		if !containsRequirements(ret.MatchExpressions, []corev1.NodeSelectorRequirement{req}) {
+			ret.MatchExpressions = append(ret.MatchExpressions, *req.DeepCopy())
+		}
+	}
+	for _, req := range other.MatchFields {
+		if !containsRequirements(ret.MatchFields, []corev1.NodeSelectorRequirement{req}) {
+			ret.MatchFields = append(ret.MatchFields, *req.DeepCopy())
+		}
+	}
+	return ret
+}
@@ -0,0 +1,123 @@
+package operator
+
+import (
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	"github.com/logancloud/logan-app-operator/pkg/logan/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Scheduling", func() {
+	requirement := func(key string, values ...string) corev1.NodeSelectorRequirement {
+		return corev1.NodeSelectorRequirement{Key: key, Operator: corev1.NodeSelectorOpIn, Values: values}
+	}
+	term := func(reqs ...corev1.NodeSelectorRequirement) corev1.NodeSelectorTerm {
+		return corev1.NodeSelectorTerm{MatchExpressions: reqs}
+	}
+	required := func(terms ...corev1.NodeSelectorTerm) *corev1.NodeAffinity {
+		return &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: terms},
+		}
+	}
+	pool := requirement("logan/pool", "app")
+	zone := requirement("logan/zone", "a")
+	disk := requirement("logan/disk", "ssd")
+
+	DescribeTable("MergeNodeAffinity ANDs the required terms of the config",
+		func(bootAffinity, cfgAffinity, expected *corev1.NodeAffinity, changed bool) {
+			merged, mergedChanged := MergeNodeAffinity(bootAffinity, cfgAffinity)
+			Expect(merged).To(Equal(expected))
+			Expect(mergedChanged).To(Equal(changed))
+		},
+		Entry("no config", required(term(zone)), nil, required(term(zone)), false),
+		Entry("no boot", nil, required(term(pool)), required(term(pool)), true),
+		Entry("boot's term contains the config's", required(term(zone, pool)), required(term(pool)),
+			required(term(zone, pool)), false),
+		Entry("boot's term is expanded", required(term(zone)), required(term(pool)),
+			required(term(zone, pool)), true),
+		Entry("boot's terms are expanded with each of the config's", required(term(zone), term(disk)),
+			required(term(pool)), required(term(zone, pool), term(disk, pool)), true),
+	)
+
+	DescribeTable("MergeTolerations adds the missing tolerations of the config",
+		func(bootTolerations, cfgTolerations, expected []corev1.Toleration, changed bool) {
+			merged, mergedChanged := MergeTolerations(bootTolerations, cfgTolerations)
+			Expect(merged).To(Equal(expected))
+			Expect(mergedChanged).To(Equal(changed))
+		},
+		Entry("no config", []corev1.Toleration{{Key: "a"}}, nil, []corev1.Toleration{{Key: "a"}}, false),
+		Entry("existing", []corev1.Toleration{{Key: "a"}}, []corev1.Toleration{{Key: "a"}},
+			[]corev1.Toleration{{Key: "a"}}, false),
+		Entry("missing", []corev1.Toleration{{Key: "a"}}, []corev1.Toleration{{Key: "b"}},
+			[]corev1.Toleration{{Key: "a"}, {Key: "b"}}, true),
+	)
+
+	Context("With the placement class", func() {
+		var handler *BootHandler
+
+		BeforeEach(func() {
+			boot := newTestBoot("placement")
+			boot.Spec.Placement = "batch"
+			boot.Spec.NodeSelector = map[string]string{"logan/pool": "app", "logan/disk": "ssd"}
+			boot.Spec.Tolerations = []corev1.Toleration{{Key: "logan/disk"}}
+			boot.Spec.NodeAffinity = required(term(zone))
+			handler = newTestHandler(boot, &config.AppSpec{
+				Placements: map[string]*config.PlacementClass{
+					"batch": {
+						NodeSelector:      map[string]string{"logan/pool": "batch"},
+						Tolerations:       []corev1.Toleration{{Key: "logan/pool", Value: "batch"}},
+						NodeAffinity:      required(term(pool)),
+						PriorityClassName: "low-priority",
+					},
+				},
+			})
+		})
+
+		It("test the class is merged into the boot's scheduling", func() {
+			Expect(handler.podNodeSelector()).To(Equal(map[string]string{"logan/pool": "batch", "logan/disk": "ssd"}))
+			Expect(handler.podTolerations()).To(Equal([]corev1.Toleration{
+				{Key: "logan/disk"}, {Key: "logan/pool", Value: "batch"}}))
+			Expect(handler.podNodeAffinity()).To(Equal(required(term(zone, pool))))
+			Expect(handler.podPriority()).To(Equal("low-priority"))
+			// the boot's spec is not changed by the merge
+			Expect(handler.Boot.Spec.NodeAffinity).To(Equal(required(term(zone))))
+		})
+
+		It("test the boot's priority wins", func() {
+			handler.Boot.Spec.Priority = "high-priority"
+			Expect(handler.podPriority()).To(Equal("high-priority"))
+		})
+
+		It("test the unknown class is ignored", func() {
+			handler.Boot.Spec.Placement = "unknown"
+			Expect(handler.podNodeSelector()).To(Equal(handler.Boot.Spec.NodeSelector))
+			Expect(handler.podTolerations()).To(Equal(handler.Boot.Spec.Tolerations))
+			Expect(handler.podPriority()).To(BeEmpty())
+		})
+	})
+
+	DescribeTable("topologySpreadKeys",
+		func(zoneAntiAffinity appv1.ZoneAntiAffinity, constraints []appv1.BootTopologySpreadConstraint,
+			requiredKeys, preferredKeys []string) {
+			boot := newTestBoot("spread")
+			boot.Spec.ZoneAntiAffinity = zoneAntiAffinity
+			boot.Spec.TopologySpreadConstraints = constraints
+
+			required, preferred := topologySpreadKeys(boot)
+			Expect(required).To(Equal(requiredKeys))
+			Expect(preferred).To(Equal(preferredKeys))
+		},
+		Entry("none", appv1.ZoneAntiAffinity(""), nil, []string{}, []string{}),
+		Entry("required zone", appv1.ZoneAntiAffinityRequired, nil, []string{zoneTopologyKey}, []string{}),
+		Entry("preferred zone", appv1.ZoneAntiAffinityPreferred, nil, []string{}, []string{zoneTopologyKey}),
+		Entry("spread constraints are preferred", appv1.ZoneAntiAffinityRequired,
+			[]appv1.BootTopologySpreadConstraint{
+				{TopologyKey: zoneTopologyKey, WhenUnsatisfiable: appv1.DoNotSchedule},
+				{TopologyKey: "logan/rack"},
+				{TopologyKey: hostnameTopologyKey},
+			},
+			[]string{zoneTopologyKey}, []string{"logan/rack"}),
+	)
+})
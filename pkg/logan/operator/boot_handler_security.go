@@ -0,0 +1,118 @@
+package operator
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NewPodSecurityContext return the pod's securityContext: the securityContext of the config's podSpec,
+// overridden by the boot's, then defaulted to comply with the security policy.
+// An empty securityContext is returned instead of nil, as kubernetes defaults it.
+func (handler *BootHandler) NewPodSecurityContext(cfgContext *corev1.PodSecurityContext) *corev1.PodSecurityContext {
+	sc := &corev1.PodSecurityContext{}
+	if cfgContext != nil {
+		sc = cfgContext.DeepCopy()
+	}
+
+	bootContext := handler.Boot.Spec.SecurityContext
+	if bootContext != nil {
+		if bootContext.RunAsUser != nil {
+			runAsUser := *bootContext.RunAsUser
+			sc.RunAsUser = &runAsUser
+		}
+		if bootContext.RunAsNonRoot != nil {
+			runAsNonRoot := *bootContext.RunAsNonRoot
+			sc.RunAsNonRoot = &runAsNonRoot
+		}
+		if bootContext.FSGroup != nil {
+			fsGroup := *bootContext.FSGroup
+			sc.FSGroup = &fsGroup
+		}
+	}
+
+	policy := handler.Config.AppSpec.SecurityPolicy
+	if policy != nil && policy.RequireNonRoot {
+		runAsNonRoot := true
+		sc.RunAsNonRoot = &runAsNonRoot
+		if sc.RunAsUser == nil && policy.DefaultRunAsUser != nil {
+			runAsUser := *policy.DefaultRunAsUser
+			sc.RunAsUser = &runAsUser
+		}
+	}
+
+	return sc
+}
+
+// applyAppSecurityContext set the boot's readOnlyRootFilesystem and capabilities to the app container,
+// then default it to comply with the security policy.
+func (handler *BootHandler) applyAppSecurityContext(container *corev1.Container) {
+	bootContext := handler.Boot.Spec.SecurityContext
+	if bootContext != nil && (bootContext.ReadOnlyRootFilesystem != nil || bootContext.Capabilities != nil) {
+		sc := &corev1.SecurityContext{}
+		if container.SecurityContext != nil {
+			sc = container.SecurityContext.DeepCopy()
+		}
+		if bootContext.ReadOnlyRootFilesystem != nil {
+			readOnly := *bootContext.ReadOnlyRootFilesystem
+			sc.ReadOnlyRootFilesystem = &readOnly
+		}
+		if bootContext.Capabilities != nil {
+			sc.Capabilities = bootContext.Capabilities.DeepCopy()
+		}
+		container.SecurityContext = sc
+	}
+
+	handler.applyContainerSecurityPolicy(container)
+}
+
+// applyContainerSecurityPolicy default the container's securityContext to comply with the security policy,
+// used for the app, sidecar and init containers.
+func (handler *BootHandler) applyContainerSecurityPolicy(container *corev1.Container) {
+	policy := handler.Config.AppSpec.SecurityPolicy
+	if policy == nil {
+		return
+	}
+
+	nonRootViolated := policy.RequireNonRoot && container.SecurityContext != nil &&
+		container.SecurityContext.RunAsNonRoot != nil && !*container.SecurityContext.RunAsNonRoot
+	if !policy.RequireReadOnlyRootFilesystem && !nonRootViolated {
+		return
+	}
+
+	sc := &corev1.SecurityContext{}
+	if container.SecurityContext != nil {
+		sc = container.SecurityContext.DeepCopy()
+	}
+	if policy.RequireReadOnlyRootFilesystem {
+		readOnly := true
+		sc.ReadOnlyRootFilesystem = &readOnly
+	}
+	if nonRootViolated {
+		runAsNonRoot := true
+		sc.RunAsNonRoot = &runAsNonRoot
+	}
+	container.SecurityContext = sc
+}
+
+// expectedInitContainers return the init containers of the config's podSpec, defaulted by the security policy
+func (handler *BootHandler) expectedInitContainers() []corev1.Container {
+	podSpec := handler.Config.AppSpec.PodSpec
+	if podSpec == nil || len(podSpec.InitContainers) == 0 {
+		return nil
+	}
+
+	initContainers := make([]corev1.Container, len(podSpec.InitContainers))
+	for i := range podSpec.InitContainers {
+		podSpec.InitContainers[i].DeepCopyInto(&initContainers[i])
+		handler.applyContainerSecurityPolicy(&initContainers[i])
+	}
+	return initContainers
+}
+
+// expectedPodSecurityContext return the securityContext of the boot's pods
+func (handler *BootHandler) expectedPodSecurityContext() *corev1.PodSecurityContext {
+	var cfgContext *corev1.PodSecurityContext
+	if handler.Config.AppSpec.PodSpec != nil {
+		cfgContext = handler.Config.AppSpec.PodSpec.SecurityContext
+	}
+	return handler.NewPodSecurityContext(cfgContext)
+}
@@ -0,0 +1,79 @@
+package operator
+
+import (
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	"github.com/logancloud/logan-app-operator/pkg/logan/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Security", func() {
+	boolPtr := func(b bool) *bool { return &b }
+	int64Ptr := func(i int64) *int64 { return &i }
+
+	DescribeTable("NewPodSecurityContext complies with the security policy",
+		func(cfgContext *corev1.PodSecurityContext, bootContext *appv1.BootSecurityContext, policy *config.SecurityPolicy,
+			expected *corev1.PodSecurityContext) {
+			boot := newTestBoot("security")
+			boot.Spec.SecurityContext = bootContext
+			handler := newTestHandler(boot, &config.AppSpec{SecurityPolicy: policy})
+
+			Expect(handler.NewPodSecurityContext(cfgContext)).To(Equal(expected))
+		},
+		Entry("empty", nil, nil, nil, &corev1.PodSecurityContext{}),
+		Entry("boot's over the config's",
+			&corev1.PodSecurityContext{RunAsUser: int64Ptr(0), FSGroup: int64Ptr(1)},
+			&appv1.BootSecurityContext{RunAsUser: int64Ptr(1000)}, nil,
+			&corev1.PodSecurityContext{RunAsUser: int64Ptr(1000), FSGroup: int64Ptr(1)}),
+		Entry("non-root required with the default user", nil, nil,
+			&config.SecurityPolicy{RequireNonRoot: true, DefaultRunAsUser: int64Ptr(1000)},
+			&corev1.PodSecurityContext{RunAsNonRoot: boolPtr(true), RunAsUser: int64Ptr(1000)}),
+		Entry("non-root required keeps the boot's user",
+			nil, &appv1.BootSecurityContext{RunAsUser: int64Ptr(2000), RunAsNonRoot: boolPtr(false)},
+			&config.SecurityPolicy{RequireNonRoot: true, DefaultRunAsUser: int64Ptr(1000)},
+			&corev1.PodSecurityContext{RunAsNonRoot: boolPtr(true), RunAsUser: int64Ptr(2000)}),
+	)
+
+	DescribeTable("applyAppSecurityContext complies with the security policy",
+		func(containerContext *corev1.SecurityContext, bootContext *appv1.BootSecurityContext,
+			policy *config.SecurityPolicy, expected *corev1.SecurityContext) {
+			boot := newTestBoot("security")
+			boot.Spec.SecurityContext = bootContext
+			handler := newTestHandler(boot, &config.AppSpec{SecurityPolicy: policy})
+
+			container := &corev1.Container{Name: "app", SecurityContext: containerContext}
+			handler.applyAppSecurityContext(container)
+			Expect(container.SecurityContext).To(Equal(expected))
+		},
+		Entry("empty", nil, nil, nil, nil),
+		Entry("boot's", nil,
+			&appv1.BootSecurityContext{ReadOnlyRootFilesystem: boolPtr(false),
+				Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"NET_BIND_SERVICE"}}}, nil,
+			&corev1.SecurityContext{ReadOnlyRootFilesystem: boolPtr(false),
+				Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"NET_BIND_SERVICE"}}}),
+		Entry("read-only required", nil, &appv1.BootSecurityContext{ReadOnlyRootFilesystem: boolPtr(false)},
+			&config.SecurityPolicy{RequireReadOnlyRootFilesystem: true},
+			&corev1.SecurityContext{ReadOnlyRootFilesystem: boolPtr(true)}),
+		Entry("root container with non-root required", &corev1.SecurityContext{RunAsNonRoot: boolPtr(false)}, nil,
+			&config.SecurityPolicy{RequireNonRoot: true},
+			&corev1.SecurityContext{RunAsNonRoot: boolPtr(true)}),
+		Entry("container without user with non-root required", nil, nil,
+			&config.SecurityPolicy{RequireNonRoot: true}, nil),
+	)
+
+	It("test the config's init containers comply with the security policy", func() {
+		podSpec := &corev1.PodSpec{InitContainers: []corev1.Container{{Name: "init"}}}
+		handler := newTestHandler(newTestBoot("security"), &config.AppSpec{
+			PodSpec:        podSpec,
+			SecurityPolicy: &config.SecurityPolicy{RequireReadOnlyRootFilesystem: true},
+		})
+
+		initContainers := handler.expectedInitContainers()
+		Expect(initContainers).To(HaveLen(1))
+		Expect(*initContainers[0].SecurityContext.ReadOnlyRootFilesystem).To(BeTrue())
+		// the config is not changed
+		Expect(podSpec.InitContainers[0].SecurityContext).To(BeNil())
+	})
+})
@@ -0,0 +1,434 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	loganMetrics "github.com/logancloud/logan-app-operator/pkg/logan/metrics"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ServiceAccountName return the name of the ServiceAccount of the boot's pods, empty for the namespace's default
+func ServiceAccountName(boot *appv1.Boot) string {
+	sa := boot.Spec.ServiceAccount
+	if sa == nil {
+		return ""
+	}
+	if sa.Name == "" && sa.Create {
+		return boot.Name
+	}
+	return sa.Name
+}
+
+// AutomountServiceAccountToken return whether the ServiceAccount's token is mounted into the boot's pods,
+// default is true if the boot has rules. nil if the boot has no ServiceAccount.
+func AutomountServiceAccountToken(boot *appv1.Boot) *bool {
+	sa := boot.Spec.ServiceAccount
+	if sa == nil {
+		return nil
+	}
+	// The rules are granted through the token, which is mounted unless disabled
+	automount := len(sa.Rules) > 0
+	if sa.AutomountToken != nil {
+		automount = *sa.AutomountToken
+	}
+	return &automount
+}
+
+// RoleName return name for the created Role and RoleBinding, which is specific to the operator so that it does not
+// collide with the Roles created by the users
+func RoleName(boot *appv1.Boot) string {
+	return boot.Name + "-boot"
+}
+
+// NewServiceAccount return a new created ServiceAccount object, nil if the boot does not create it
+func (handler *BootHandler) NewServiceAccount() *corev1.ServiceAccount {
+	boot := handler.Boot
+	if boot.Spec.ServiceAccount == nil || !boot.Spec.ServiceAccount.Create {
+		return nil
+	}
+
+	sa := &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ServiceAccount",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ServiceAccountName(boot),
+			Namespace: boot.Namespace,
+			Labels:    WorkloadLabels(boot),
+		},
+	}
+
+	// Set Boot instance as the owner and controller
+	_ = controllerutil.SetControllerReference(handler.OperatorBoot, sa, handler.Scheme)
+
+	return sa
+}
+
+// NewRole return a new created Role object, nil if the boot has no rules
+func (handler *BootHandler) NewRole() *rbacv1.Role {
+	boot := handler.Boot
+	if boot.Spec.ServiceAccount == nil || len(boot.Spec.ServiceAccount.Rules) == 0 {
+		return nil
+	}
+
+	rules := make([]rbacv1.PolicyRule, len(boot.Spec.ServiceAccount.Rules))
+	for i := range boot.Spec.ServiceAccount.Rules {
+		boot.Spec.ServiceAccount.Rules[i].DeepCopyInto(&rules[i])
+	}
+
+	role := &rbacv1.Role{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "Role",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      RoleName(boot),
+			Namespace: boot.Namespace,
+			Labels:    WorkloadLabels(boot),
+		},
+		Rules: rules,
+	}
+
+	// Set Boot instance as the owner and controller
+	_ = controllerutil.SetControllerReference(handler.OperatorBoot, role, handler.Scheme)
+
+	return role
+}
+
+// NewRoleBinding return a new created RoleBinding object, which binds the boot's Role to the ServiceAccount.
+// nil if the boot has no rules
+func (handler *BootHandler) NewRoleBinding() *rbacv1.RoleBinding {
+	boot := handler.Boot
+	if boot.Spec.ServiceAccount == nil || len(boot.Spec.ServiceAccount.Rules) == 0 {
+		return nil
+	}
+
+	roleBinding := &rbacv1.RoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "RoleBinding",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      RoleName(boot),
+			Namespace: boot.Namespace,
+			Labels:    WorkloadLabels(boot),
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      ServiceAccountName(boot),
+				Namespace: boot.Namespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     RoleName(boot),
+		},
+	}
+
+	// Set Boot instance as the owner and controller
+	_ = controllerutil.SetControllerReference(handler.OperatorBoot, roleBinding, handler.Scheme)
+
+	return roleBinding
+}
+
+// serviceAccountError record the error of the boot's ServiceAccount, Role or RoleBinding, and requeue
+func (handler *BootHandler) serviceAccountError(err error, msg string, subStage string, reason string) (reconcile.Result, bool, error) {
+	boot := handler.Boot
+	handler.Logger.Error(err, msg)
+	loganMetrics.UpdateReconcileErrors(boot.Kind,
+		loganMetrics.RECONCILE_UPDATE_STAGE,
+		subStage,
+		boot.Name)
+	handler.RecordEvent(reason, msg, err)
+	return reconcile.Result{Requeue: true}, true, err
+}
+
+// reconcileUpdateServiceAccount handle create/update/delete of the boot's ServiceAccount, Role and RoleBinding
+// 1. Delete the ServiceAccounts owned by the boot which are not used, create the ServiceAccount if not found
+// 2. Check the Role's existence and rules
+// 3. Check the RoleBinding's existence, subjects and roleRef
+func (handler *BootHandler) reconcileUpdateServiceAccount() (reconcile.Result, bool, error) {
+	result, requeue, err := handler.reconcileOwnedServiceAccount()
+	if requeue {
+		return result, true, err
+	}
+
+	result, requeue, err = handler.reconcileOwnedRole()
+	if requeue {
+		return result, true, err
+	}
+
+	return handler.reconcileOwnedRoleBinding()
+}
+
+// reconcileOwnedServiceAccount handle create/delete of the ServiceAccount owned by the boot
+func (handler *BootHandler) reconcileOwnedServiceAccount() (reconcile.Result, bool, error) {
+	boot := handler.Boot
+	logger := handler.Logger
+	c := handler.Client
+
+	expected := handler.NewServiceAccount()
+
+	saList := &corev1.ServiceAccountList{}
+	err := c.List(context.TODO(), saList,
+		client.InNamespace(boot.Namespace),
+		client.MatchingLabels(WorkloadLabels(boot)))
+	if err != nil {
+		return handler.serviceAccountError(err, "Failed to list ServiceAccounts",
+			loganMetrics.RECONCILE_GET_SERVICEACCOUNT_SUBSTAGE, keys.FailedGetServiceAccount)
+	}
+
+	// 1. Delete the unused ServiceAccounts created by the operator
+	deleted := false
+	for i := range saList.Items {
+		sa := &saList.Items[i]
+		if !metav1.IsControlledBy(sa, handler.OperatorBoot) {
+			continue
+		}
+		if expected != nil && sa.Name == expected.Name {
+			continue
+		}
+
+		logger.Info("Deleting ServiceAccount", "serviceAccount", sa.Name)
+		err = c.Delete(context.TODO(), sa)
+		if err != nil && !errors.IsNotFound(err) {
+			return handler.serviceAccountError(err, fmt.Sprintf("Failed to delete ServiceAccount: %s", sa.Name),
+				loganMetrics.RECONCILE_DELETE_SERVICEACCOUNT_SUBSTAGE, keys.FailedDeleteServiceAccount)
+		}
+		handler.RecordEvent(keys.DeletedServiceAccount, fmt.Sprintf("Deleted ServiceAccount: %s", sa.Name), nil)
+		deleted = true
+	}
+	if deleted {
+		return reconcile.Result{Requeue: true}, true, nil
+	}
+
+	if expected == nil {
+		return reconcile.Result{}, false, nil
+	}
+
+	// 2. Create the ServiceAccount, an existing ServiceAccount of the same name not created by the operator
+	// is never used, which may be bound to any roles
+	found := &corev1.ServiceAccount{}
+	err = c.Get(context.TODO(), types.NamespacedName{Name: expected.Name, Namespace: boot.Namespace}, found)
+	if err == nil {
+		if !metav1.IsControlledBy(found, handler.OperatorBoot) {
+			return handler.serviceAccountError(notControlledError("ServiceAccount", expected.Name, boot),
+				fmt.Sprintf("Failed to create ServiceAccount: %s", expected.Name),
+				loganMetrics.RECONCILE_CREATE_SERVICEACCOUNT_SUBSTAGE, keys.FailedCreateServiceAccount)
+		}
+		return reconcile.Result{}, false, nil
+	}
+	if !errors.IsNotFound(err) {
+		return handler.serviceAccountError(err, fmt.Sprintf("Failed to get ServiceAccount: %s", expected.Name),
+			loganMetrics.RECONCILE_GET_SERVICEACCOUNT_SUBSTAGE, keys.FailedGetServiceAccount)
+	}
+
+	logger.Info("Creating ServiceAccount", "serviceAccount", expected.Name)
+	err = c.Create(context.TODO(), expected)
+	if err != nil {
+		return handler.serviceAccountError(err, fmt.Sprintf("Failed to create ServiceAccount: %s", expected.Name),
+			loganMetrics.RECONCILE_CREATE_SERVICEACCOUNT_SUBSTAGE, keys.FailedCreateServiceAccount)
+	}
+	handler.RecordEvent(keys.CreatedServiceAccount, fmt.Sprintf("Created ServiceAccount: %s", expected.Name), nil)
+
+	return reconcile.Result{Requeue: true}, true, nil
+}
+
+// reconcileOwnedRole handle create/update/delete of the Role owned by the boot
+func (handler *BootHandler) reconcileOwnedRole() (reconcile.Result, bool, error) {
+	boot := handler.Boot
+	logger := handler.Logger
+	c := handler.Client
+
+	expected := handler.NewRole()
+
+	roleList := &rbacv1.RoleList{}
+	err := c.List(context.TODO(), roleList,
+		client.InNamespace(boot.Namespace),
+		client.MatchingLabels(WorkloadLabels(boot)))
+	if err != nil {
+		return handler.serviceAccountError(err, "Failed to list Roles",
+			loganMetrics.RECONCILE_GET_ROLE_SUBSTAGE, keys.FailedGetRole)
+	}
+
+	// 1. Delete the unused Roles created by the operator, such as the boot has no rules
+	deleted := false
+	for i := range roleList.Items {
+		role := &roleList.Items[i]
+		if !metav1.IsControlledBy(role, handler.OperatorBoot) {
+			continue
+		}
+		if expected != nil && role.Name == expected.Name {
+			continue
+		}
+
+		logger.Info("Deleting Role", "role", role.Name)
+		err = c.Delete(context.TODO(), role)
+		if err != nil && !errors.IsNotFound(err) {
+			return handler.serviceAccountError(err, fmt.Sprintf("Failed to delete Role: %s", role.Name),
+				loganMetrics.RECONCILE_DELETE_ROLE_SUBSTAGE, keys.FailedDeleteRole)
+		}
+		handler.RecordEvent(keys.DeletedRole, fmt.Sprintf("Deleted Role: %s", role.Name), nil)
+		deleted = true
+	}
+	if deleted {
+		return reconcile.Result{Requeue: true}, true, nil
+	}
+
+	if expected == nil {
+		return reconcile.Result{}, false, nil
+	}
+
+	roleName := expected.Name
+	found := &rbacv1.Role{}
+	err = c.Get(context.TODO(), types.NamespacedName{Name: roleName, Namespace: boot.Namespace}, found)
+	if err != nil && !errors.IsNotFound(err) {
+		return handler.serviceAccountError(err, fmt.Sprintf("Failed to get Role: %s", roleName),
+			loganMetrics.RECONCILE_GET_ROLE_SUBSTAGE, keys.FailedGetRole)
+	}
+
+	// 2. Not found: create it
+	if errors.IsNotFound(err) {
+		logger.Info("Creating Role", "role", roleName, "rules", expected.Rules)
+		err = c.Create(context.TODO(), expected)
+		if err != nil {
+			return handler.serviceAccountError(err, fmt.Sprintf("Failed to create Role: %s", roleName),
+				loganMetrics.RECONCILE_CREATE_ROLE_SUBSTAGE, keys.FailedCreateRole)
+		}
+		handler.RecordEvent(keys.CreatedRole, fmt.Sprintf("Created Role: %s", roleName), nil)
+		return reconcile.Result{Requeue: true}, true, nil
+	}
+
+	// 3. A Role of the same name not created by the operator is never bound
+	if !metav1.IsControlledBy(found, handler.OperatorBoot) {
+		return handler.serviceAccountError(notControlledError("Role", roleName, boot),
+			fmt.Sprintf("Failed to create Role: %s", roleName),
+			loganMetrics.RECONCILE_CREATE_ROLE_SUBSTAGE, keys.FailedCreateRole)
+	}
+
+	// 4. Check the rules
+	if reflect.DeepEqual(found.Rules, expected.Rules) {
+		return reconcile.Result{}, false, nil
+	}
+
+	logger.Info("Updating Role", "type", "rules", "role", roleName, "old", found.Rules, "new", expected.Rules)
+	found.Rules = expected.Rules
+	err = c.Update(context.TODO(), found)
+	if err != nil {
+		return handler.serviceAccountError(err, fmt.Sprintf("Failed to update Role: %s", roleName),
+			loganMetrics.RECONCILE_UPDATE_ROLE_SUBSTAGE, keys.FailedUpdateRole)
+	}
+	handler.RecordEvent(keys.UpdatedRole, fmt.Sprintf("Updated Role: %s", roleName), nil)
+
+	return reconcile.Result{Requeue: true}, true, nil
+}
+
+// reconcileOwnedRoleBinding handle create/update/delete of the RoleBinding owned by the boot
+func (handler *BootHandler) reconcileOwnedRoleBinding() (reconcile.Result, bool, error) {
+	boot := handler.Boot
+	logger := handler.Logger
+	c := handler.Client
+
+	expected := handler.NewRoleBinding()
+
+	roleBindingList := &rbacv1.RoleBindingList{}
+	err := c.List(context.TODO(), roleBindingList,
+		client.InNamespace(boot.Namespace),
+		client.MatchingLabels(WorkloadLabels(boot)))
+	if err != nil {
+		return handler.serviceAccountError(err, "Failed to list RoleBindings",
+			loganMetrics.RECONCILE_GET_ROLEBINDING_SUBSTAGE, keys.FailedGetRoleBinding)
+	}
+
+	// 1. Delete the unused RoleBindings created by the operator, such as the boot has no rules.
+	// RoleBinding's roleRef is immutable, it is deleted and created again if changed.
+	deleted := false
+	for i := range roleBindingList.Items {
+		roleBinding := &roleBindingList.Items[i]
+		if !metav1.IsControlledBy(roleBinding, handler.OperatorBoot) {
+			continue
+		}
+		if expected != nil && roleBinding.Name == expected.Name &&
+			reflect.DeepEqual(roleBinding.RoleRef, expected.RoleRef) {
+			continue
+		}
+
+		logger.Info("Deleting RoleBinding", "roleBinding", roleBinding.Name)
+		err = c.Delete(context.TODO(), roleBinding)
+		if err != nil && !errors.IsNotFound(err) {
+			return handler.serviceAccountError(err, fmt.Sprintf("Failed to delete RoleBinding: %s", roleBinding.Name),
+				loganMetrics.RECONCILE_DELETE_ROLEBINDING_SUBSTAGE, keys.FailedDeleteRoleBinding)
+		}
+		handler.RecordEvent(keys.DeletedRoleBinding, fmt.Sprintf("Deleted RoleBinding: %s", roleBinding.Name), nil)
+		deleted = true
+	}
+	if deleted {
+		return reconcile.Result{Requeue: true}, true, nil
+	}
+
+	if expected == nil {
+		return reconcile.Result{}, false, nil
+	}
+
+	roleBindingName := expected.Name
+	found := &rbacv1.RoleBinding{}
+	err = c.Get(context.TODO(), types.NamespacedName{Name: roleBindingName, Namespace: boot.Namespace}, found)
+	if err != nil && !errors.IsNotFound(err) {
+		return handler.serviceAccountError(err, fmt.Sprintf("Failed to get RoleBinding: %s", roleBindingName),
+			loganMetrics.RECONCILE_GET_ROLEBINDING_SUBSTAGE, keys.FailedGetRoleBinding)
+	}
+
+	// 2. Not found: create it
+	if errors.IsNotFound(err) {
+		logger.Info("Creating RoleBinding", "roleBinding", roleBindingName, "subjects", expected.Subjects)
+		err = c.Create(context.TODO(), expected)
+		if err != nil {
+			return handler.serviceAccountError(err, fmt.Sprintf("Failed to create RoleBinding: %s", roleBindingName),
+				loganMetrics.RECONCILE_CREATE_ROLEBINDING_SUBSTAGE, keys.FailedCreateRoleBinding)
+		}
+		handler.RecordEvent(keys.CreatedRoleBinding, fmt.Sprintf("Created RoleBinding: %s", roleBindingName), nil)
+		return reconcile.Result{Requeue: true}, true, nil
+	}
+
+	// 3. A RoleBinding of the same name not created by the operator is never updated
+	if !metav1.IsControlledBy(found, handler.OperatorBoot) {
+		return handler.serviceAccountError(notControlledError("RoleBinding", roleBindingName, boot),
+			fmt.Sprintf("Failed to create RoleBinding: %s", roleBindingName),
+			loganMetrics.RECONCILE_CREATE_ROLEBINDING_SUBSTAGE, keys.FailedCreateRoleBinding)
+	}
+
+	// 4. Check the subjects
+	if reflect.DeepEqual(found.Subjects, expected.Subjects) {
+		return reconcile.Result{}, false, nil
+	}
+
+	logger.Info("Updating RoleBinding", "type", "subjects", "roleBinding", roleBindingName,
+		"old", found.Subjects, "new", expected.Subjects)
+	found.Subjects = expected.Subjects
+	err = c.Update(context.TODO(), found)
+	if err != nil {
+		return handler.serviceAccountError(err, fmt.Sprintf("Failed to update RoleBinding: %s", roleBindingName),
+			loganMetrics.RECONCILE_UPDATE_ROLEBINDING_SUBSTAGE, keys.FailedUpdateRoleBinding)
+	}
+	handler.RecordEvent(keys.UpdatedRoleBinding, fmt.Sprintf("Updated RoleBinding: %s", roleBindingName), nil)
+
+	return reconcile.Result{Requeue: true}, true, nil
+}
+
+// notControlledError return the error of the existing object of the name, which is not controlled by the boot
+func notControlledError(kind string, name string, boot *appv1.Boot) error {
+	return fmt.Errorf("%s %s already exists and is not controlled by the boot %s", kind, name, boot.Name)
+}
@@ -0,0 +1,89 @@
+package operator
+
+import (
+	"fmt"
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// bootShutdown return the boot's shutdown, fallback to the config's
+func (handler *BootHandler) bootShutdown() *appv1.BootShutdown {
+	if handler.Boot.Spec.Shutdown != nil {
+		return handler.Boot.Spec.Shutdown
+	}
+	return handler.Config.AppSpec.Shutdown
+}
+
+// NewPreStopHandler return the preStop handler of the app container, nil if neither the preStop hook
+// nor the drain delay is specified. The drain delay is a sleep executed before the Exec hook's command.
+func (handler *BootHandler) NewPreStopHandler() *corev1.Handler {
+	boot := handler.Boot
+	shutdown := handler.bootShutdown()
+	if shutdown == nil {
+		return nil
+	}
+
+	drainDelay := int32Value(shutdown.DrainDelaySeconds, 0)
+	preStop := shutdown.PreStop
+	if preStop == nil {
+		if drainDelay <= 0 {
+			return nil
+		}
+		return &corev1.Handler{
+			Exec: &corev1.ExecAction{
+				Command: []string{defaultShell, "-c", fmt.Sprintf("sleep %d", drainDelay)},
+			},
+		}
+	}
+
+	if preStop.Type == appv1.ProbeExec {
+		command := preStop.Command
+		if drainDelay > 0 {
+			// "$@" is the hook's command, $0 is the name of the script
+			command = append([]string{defaultShell, "-c",
+				fmt.Sprintf("sleep %d && exec \"$@\"", drainDelay), "preStop"}, preStop.Command...)
+		}
+		return &corev1.Handler{
+			Exec: &corev1.ExecAction{
+				Command: command,
+			},
+		}
+	}
+
+	port := intstr.FromInt(int(boot.Spec.Port))
+	if preStop.Port != nil {
+		port = intstr.FromInt(int(*preStop.Port))
+	}
+	path := preStop.Path
+	if path == "" {
+		path = "/"
+	}
+	scheme := preStop.Scheme
+	if scheme == "" {
+		scheme = corev1.URISchemeHTTP
+	}
+
+	return &corev1.Handler{
+		HTTPGet: &corev1.HTTPGetAction{
+			Path:   path,
+			Port:   port,
+			Scheme: scheme,
+		},
+	}
+}
+
+// expectedTerminationGracePeriod return the termination grace period of the boot's pods,
+// fallback to the config's podSpec and the kubernetes' default value.
+func (handler *BootHandler) expectedTerminationGracePeriod() int64 {
+	shutdown := handler.bootShutdown()
+	if shutdown != nil && shutdown.TerminationGracePeriodSeconds != nil {
+		return *shutdown.TerminationGracePeriodSeconds
+	}
+
+	podSpec := handler.Config.AppSpec.PodSpec
+	if podSpec != nil && podSpec.TerminationGracePeriodSeconds != nil {
+		return *podSpec.TerminationGracePeriodSeconds
+	}
+	return defaultTerminationGracePeriodSeconds
+}
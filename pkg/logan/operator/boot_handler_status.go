@@ -1,10 +1,42 @@
 package operator
 
 import (
+	"context"
+	"fmt"
 	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"strconv"
+	"strings"
+)
+
+const (
+	// ReasonMinimumReplicasAvailable is the condition reason when all desired replicas are ready
+	ReasonMinimumReplicasAvailable = "MinimumReplicasAvailable"
+	// ReasonMinimumReplicasUnavailable is the condition reason when some desired replicas are not ready
+	ReasonMinimumReplicasUnavailable = "MinimumReplicasUnavailable"
+	// ReasonRolloutInProgress is the condition reason when the workload is rolling out
+	ReasonRolloutInProgress = "RolloutInProgress"
+	// ReasonRolloutComplete is the condition reason when the workload finished rolling out
+	ReasonRolloutComplete = "RolloutComplete"
+	// ReasonProgressDeadlineExceeded is the condition reason when the workload exceeded its progress deadline
+	ReasonProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+	// ReasonReconcileSucceeded is the condition reason when the latest reconcile succeeded
+	ReasonReconcileSucceeded = "ReconcileSucceeded"
+	// ReasonConfigValid is the condition reason when the operator config of the boot is valid
+	ReasonConfigValid = "ConfigValid"
+	// ReasonProfileInvalid is the condition reason when the profile of the boot is invalid
+	ReasonProfileInvalid = "ProfileInvalid"
+	// ReasonRevisionFailed is the condition reason when the latest revision failed to become Active
+	ReasonRevisionFailed = "RevisionFailed"
+	// ReasonRevisionRolledBack is the condition reason when the latest revision is created by a rollback
+	ReasonRevisionRolledBack = "RevisionRolledBack"
+	// ReasonNewRevision is the condition reason when the latest revision is not created by a rollback
+	ReasonNewRevision = "NewRevision"
 )
 
 // ReconcileUpdateStatus handle update logic for status
@@ -103,6 +135,20 @@ func (handler *BootHandler) ReconcileUpdateStatus() (reconcile.Result, bool, boo
 		changed = true
 	}
 
+	// 4.1 ingress
+	ingressNames, err := handler.listRuntimeIngressNames()
+	if err != nil {
+		return reconcile.Result{Requeue: true}, true, changed, err
+	}
+	ingressText := strings.Join(ingressNames, ",")
+	if bootStatus.Ingresses != ingressText {
+		logger.Info(reason, "type", "status.Ingresses",
+			"from", bootStatus.Ingresses,
+			"to", ingressText)
+		bootStatus.Ingresses = ingressText
+		changed = true
+	}
+
 	// 5. revision
 	revisionLst, _ := c.ListRevision(boot.Namespace, podLabels)
 	latestRevision := revisionLst.SelectLatestRevision()
@@ -117,5 +163,152 @@ func (handler *BootHandler) ReconcileUpdateStatus() (reconcile.Result, bool, boo
 		}
 	}
 
+	// 5.1 imageDigest
+	if bootStatus.ImageDigest != boot.Spec.ImageDigest {
+		logger.Info(reason, "type", "status.ImageDigest",
+			"from", bootStatus.ImageDigest,
+			"to", boot.Spec.ImageDigest)
+		bootStatus.ImageDigest = boot.Spec.ImageDigest
+		changed = true
+	}
+
+	// 6. observedGeneration
+	generation := handler.OperatorMeta.GetGeneration()
+	if bootStatus.ObservedGeneration != generation {
+		logger.Info(reason, "type", "status.ObservedGeneration",
+			"from", bootStatus.ObservedGeneration,
+			"to", generation)
+		bootStatus.ObservedGeneration = generation
+		changed = true
+	}
+
+	// 7. conditions
+	progressing, deadlineExceeded, err := handler.getWorkloadRolloutStatus()
+	if err != nil {
+		return reconcile.Result{Requeue: true}, true, changed, err
+	}
+	if handler.updateConditions(readyReplicas, progressing, deadlineExceeded) {
+		logger.Info(reason, "type", "status.Conditions",
+			"to", bootStatus.Conditions)
+		changed = true
+	}
+
 	return reconcile.Result{Requeue: changed}, changed, changed, nil
 }
+
+// updateConditions will update the Available/Progressing/Degraded/ReconcileFailed/ConfigInvalid/RolledBack conditions,
+// return true if any condition changed.
+func (handler *BootHandler) updateConditions(readyReplicas int32, progressing, deadlineExceeded bool) bool {
+	bootStatus := handler.OperatorStatus
+	desired := *handler.OperatorSpec.Replicas
+	changed := false
+
+	// Available
+	if readyReplicas >= desired {
+		changed = bootStatus.SetCondition(appv1.BootAvailable, corev1.ConditionTrue,
+			ReasonMinimumReplicasAvailable,
+			fmt.Sprintf("%d/%d replicas are ready", readyReplicas, desired)) || changed
+	} else {
+		changed = bootStatus.SetCondition(appv1.BootAvailable, corev1.ConditionFalse,
+			ReasonMinimumReplicasUnavailable,
+			fmt.Sprintf("%d/%d replicas are ready", readyReplicas, desired)) || changed
+	}
+
+	// Progressing and Degraded
+	latestRevision := handler.latestRevision()
+	if deadlineExceeded {
+		changed = bootStatus.SetCondition(appv1.BootProgressing, corev1.ConditionFalse,
+			ReasonProgressDeadlineExceeded, "Workload has exceeded its progress deadline") || changed
+		changed = bootStatus.SetCondition(appv1.BootDegraded, corev1.ConditionTrue,
+			ReasonProgressDeadlineExceeded, "Workload has exceeded its progress deadline") || changed
+	} else if latestRevision != nil &&
+		latestRevision.Annotations[keys.BootRevisionPhaseAnnotationKey] == RevisionPhaseFailed {
+		message := fmt.Sprintf("Revision %d failed to become Active: %s", latestRevision.GetRevisionId(),
+			latestRevision.Annotations[keys.BootRevisionFailureAnnotationKey])
+		changed = bootStatus.SetCondition(appv1.BootProgressing, corev1.ConditionFalse,
+			ReasonRevisionFailed, message) || changed
+		changed = bootStatus.SetCondition(appv1.BootDegraded, corev1.ConditionTrue,
+			ReasonRevisionFailed, message) || changed
+	} else {
+		if progressing {
+			changed = bootStatus.SetCondition(appv1.BootProgressing, corev1.ConditionTrue,
+				ReasonRolloutInProgress, "Workload is rolling out") || changed
+		} else {
+			changed = bootStatus.SetCondition(appv1.BootProgressing, corev1.ConditionFalse,
+				ReasonRolloutComplete, "Workload has finished rolling out") || changed
+		}
+		changed = bootStatus.SetCondition(appv1.BootDegraded, corev1.ConditionFalse,
+			ReasonRolloutComplete, "") || changed
+	}
+
+	// ReconcileFailed: this is called after the reconcile of all components succeeded.
+	changed = bootStatus.SetCondition(appv1.BootReconcileFailed, corev1.ConditionFalse,
+		ReasonReconcileSucceeded, "") || changed
+
+	// ConfigInvalid
+	if _, err := GetProfileBootConfig(handler.Boot, handler.Logger); err != nil {
+		changed = bootStatus.SetCondition(appv1.BootConfigInvalid, corev1.ConditionTrue,
+			ReasonProfileInvalid, err.Error()) || changed
+	} else {
+		changed = bootStatus.SetCondition(appv1.BootConfigInvalid, corev1.ConditionFalse,
+			ReasonConfigValid, "") || changed
+	}
+
+	// RolledBack: only set once the boot has been rolled back
+	if message, rolledBack := rolledBackCondition(latestRevision); rolledBack {
+		changed = bootStatus.SetCondition(appv1.BootRolledBack, corev1.ConditionTrue,
+			ReasonRevisionRolledBack, message) || changed
+	} else if bootStatus.GetCondition(appv1.BootRolledBack) != nil {
+		changed = bootStatus.SetCondition(appv1.BootRolledBack, corev1.ConditionFalse,
+			ReasonNewRevision, "") || changed
+	}
+
+	return changed
+}
+
+// ReconcileFailedStatus will set the ReconcileFailed condition with the failed stage, return true if changed.
+func (handler *BootHandler) ReconcileFailedStatus(stage string, err error) bool {
+	if err == nil {
+		return false
+	}
+	return handler.OperatorStatus.SetCondition(appv1.BootReconcileFailed, corev1.ConditionTrue,
+		stage, err.Error())
+}
+
+// getWorkloadRolloutStatus will return whether the workload is progressing and whether it exceeded the deadline
+func (handler *BootHandler) getWorkloadRolloutStatus() (bool, bool, error) {
+	boot := handler.Boot
+	c := handler.Client
+
+	workloadName := handler.activeWorkloadName()
+	if boot.Spec.Workload == appv1.Deployment || boot.Spec.Workload == "" {
+		dep := &appsv1.Deployment{}
+		err := c.Get(context.TODO(), types.NamespacedName{Name: workloadName, Namespace: boot.Namespace}, dep)
+		if err != nil {
+			return false, false, err
+		}
+		for _, cond := range dep.Status.Conditions {
+			if cond.Type == appsv1.DeploymentProgressing && cond.Reason == ReasonProgressDeadlineExceeded {
+				return false, true, nil
+			}
+		}
+		progressing := dep.Generation > dep.Status.ObservedGeneration ||
+			dep.Status.UpdatedReplicas < *dep.Spec.Replicas ||
+			dep.Status.Replicas > dep.Status.UpdatedReplicas ||
+			dep.Status.AvailableReplicas < dep.Status.UpdatedReplicas
+		return progressing, false, nil
+	} else if boot.Spec.Workload == appv1.StatefulSet {
+		sts := &appsv1.StatefulSet{}
+		err := c.Get(context.TODO(), types.NamespacedName{Name: workloadName, Namespace: boot.Namespace}, sts)
+		if err != nil {
+			return false, false, err
+		}
+		progressing := sts.Generation > sts.Status.ObservedGeneration ||
+			sts.Status.UpdateRevision != sts.Status.CurrentRevision ||
+			sts.Status.ReadyReplicas < *sts.Spec.Replicas
+		return progressing, false, nil
+	}
+
+	//should not execute this
+	return false, false, nil
+}
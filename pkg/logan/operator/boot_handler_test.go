@@ -0,0 +1,63 @@
+package operator
+
+import (
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	"github.com/logancloud/logan-app-operator/pkg/logan/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var _ = Describe("BootHandler", func() {
+	Context("With the deployment strategy", func() {
+		intOrString := func(s string) *intstr.IntOrString {
+			v := intstr.Parse(s)
+			return &v
+		}
+		deadline := int32(120)
+
+		DescribeTable("resolve the strategy from the boot and the config",
+			func(bootStrategy, cfgStrategy *appv1.BootStrategy,
+				expectedType appsv1.DeploymentStrategyType, maxSurge, maxUnavailable string, progressDeadline int32) {
+				boot := newTestBoot("strategy")
+				boot.Spec.Strategy = bootStrategy
+				handler := newTestHandler(boot, &config.AppSpec{Strategy: cfgStrategy})
+
+				strategy, _, progressDeadlineSeconds := handler.NewDeploymentStrategy()
+				Expect(strategy.Type).To(Equal(expectedType))
+				Expect(*progressDeadlineSeconds).To(Equal(progressDeadline))
+				if expectedType == appsv1.RecreateDeploymentStrategyType {
+					Expect(strategy.RollingUpdate).To(BeNil())
+					return
+				}
+				Expect(strategy.RollingUpdate.MaxSurge.String()).To(Equal(maxSurge))
+				Expect(strategy.RollingUpdate.MaxUnavailable.String()).To(Equal(maxUnavailable))
+			},
+			Entry("default of java", nil, nil,
+				appsv1.RollingUpdateDeploymentStrategyType, defaultMaxSurge, "1%", int32(defaultProgressDeadlineSeconds)),
+			Entry("the config's", nil, &appv1.BootStrategy{MaxUnavailable: intOrString("50%")},
+				appsv1.RollingUpdateDeploymentStrategyType, defaultMaxSurge, "50%", int32(defaultProgressDeadlineSeconds)),
+			Entry("the boot's over the config's",
+				&appv1.BootStrategy{MaxSurge: intOrString("2"), ProgressDeadlineSeconds: &deadline},
+				&appv1.BootStrategy{MaxUnavailable: intOrString("50%")},
+				appsv1.RollingUpdateDeploymentStrategyType, "2", defaultMaxUnavailable, deadline),
+			Entry("recreate", &appv1.BootStrategy{Type: appv1.RecreateStrategy}, nil,
+				appsv1.RecreateDeploymentStrategyType, "", "", int32(defaultProgressDeadlineSeconds)),
+		)
+
+		It("test the config's change reaches the boot without strategy", func() {
+			boot := newTestBoot("strategy")
+			handler := newTestHandler(boot, &config.AppSpec{Strategy: &appv1.BootStrategy{MaxUnavailable: intOrString("1")}})
+			before, _, _ := handler.NewDeploymentStrategy()
+
+			handler.Config.AppSpec.Strategy = &appv1.BootStrategy{MaxUnavailable: intOrString("25%")}
+			after, _, _ := handler.NewDeploymentStrategy()
+
+			Expect(before).NotTo(Equal(after))
+			Expect(after.RollingUpdate.MaxUnavailable.String()).To(Equal("25%"))
+			Expect(boot.Spec.Strategy).To(BeNil())
+		})
+	})
+})
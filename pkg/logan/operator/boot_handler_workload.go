@@ -16,6 +16,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+// rolloutState is the state of the progressive rollout in progress, returned to the workload's update logic
+type rolloutState struct {
+	// hold is true when the workload must keep its pod template
+	hold bool
+	// replicas is the replicas of the Deployment, nil for the boot's replicas
+	replicas *int32
+	// partition is the partition of the StatefulSet, nil for the partition of the strategy
+	partition *int32
+}
+
 // reconcileWorkloadCreate handle create logic for workload
 func (handler *BootHandler) reconcileWorkloadCreate() (*corev1.PodTemplateSpec, reconcile.Result, bool, error) {
 	workload := handler.Boot.Spec.Workload
@@ -147,12 +157,26 @@ func (handler *BootHandler) reconcileDeploymentUpdate() (*corev1.PodTemplateSpec
 			boot.Name)
 		return nil, reconcile.Result{Requeue: true}, true, err
 	}
-	result, requeue, err := handler.innerReconcileUpdateDeploy(depFound)
+
+	rollout, result, requeue, err := handler.reconcileCanaryDeploy(depFound)
+	if requeue {
+		return &depFound.Spec.Template, result, requeue, err
+	}
+
+	if rollout == nil {
+		rollout, result, requeue, err = handler.reconcileBlueGreenDeploy(depFound)
+		if requeue {
+			return &depFound.Spec.Template, result, requeue, err
+		}
+	}
+
+	result, requeue, err = handler.innerReconcileUpdateDeploy(depFound, rollout)
 	return &depFound.Spec.Template, result, requeue, err
 }
 
-// innerReconcileUpdateDeploy handle update logic of Deployment
-func (handler *BootHandler) innerReconcileUpdateDeploy(deploy *appsv1.Deployment) (reconcile.Result, bool, error) {
+// innerReconcileUpdateDeploy handle update logic of Deployment.
+// While a rollout is in progress, the replicas and the pod template of the Deployment are decided by the rollout.
+func (handler *BootHandler) innerReconcileUpdateDeploy(deploy *appsv1.Deployment, rollout *rolloutState) (reconcile.Result, bool, error) {
 	logger := handler.Logger
 	boot := handler.Boot
 	c := handler.Client
@@ -171,24 +195,63 @@ func (handler *BootHandler) innerReconcileUpdateDeploy(deploy *appsv1.Deployment
 	}
 
 	// 2. Check size
-	size := boot.Spec.Replicas
-	if *deploy.Spec.Replicas != *size {
+	size := *boot.Spec.Replicas
+	if rollout != nil && rollout.replicas != nil {
+		size = *rollout.replicas
+	}
+	if *deploy.Spec.Replicas != size {
 		logger.Info(reason, "type", "replicas", "deploy", deploy.Name,
 			"old", deploy.Spec.Replicas, "new", size)
-		*deploy.Spec.Replicas = *size
+		deploy.Spec.Replicas = &size
+
+		updated = true
+	}
+
+	// 3. Check strategy
+	strategy, minReadySeconds, progressDeadlineSeconds := handler.NewDeploymentStrategy()
+	if !reflect.DeepEqual(deploy.Spec.Strategy, strategy) ||
+		deploy.Spec.MinReadySeconds != minReadySeconds ||
+		!reflect.DeepEqual(deploy.Spec.ProgressDeadlineSeconds, progressDeadlineSeconds) {
+		logger.Info(reason, "type", "strategy", "deploy", deploy.Name,
+			"old", deploy.Spec.Strategy, "new", strategy,
+			"oldMinReadySeconds", deploy.Spec.MinReadySeconds, "newMinReadySeconds", minReadySeconds,
+			"oldProgressDeadlineSeconds", deploy.Spec.ProgressDeadlineSeconds,
+			"newProgressDeadlineSeconds", progressDeadlineSeconds)
+		deploy.Spec.Strategy = strategy
+		deploy.Spec.MinReadySeconds = minReadySeconds
+		deploy.Spec.ProgressDeadlineSeconds = progressDeadlineSeconds
+
+		updated = true
+	}
 
+	// 3.1 Check the labels and annotations of workloadMetadata, the pods are not restarted
+	if applyMetadata(&deploy.ObjectMeta, boot.Spec.WorkloadMetadata) {
+		logger.Info(reason, "type", "workloadMetadata", "deploy", deploy.Name)
 		updated = true
 	}
 
-	// 3. Check pod spec
+	// 4. Check pod spec
 	restartUpdated, rebootUpdated, err := handler.reconcilePodTemplateSpecUpdate(&deploy.Spec.Template)
 	if err != nil {
 		return reconcile.Result{Requeue: true}, true, err
 	}
 
+	// 4.1 Check the hash of the workload overrides, the spec is rebuilt with the patch
+	if overridesChanged(deploy, handler.workloadOverride()) {
+		logger.Info(reason, "type", "overrides", "deploy", deploy.Name)
+		rebootUpdated = true
+	}
+
+	if rebootUpdated && rollout != nil && rollout.hold {
+		logger.Info("rollout in progress, keep the current version", "Deploy", deploy.Name)
+		rebootUpdated = false
+	}
+
 	if rebootUpdated {
 		updateDeploy := handler.NewDeployment()
 		deploy.Spec = updateDeploy.Spec
+		deploy.Spec.Replicas = &size
+		setOverridesHash(deploy, OverridesHash(handler.workloadOverride()))
 		logger.Info("this update will cause rolling update", "Deploy", deploy.Name)
 	}
 
@@ -235,7 +298,8 @@ func (handler *BootHandler) reconcileStatefulSetUpdate() (*corev1.PodTemplateSpe
 	return &stsFound.Spec.Template, result, requeue, err
 }
 
-// innerReconcileUpdateDeploy handle update logic of StatefulSet
+// innerReconcileUpdateStatefulSet handle update logic of StatefulSet.
+// While a canary is in progress, only the ordinals above the partition are rolled to the new version.
 func (handler *BootHandler) innerReconcileUpdateStatefulSet(sts *appsv1.StatefulSet) (reconcile.Result, bool, error) {
 	logger := handler.Logger
 	boot := handler.Boot
@@ -264,15 +328,56 @@ func (handler *BootHandler) innerReconcileUpdateStatefulSet(sts *appsv1.Stateful
 		updated = true
 	}
 
-	// 3. Check pod spec
+	// 3. Check canary
+	rollout, canaryUpdated, err := handler.reconcileCanaryStatefulSet(sts)
+	if err != nil {
+		return reconcile.Result{Requeue: true}, true, err
+	}
+	if canaryUpdated {
+		updated = true
+	}
+
+	// 4. Check update strategy
+	updateStrategy := handler.NewStatefulSetUpdateStrategy()
+	if rollout != nil && rollout.partition != nil {
+		updateStrategy.RollingUpdate.Partition = rollout.partition
+	}
+	if !reflect.DeepEqual(sts.Spec.UpdateStrategy, updateStrategy) {
+		logger.Info(reason, "type", "updateStrategy", "statefulset", sts.Name,
+			"old", sts.Spec.UpdateStrategy, "new", updateStrategy)
+		sts.Spec.UpdateStrategy = updateStrategy
+
+		updated = true
+	}
+
+	// 4.1 Check the labels and annotations of workloadMetadata, the pods are not restarted
+	if applyMetadata(&sts.ObjectMeta, boot.Spec.WorkloadMetadata) {
+		logger.Info(reason, "type", "workloadMetadata", "statefulset", sts.Name)
+		updated = true
+	}
+
+	// 5. Check pod spec
 	restartUpdated, rebootUpdated, err := handler.reconcilePodTemplateSpecUpdate(&sts.Spec.Template)
 	if err != nil {
 		return reconcile.Result{Requeue: true}, true, err
 	}
 
+	// 5.1 Check the hash of the workload overrides, the spec is rebuilt with the patch
+	if overridesChanged(sts, handler.workloadOverride()) {
+		logger.Info(reason, "type", "overrides", "statefulset", sts.Name)
+		rebootUpdated = true
+	}
+
+	if rebootUpdated && rollout != nil && rollout.hold {
+		logger.Info("canary aborted, keep the current version", "statefulset", sts.Name)
+		rebootUpdated = false
+	}
+
 	if rebootUpdated {
 		updateSts := handler.NewStatefulSet()
 		sts.Spec = updateSts.Spec
+		sts.Spec.UpdateStrategy = updateStrategy
+		setOverridesHash(sts, OverridesHash(handler.workloadOverride()))
 		logger.Info("this update will cause rolling update", "statefulset", sts.Name)
 	}
 
@@ -305,6 +410,10 @@ func (handler *BootHandler) reconcilePodTemplateSpecUpdate(podSpec *corev1.PodTe
 	rebootUpdated := false
 	restartUpdated := false
 	reason := "Updating Workload PodTemplateSpec"
+	// The expected containers are built once, the first one is the app container
+	bootContainers := handler.NewContainers()
+	appContainer := &bootContainers[0]
+
 	// "spec.template.spec.containers" is a required value, no need to verify.
 	// 1. Check image and version:
 	workloadImg := podSpec.Spec.Containers[0].Image
@@ -326,12 +435,19 @@ func (handler *BootHandler) reconcilePodTemplateSpecUpdate(podSpec *corev1.PodTe
 		rebootUpdated = true
 	}
 
+	// 2.1 Check envFrom: check fist container(boot container)
+	workloadEnvFrom := podSpec.Spec.Containers[0].EnvFrom
+	bootEnvFrom := boot.Spec.EnvFrom
+	if (len(workloadEnvFrom) > 0 || len(bootEnvFrom) > 0) && !reflect.DeepEqual(workloadEnvFrom, bootEnvFrom) {
+		logger.Info(reason, "type", "envFrom",
+			"old", workloadEnvFrom, "new", bootEnvFrom)
+
+		rebootUpdated = true
+	}
+
 	// 3. Check port: check fist container(boot container)
 	workloadPorts := podSpec.Spec.Containers[0].Ports
-	bootPorts := []corev1.ContainerPort{{
-		Name:          HttpPortName,
-		ContainerPort: boot.Spec.Port,
-		Protocol:      corev1.ProtocolTCP}}
+	bootPorts := ContainerPorts(boot)
 	if !reflect.DeepEqual(workloadPorts, bootPorts) {
 		logger.Info(reason, "type", "port",
 			"old", workloadPorts, "new", bootPorts)
@@ -350,78 +466,29 @@ func (handler *BootHandler) reconcilePodTemplateSpecUpdate(podSpec *corev1.PodTe
 	}
 
 	// 5. Check liveness and readiness : check fist container(boot container)
+	// Every field of the probes is compared, include the handler, port, path, delays and thresholds.
+	bootLiveness, bootReadiness := appContainer.LivenessProbe, appContainer.ReadinessProbe
 	// 5.1 Check liveness
 	livenessProbe := podSpec.Spec.Containers[0].LivenessProbe
-	bootHealth := *boot.Spec.Health
-	if bootHealth == "" {
-		if livenessProbe != nil {
-			// Remove the 2 existing probes.
-			workloadHealth := livenessProbe.HTTPGet.Path
-			logger.Info(reason, "type", "health",
-				"old", workloadHealth, "new", "")
-
-			rebootUpdated = true
-		}
-	} else {
-		if livenessProbe == nil {
-			// 1. If probe is nil, add Liveness and Readiness
-			logger.Info(reason, "type", "health",
-				"old", "empty", "new", bootHealth)
-
-			rebootUpdated = true
-		} else {
-			workloadHealth := livenessProbe.HTTPGet.Path
-			// 2. If livenessProbe is not nil, we only need to update the health path
-			if workloadHealth != bootHealth {
-				logger.Info(reason, "type", "health",
-					"old", workloadHealth, "new", bootHealth)
+	if !reflect.DeepEqual(livenessProbe, bootLiveness) {
+		logger.Info(reason, "type", "liveness",
+			"old", livenessProbe, "new", bootLiveness)
 
-				rebootUpdated = true
-			}
-		}
+		rebootUpdated = true
 	}
 
 	// 5.2 Check readiness
 	readinessProbe := podSpec.Spec.Containers[0].ReadinessProbe
-	readinessPath := *boot.Spec.Health
-
-	// if boot.Spec.Health is empty, ignore the Readiness
-	if boot.Spec.Readiness != nil && *boot.Spec.Readiness != "" &&
-		boot.Spec.Health != nil && *boot.Spec.Health != "" {
-		readinessPath = *boot.Spec.Readiness
-	}
-
-	if readinessPath == "" {
-		if readinessProbe != nil {
-			// Remove the 2 existing probes.
-			workloadHealth := readinessProbe.HTTPGet.Path
-			logger.Info(reason, "type", "readiness",
-				"old", workloadHealth, "new", "")
+	if !reflect.DeepEqual(readinessProbe, bootReadiness) {
+		logger.Info(reason, "type", "readiness",
+			"old", readinessProbe, "new", bootReadiness)
 
-			rebootUpdated = true
-		}
-	} else {
-		if readinessProbe == nil {
-			// 1. If probe is nil, add  Readiness
-			logger.Info(reason, "type", "readiness",
-				"old", "empty", "new", readinessPath)
-
-			rebootUpdated = true
-		} else {
-			workloadHealth := readinessProbe.HTTPGet.Path
-			// 2. If readinessProbe is not nil, we only need to update the readiness path
-			if workloadHealth != readinessPath {
-				logger.Info(reason, "type", "readiness",
-					"old", workloadHealth, "new", readinessPath)
-
-				rebootUpdated = true
-			}
-		}
+		rebootUpdated = true
 	}
 
 	// 6. Check nodeSelector: map[string]string
 	workloadNodeSelector := podSpec.Spec.NodeSelector
-	bootNodeSelector := boot.Spec.NodeSelector
+	bootNodeSelector := handler.podNodeSelector()
 	if !reflect.DeepEqual(workloadNodeSelector, bootNodeSelector) {
 		logger.Info(reason, "type", "nodeSelector",
 			"old", workloadNodeSelector, "new", bootNodeSelector)
@@ -429,6 +496,22 @@ func (handler *BootHandler) reconcilePodTemplateSpecUpdate(podSpec *corev1.PodTe
 		rebootUpdated = true
 	}
 
+	// 6.1 Check tolerations and affinity: the node affinity, zone anti-affinity and topology spread constraints
+	bootTolerations, bootAffinity := handler.expectedScheduling()
+	if !reflect.DeepEqual(podSpec.Spec.Tolerations, bootTolerations) {
+		logger.Info(reason, "type", "tolerations",
+			"old", podSpec.Spec.Tolerations, "new", bootTolerations)
+
+		rebootUpdated = true
+	}
+
+	if !reflect.DeepEqual(podSpec.Spec.Affinity, bootAffinity) {
+		logger.Info(reason, "type", "affinity",
+			"old", podSpec.Spec.Affinity, "new", bootAffinity)
+
+		rebootUpdated = true
+	}
+
 	// 7. Check command
 	workloadCommand := podSpec.Spec.Containers[0].Command
 	bootCommand := boot.Spec.Command
@@ -439,6 +522,53 @@ func (handler *BootHandler) reconcilePodTemplateSpecUpdate(podSpec *corev1.PodTe
 		rebootUpdated = true
 	}
 
+	// 7.1 Check shutdown: the termination grace period and the preStop hook of the app container
+	workloadGracePeriod := int64(defaultTerminationGracePeriodSeconds)
+	if podSpec.Spec.TerminationGracePeriodSeconds != nil {
+		workloadGracePeriod = *podSpec.Spec.TerminationGracePeriodSeconds
+	}
+	bootGracePeriod := handler.expectedTerminationGracePeriod()
+	if workloadGracePeriod != bootGracePeriod {
+		logger.Info(reason, "type", "terminationGracePeriodSeconds",
+			"old", workloadGracePeriod, "new", bootGracePeriod)
+
+		rebootUpdated = true
+	}
+
+	workloadLifecycle := podSpec.Spec.Containers[0].Lifecycle
+	bootLifecycle := appContainer.Lifecycle
+	if !reflect.DeepEqual(workloadLifecycle, bootLifecycle) {
+		logger.Info(reason, "type", "lifecycle",
+			"old", workloadLifecycle, "new", bootLifecycle)
+
+		rebootUpdated = true
+	}
+
+	// 7.2 Check args, workingDir and imagePullPolicy
+	workloadArgs := podSpec.Spec.Containers[0].Args
+	if (len(workloadArgs) > 0 || len(appContainer.Args) > 0) && !reflect.DeepEqual(workloadArgs, appContainer.Args) {
+		logger.Info(reason, "type", "args",
+			"old", workloadArgs, "new", appContainer.Args)
+
+		rebootUpdated = true
+	}
+
+	workloadWorkingDir := podSpec.Spec.Containers[0].WorkingDir
+	if workloadWorkingDir != appContainer.WorkingDir {
+		logger.Info(reason, "type", "workingDir",
+			"old", workloadWorkingDir, "new", appContainer.WorkingDir)
+
+		rebootUpdated = true
+	}
+
+	workloadPullPolicy := podSpec.Spec.Containers[0].ImagePullPolicy
+	if workloadPullPolicy != appContainer.ImagePullPolicy {
+		logger.Info(reason, "type", "imagePullPolicy",
+			"old", workloadPullPolicy, "new", appContainer.ImagePullPolicy)
+
+		rebootUpdated = true
+	}
+
 	// 8. Check vol
 	workloadVols := podSpec.Spec.Containers[0].VolumeMounts
 	bootVolStr, ok := boot.Annotations[keys.BootDeployPvcsAnnotationKey]
@@ -455,7 +585,7 @@ func (handler *BootHandler) reconcilePodTemplateSpecUpdate(podSpec *corev1.PodTe
 			logger.Info("Boot VolumeMounts change.",
 				"deleted", deleted, "added", added, "modified", modified)
 
-			volUpdated, err := handler.checkVolumeMountUpdate(deleted, added, modified)
+			volUpdated, err := handler.checkVolumeMountUpdate(podSpec.Spec.Volumes, deleted, added, modified)
 			if err != nil {
 				logger.Error(err, "Fail to reconcile VolumeMounts",
 					"deleted", deleted, "added", added, "modified", modified)
@@ -475,6 +605,14 @@ func (handler *BootHandler) reconcilePodTemplateSpecUpdate(podSpec *corev1.PodTe
 		// rebootUpdated = true
 	}
 
+	// 8.1 Check boot's volumes: configMap/secret/emptyDir/projected source
+	bootVolumes := ConvertBootVolume(boot.Spec.Volumes)
+	if !BootVolumesEq(podSpec.Spec.Volumes, bootVolumes) {
+		logger.Info(reason, "type", "Volumes",
+			"old", podSpec.Spec.Volumes, "new", bootVolumes)
+		rebootUpdated = true
+	}
+
 	// 9. Check RestartedAt
 	if bootRestartedAt, ok := boot.Annotations[keys.BootRestartedAtAnnotationKey]; ok {
 		if podSpec.Annotations == nil {
@@ -488,23 +626,101 @@ func (handler *BootHandler) reconcilePodTemplateSpecUpdate(podSpec *corev1.PodTe
 		}
 	}
 
+	// 9.1 Check the hash of the referenced Secrets and ConfigMaps
+	configHashUpdated, err := handler.reconcileConfigHash(podSpec)
+	if err != nil {
+		logger.Error(err, "Failed to calculate the config hash")
+		return true, true, err
+	}
+	if configHashUpdated {
+		restartUpdated = true
+	}
+
+	// 9.2 Check the labels and annotations of podMetadata
+	if applyMetadata(&podSpec.ObjectMeta, boot.Spec.PodMetadata) {
+		logger.Info(reason, "type", "podMetadata", "new", boot.Spec.PodMetadata)
+		restartUpdated = true
+	}
+
 	// 10. Check Priority
-	if podSpec.Spec.PriorityClassName != boot.Spec.Priority {
+	bootPriority := handler.podPriority()
+	if podSpec.Spec.PriorityClassName != bootPriority {
 		logger.Info(reason, "type", "Priority",
-			"old", podSpec.Spec.PriorityClassName, "new", boot.Spec.Priority)
+			"old", podSpec.Spec.PriorityClassName, "new", bootPriority)
+		rebootUpdated = true
+	}
+
+	// 10.1 Check ServiceAccount
+	bootServiceAccount := ServiceAccountName(boot)
+	if podSpec.Spec.ServiceAccountName != bootServiceAccount {
+		logger.Info(reason, "type", "serviceAccountName",
+			"old", podSpec.Spec.ServiceAccountName, "new", bootServiceAccount)
+		rebootUpdated = true
+	}
+
+	bootAutomountToken := AutomountServiceAccountToken(boot)
+	if !reflect.DeepEqual(podSpec.Spec.AutomountServiceAccountToken, bootAutomountToken) {
+		logger.Info(reason, "type", "automountServiceAccountToken",
+			"old", podSpec.Spec.AutomountServiceAccountToken, "new", bootAutomountToken)
+		rebootUpdated = true
+	}
+
+	// 10.2 Check imagePullSecrets
+	var cfgPullSecrets []corev1.LocalObjectReference
+	if handler.Config.AppSpec.PodSpec != nil {
+		cfgPullSecrets = handler.Config.AppSpec.PodSpec.ImagePullSecrets
+	}
+	bootPullSecrets := PodImagePullSecrets(boot, handler.Config.AppSpec, cfgPullSecrets)
+	if (len(podSpec.Spec.ImagePullSecrets) > 0 || len(bootPullSecrets) > 0) &&
+		!reflect.DeepEqual(podSpec.Spec.ImagePullSecrets, bootPullSecrets) {
+		logger.Info(reason, "type", "imagePullSecrets",
+			"old", podSpec.Spec.ImagePullSecrets, "new", bootPullSecrets)
 		rebootUpdated = true
 	}
 
+	// 10.3 Check securityContext: the pod's, the containers' and the init containers'
+	workloadSecurityContext := podSpec.Spec.SecurityContext
+	if workloadSecurityContext == nil {
+		workloadSecurityContext = &corev1.PodSecurityContext{}
+	}
+	bootSecurityContext := handler.expectedPodSecurityContext()
+	if !reflect.DeepEqual(workloadSecurityContext, bootSecurityContext) {
+		logger.Info(reason, "type", "securityContext",
+			"old", workloadSecurityContext, "new", bootSecurityContext)
+		rebootUpdated = true
+	}
+
+	if len(podSpec.Spec.Containers) == len(bootContainers) {
+		for i, container := range podSpec.Spec.Containers {
+			if !reflect.DeepEqual(container.SecurityContext, bootContainers[i].SecurityContext) {
+				logger.Info(reason, "type", "containerSecurityContext", "container", container.Name,
+					"old", container.SecurityContext, "new", bootContainers[i].SecurityContext)
+				rebootUpdated = true
+			}
+		}
+	}
+
+	bootInitContainers := handler.expectedInitContainers()
+	if len(podSpec.Spec.InitContainers) == len(bootInitContainers) {
+		for i, container := range podSpec.Spec.InitContainers {
+			if !reflect.DeepEqual(container.SecurityContext, bootInitContainers[i].SecurityContext) {
+				logger.Info(reason, "type", "initContainerSecurityContext", "container", container.Name,
+					"old", container.SecurityContext, "new", bootInitContainers[i].SecurityContext)
+				rebootUpdated = true
+			}
+		}
+	}
+
 	return restartUpdated, rebootUpdated, nil
 }
 
-// getWorkloadStatus will return ReadyReplicas and CurrentReplicas
+// getWorkloadStatus will return ReadyReplicas and CurrentReplicas of the workload selected by the app Service
 func (handler *BootHandler) getWorkloadStatus() (int32, int32, error) {
 	logger := handler.Logger
 	boot := handler.Boot
 	c := handler.Client
 
-	workloadName := WorkloadName(boot)
+	workloadName := handler.activeWorkloadName()
 	if boot.Spec.Workload == v1.Deployment || boot.Spec.Workload == "" {
 		dep := &appsv1.Deployment{}
 		err := c.Get(context.TODO(), types.NamespacedName{Name: workloadName, Namespace: boot.Namespace}, dep)
@@ -0,0 +1,62 @@
+package operator
+
+import (
+	"testing"
+
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	"github.com/logancloud/logan-app-operator/pkg/logan"
+	"github.com/logancloud/logan-app-operator/pkg/logan/config"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestOperator(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Operator Suite")
+}
+
+// newTestBoot return a java boot in the test namespace
+func newTestBoot(name string) *appv1.Boot {
+	replicas := int32(2)
+	boot := &appv1.Boot{
+		Spec: appv1.BootSpec{
+			Image:    "logan/" + name,
+			Version:  "v1",
+			Replicas: &replicas,
+			Port:     8080,
+		},
+		BootType: logan.BootJava,
+	}
+	boot.Name = name
+	boot.Namespace = "test"
+	boot.Kind = "JavaBoot"
+	return boot
+}
+
+// newTestHandler return a BootHandler of the boot with the app config, whose client is a fake client of the objects
+func newTestHandler(boot *appv1.Boot, appSpec *config.AppSpec, objs ...runtime.Object) *BootHandler {
+	scheme := runtime.NewScheme()
+	Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	Expect(appv1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+
+	if appSpec == nil {
+		appSpec = &config.AppSpec{}
+	}
+
+	return &BootHandler{
+		OperatorSpec: &boot.Spec,
+		OperatorMeta: &boot.ObjectMeta,
+		Boot:         boot,
+		Config:       &config.BootConfig{AppSpec: appSpec},
+		Scheme:       scheme,
+		Client:       util.NewClient(fake.NewFakeClientWithScheme(scheme, objs...)),
+		Logger:       logf.Log.WithName("operator_test"),
+		Recorder:     record.NewFakeRecorder(100),
+	}
+}
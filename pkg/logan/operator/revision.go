@@ -1,24 +1,51 @@
 package operator
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	v1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
 	"github.com/logancloud/logan-app-operator/pkg/logan/config"
-	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/yaml"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
+	"strings"
+	"time"
 )
 
 const (
 	// RevisionPhaseRunning is the revision phase for Running
 	RevisionPhaseRunning = "Running"
+	// RevisionPhaseCanary is the revision phase for Canary, the revision is running next to the stable one
+	RevisionPhaseCanary = "Canary"
 	// RevisionPhaseActive is the revision phase for Active
 	RevisionPhaseActive = "Active"
 	// RevisionPhaseComplete is the revision phase for Complete
 	RevisionPhaseComplete = "Complete"
 	// RevisionPhaseCancel is the revision phase for Cancelled
 	RevisionPhaseCancel = "Cancelled"
+	// RevisionPhaseFailed is the revision phase for Failed, the revision failed to become Active
+	RevisionPhaseFailed = "Failed"
+)
+
+const (
+	// envChangePath is the path prefix of the changes of the envs
+	envChangePath = "spec.env["
+	// redactedValue is the value of the changes of the envs from secrets
+	redactedValue = "<redacted>"
+)
+
+const (
+	// failureRestartCount is the restart count of a container in CrashLoopBackOff to fail the running revision
+	failureRestartCount = 3
+	// failureImagePullTime is how long a pod in ImagePullBackOff lives to fail the running revision
+	failureImagePullTime = 5 * time.Minute
+	// revisionPendingRequeue is the time to requeue for the revision of the boot's current spec to be recorded
+	revisionPendingRequeue = 5 * time.Second
 )
 
 // InitBootRevision will init a revision from boot
@@ -50,30 +77,105 @@ func initRevisionAnnotations(boot *v1.Boot) map[string]string {
 	return map[string]string{}
 }
 
-// RevisionDiff will compute the diff with two revision
-func RevisionDiff(current, latest v1.BootRevision) string {
-	currentCopy := current.DeepCopy()
-	currentCopy.ObjectMeta = metav1.ObjectMeta{}
-	currentYaml, _ := yaml.Marshal(currentCopy)
+// RevisionChanges will compute the field-level changes of the current revision's spec from the previous revision,
+// the values of the envs from secrets are redacted
+func RevisionChanges(current, previous *v1.BootRevision) ([]v1.BootRevisionChange, error) {
+	changes, err := util.FieldChanges(
+		map[string]interface{}{"spec": previous.Spec},
+		map[string]interface{}{"spec": current.Spec})
+	if err != nil {
+		return nil, err
+	}
 
-	latestCopy := latest.DeepCopy()
-	latestCopy.ObjectMeta = metav1.ObjectMeta{}
-	latestYaml, _ := yaml.Marshal(latestCopy)
+	redactSecretEnvs(changes, previous.Spec.Env, current.Spec.Env)
+	return changes, nil
+}
 
-	dmp := diffmatchpatch.New()
-	diffs := dmp.DiffMain(string(latestYaml), string(currentYaml), false)
-	log, err := marshalChangelog(diffs)
+// CompareRevisions will compute the changes of the boot's revision currentId from the revision previousId, which can
+// be any two revisions of the boot
+func CompareRevisions(c *util.K8SClient, boot *v1.Boot, previousId, currentId int) ([]v1.BootRevisionChange, error) {
+	revisionLst, err := c.ListRevision(boot.Namespace, PodLabels(boot))
 	if err != nil {
-		return ""
+		return nil, err
+	}
+
+	previous := revisionLst.SelectRevision(previousId)
+	if previous == nil {
+		return nil, fmt.Errorf("revision %d of boot %s not found", previousId, boot.Name)
+	}
+	current := revisionLst.SelectRevision(currentId)
+	if current == nil {
+		return nil, fmt.Errorf("revision %d of boot %s not found", currentId, boot.Name)
 	}
-	return log
+
+	return RevisionChanges(current, previous)
 }
 
-// marshalChangelog marshal the []diffmatchpatch.Diff to string
-func marshalChangelog(changelog []diffmatchpatch.Diff) (string, error) {
-	changelogSe, err := json.Marshal(changelog)
-	changelogStr := fmt.Sprintf("%s", changelogSe)
-	return changelogStr, err
+// ListRevisions will list the revisions in the namespace changed by the author, sorted by the boots and IDs. Only the
+// revisions of the boot are listed if it is not nil, and the revisions of all the authors if the author is empty
+func ListRevisions(c *util.K8SClient, namespace string, boot *v1.Boot, author string) ([]v1.BootRevision, error) {
+	labels := map[string]string{}
+	if boot != nil {
+		labels = PodLabels(boot)
+	}
+
+	revisionLst, err := c.ListRevision(namespace, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]v1.BootRevision, 0, len(revisionLst.Items))
+	for _, revision := range revisionLst.Items {
+		if author == "" || revision.Annotations[keys.BootChangedByAnnotationKey] == author {
+			revisions = append(revisions, revision)
+		}
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		iBoot := revisions[i].Labels[keys.BootNameKey]
+		jBoot := revisions[j].Labels[keys.BootNameKey]
+		if iBoot != jBoot {
+			return iBoot < jBoot
+		}
+		return revisions[i].GetRevisionId() < revisions[j].GetRevisionId()
+	})
+	return revisions, nil
+}
+
+// redactSecretEnvs replace the changed values of the envs from secrets. The envs are indexed by the names in the
+// paths, the values are redacted if any env is from secrets when they are indexed by the positions
+func redactSecretEnvs(changes []v1.BootRevisionChange, previousEnvs, currentEnvs []corev1.EnvVar) {
+	previousSecrets, previousNames := secretEnvNames(previousEnvs)
+	currentSecrets, currentNames := secretEnvNames(currentEnvs)
+
+	for i := range changes {
+		change := &changes[i]
+		if !strings.HasPrefix(change.Path, envChangePath) {
+			continue
+		}
+
+		name := strings.SplitN(strings.TrimPrefix(change.Path, envChangePath), "]", 2)[0]
+		named := previousNames[name] || currentNames[name]
+		if change.Old != "" && (previousSecrets[name] || (!named && len(previousSecrets) > 0)) {
+			change.Old = redactedValue
+		}
+		if change.New != "" && (currentSecrets[name] || (!named && len(currentSecrets) > 0)) {
+			change.New = redactedValue
+		}
+	}
+}
+
+// secretEnvNames return the names of the envs from secrets, and the names of all the envs
+func secretEnvNames(envs []corev1.EnvVar) (map[string]bool, map[string]bool) {
+	secrets := make(map[string]bool)
+	names := make(map[string]bool)
+	for _, env := range envs {
+		names[env.Name] = true
+		if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+			secrets[env.Name] = true
+		}
+	}
+	return secrets, names
 }
 
 func updateRevisionAnnotation(revision *v1.BootRevision, revisionAnnotationMap map[string]string) bool {
@@ -96,3 +198,176 @@ func updateRevisionAnnotation(revision *v1.BootRevision, revisionAnnotationMap m
 	}
 	return updated
 }
+
+// revisionOfBoot return whether the revision is recorded from the boot's current spec. The revision controller
+// records the new revision asynchronously, the latest revision may still be the previous one.
+func revisionOfBoot(revision *v1.BootRevision, boot *v1.Boot) bool {
+	return revision.Annotations[keys.BootRevisionHashAnnotationKey] == InitBootRevision(boot).BootHash()
+}
+
+// updateLatestRevisionPhase will set the phase of the boot's latest revision
+func (handler *BootHandler) updateLatestRevisionPhase(phase string) error {
+	return handler.updateLatestRevisionAnnotation(map[string]string{keys.BootRevisionPhaseAnnotationKey: phase})
+}
+
+// updateLatestRevisionAnnotation will set the annotations of the boot's latest revision
+func (handler *BootHandler) updateLatestRevisionAnnotation(revisionAnnotationMap map[string]string) error {
+	boot := handler.Boot
+	c := handler.Client
+
+	revisionLst, err := c.ListRevision(boot.Namespace, PodLabels(boot))
+	if err != nil {
+		return err
+	}
+	latestRevision := revisionLst.SelectLatestRevision()
+	if latestRevision == nil || !revisionOfBoot(latestRevision, boot) {
+		return nil
+	}
+
+	if !updateRevisionAnnotation(latestRevision, revisionAnnotationMap) {
+		return nil
+	}
+	handler.Logger.Info("Updating Boot Revision Meta", "new", revisionAnnotationMap, "revision", latestRevision.Name)
+	return c.Update(context.TODO(), latestRevision)
+}
+
+// latestRevision return the boot's latest revision, nil if not found
+func (handler *BootHandler) latestRevision() *v1.BootRevision {
+	boot := handler.Boot
+
+	revisionLst, err := handler.Client.ListRevision(boot.Namespace, PodLabels(boot))
+	if err != nil {
+		return nil
+	}
+	return revisionLst.SelectLatestRevision()
+}
+
+// revisionFailure return the reason why the running revision fails to become Active, empty if not failed.
+// The revision fails if its pods keep crashing after failureRestartCount restarts or stay in ImagePullBackOff
+// for failureImagePullTime, the workload exceeded its progress deadline, or the revision is not Active within
+// the strategy's activeDeadlineSeconds. The pods of the previous revisions are not considered.
+func (handler *BootHandler) revisionFailure(revision *v1.BootRevision, pods []corev1.Pod) string {
+	revisionPods, err := handler.revisionPods(pods)
+	if err != nil {
+		handler.Logger.Info("Failed to select the revision's pods", "err", err.Error())
+	}
+	for _, pod := range revisionPods {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Waiting == nil {
+				continue
+			}
+			reason := status.State.Waiting.Reason
+			if (reason == "CrashLoopBackOff" && status.RestartCount >= failureRestartCount) ||
+				(reason == "ImagePullBackOff" && time.Since(pod.CreationTimestamp.Time) >= failureImagePullTime) {
+				return fmt.Sprintf("container %s of pod %s is in %s", status.Name, pod.Name, reason)
+			}
+		}
+	}
+
+	_, deadlineExceeded, err := handler.getWorkloadRolloutStatus()
+	if err == nil && deadlineExceeded {
+		return "workload has exceeded its progress deadline"
+	}
+
+	deadline := handler.revisionDeadline()
+	if deadline > 0 && time.Since(revision.CreationTimestamp.Time) >= deadline {
+		return fmt.Sprintf("revision is not Active in %s", deadline)
+	}
+	return ""
+}
+
+// revisionPods return the pods running the boot's current pod template. The pods of a StatefulSet are selected
+// by its update revision, and the pods of the Deployments by the ReplicaSets which are not outdated.
+func (handler *BootHandler) revisionPods(pods []corev1.Pod) ([]corev1.Pod, error) {
+	boot := handler.Boot
+	c := handler.Client
+
+	selected := make([]corev1.Pod, 0, len(pods))
+	if boot.Spec.Workload == v1.StatefulSet {
+		sts := &appsv1.StatefulSet{}
+		err := c.Get(context.TODO(), types.NamespacedName{Name: WorkloadName(boot), Namespace: boot.Namespace}, sts)
+		if err != nil {
+			return nil, err
+		}
+		for _, pod := range pods {
+			if pod.Labels[appsv1.ControllerRevisionHashLabelKey] == sts.Status.UpdateRevision {
+				selected = append(selected, pod)
+			}
+		}
+		return selected, nil
+	}
+
+	rsList := &appsv1.ReplicaSetList{}
+	err := c.List(context.TODO(), rsList,
+		client.InNamespace(boot.Namespace),
+		client.MatchingLabels(PodLabels(boot)))
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]bool)
+	for i := range rsList.Items {
+		rs := &rsList.Items[i]
+		outdated, err := handler.workloadOutdated(&rs.Spec.Template)
+		if err != nil {
+			return nil, err
+		}
+		if !outdated {
+			current[rs.Name] = true
+		}
+	}
+	for i := range pods {
+		owner := metav1.GetControllerOf(&pods[i])
+		if owner != nil && owner.Kind == "ReplicaSet" && current[owner.Name] {
+			selected = append(selected, pods[i])
+		}
+	}
+	return selected, nil
+}
+
+// revisionDeadline return the strategy's activeDeadlineSeconds as duration, 0 if not set
+func (handler *BootHandler) revisionDeadline() time.Duration {
+	strategy := handler.bootStrategy()
+	if strategy == nil || strategy.ActiveDeadlineSeconds == nil {
+		return 0
+	}
+	return time.Duration(*strategy.ActiveDeadlineSeconds) * time.Second
+}
+
+// revisionRequeueAfter return the time to requeue for checking the running revision's deadline, 0 if no need
+func (handler *BootHandler) revisionRequeueAfter(revision *v1.BootRevision) time.Duration {
+	if revision == nil || revision.Annotations[keys.BootRevisionPhaseAnnotationKey] != RevisionPhaseRunning {
+		return 0
+	}
+	deadline := handler.revisionDeadline()
+	if deadline == 0 {
+		return 0
+	}
+	if requeueAfter := time.Until(revision.CreationTimestamp.Add(deadline)); requeueAfter > 0 {
+		return requeueAfter
+	}
+	return 0
+}
+
+// autoRollbackRevision return the last Complete revision to rollback to when the latest revision is Failed,
+// nil if autoRollback is disabled or not found. The revision created by a rollback is not rolled back again.
+func (handler *BootHandler) autoRollbackRevision(revisionLst *v1.BootRevisionList, failed *v1.BootRevision) *v1.BootRevision {
+	strategy := handler.bootStrategy()
+	if strategy == nil || !strategy.AutoRollback {
+		return nil
+	}
+	if strings.HasPrefix(failed.Annotations[keys.BootChangeCauseAnnotationKey], rollbackChangeCausePrefix) {
+		return nil
+	}
+
+	var ret *v1.BootRevision
+	for i := range revisionLst.Items {
+		item := &revisionLst.Items[i]
+		if item.Annotations[keys.BootRevisionPhaseAnnotationKey] != RevisionPhaseComplete {
+			continue
+		}
+		if ret == nil || item.GetRevisionId() > ret.GetRevisionId() {
+			ret = item
+		}
+	}
+	return ret
+}
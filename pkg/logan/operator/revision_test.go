@@ -0,0 +1,138 @@
+package operator
+
+import (
+	v1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strconv"
+	"time"
+)
+
+var _ = Describe("Revision", func() {
+	// revision return the revision of the id in the phase
+	revision := func(id int, phase string, changeCause string) v1.BootRevision {
+		r := v1.BootRevision{}
+		r.Annotations = map[string]string{
+			keys.BootRevisionIdAnnotationKey:    strconv.Itoa(id),
+			keys.BootRevisionPhaseAnnotationKey: phase,
+		}
+		if changeCause != "" {
+			r.Annotations[keys.BootChangeCauseAnnotationKey] = changeCause
+		}
+		return r
+	}
+
+	DescribeTable("autoRollbackRevision return the last Complete revision",
+		func(autoRollback bool, items []v1.BootRevision, expectedId int) {
+			boot := newTestBoot("revision")
+			boot.Spec.Strategy = &v1.BootStrategy{AutoRollback: autoRollback}
+			handler := newTestHandler(boot, nil)
+
+			lst := &v1.BootRevisionList{Items: items}
+			failed := lst.SelectLatestRevision()
+			target := handler.autoRollbackRevision(lst, failed)
+			if expectedId == 0 {
+				Expect(target).To(BeNil())
+			} else {
+				Expect(target.GetRevisionId()).To(Equal(expectedId))
+			}
+		},
+		Entry("disabled", false, []v1.BootRevision{
+			revision(1, RevisionPhaseComplete, ""), revision(2, RevisionPhaseFailed, "")}, 0),
+		Entry("the last Complete", true, []v1.BootRevision{
+			revision(1, RevisionPhaseComplete, ""), revision(2, RevisionPhaseCancel, ""),
+			revision(3, RevisionPhaseComplete, ""), revision(4, RevisionPhaseFailed, "")}, 3),
+		Entry("no Complete", true, []v1.BootRevision{
+			revision(1, RevisionPhaseCancel, ""), revision(2, RevisionPhaseFailed, "")}, 0),
+		Entry("the rollback is not rolled back again", true, []v1.BootRevision{
+			revision(1, RevisionPhaseComplete, ""),
+			revision(2, RevisionPhaseFailed, rollbackChangeCausePrefix+"1")}, 0),
+	)
+
+	Context("With the running revision of StatefulSet", func() {
+		var handler *BootHandler
+		var running *v1.BootRevision
+
+		// pod return the pod of the StatefulSet's revision, waiting for the reason
+		pod := func(name string, stsRevision string, reason string, restarts int32, age time.Duration) corev1.Pod {
+			p := corev1.Pod{}
+			p.Name = name
+			p.Labels = map[string]string{appsv1.ControllerRevisionHashLabelKey: stsRevision}
+			p.CreationTimestamp = metav1.NewTime(time.Now().Add(-age))
+			p.Status.ContainerStatuses = []corev1.ContainerStatus{
+				{
+					Name:         "app",
+					RestartCount: restarts,
+					State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason}},
+				},
+			}
+			return p
+		}
+
+		BeforeEach(func() {
+			replicas := int32(2)
+			boot := newTestBoot("revision")
+			boot.Spec.Workload = v1.StatefulSet
+			sts := &appsv1.StatefulSet{}
+			sts.Name = WorkloadName(boot)
+			sts.Namespace = boot.Namespace
+			sts.Spec.Replicas = &replicas
+			sts.Status.CurrentRevision = "r1"
+			sts.Status.UpdateRevision = "r2"
+
+			handler = newTestHandler(boot, nil, sts)
+			running = &v1.BootRevision{}
+			running.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Minute))
+		})
+
+		It("test the previous revision's pods are not considered", func() {
+			pods := []corev1.Pod{pod("revision-0", "r1", "CrashLoopBackOff", 10, time.Hour)}
+			Expect(handler.revisionFailure(running, pods)).To(BeEmpty())
+		})
+
+		It("test the pods keep crashing", func() {
+			pods := []corev1.Pod{pod("revision-1", "r2", "CrashLoopBackOff", failureRestartCount-1, time.Minute)}
+			Expect(handler.revisionFailure(running, pods)).To(BeEmpty())
+
+			pods = []corev1.Pod{pod("revision-1", "r2", "CrashLoopBackOff", failureRestartCount, time.Minute)}
+			Expect(handler.revisionFailure(running, pods)).To(ContainSubstring("CrashLoopBackOff"))
+		})
+
+		It("test the image can not be pulled", func() {
+			pods := []corev1.Pod{pod("revision-1", "r2", "ImagePullBackOff", 0, time.Minute)}
+			Expect(handler.revisionFailure(running, pods)).To(BeEmpty())
+
+			pods = []corev1.Pod{pod("revision-1", "r2", "ImagePullBackOff", 0, failureImagePullTime)}
+			Expect(handler.revisionFailure(running, pods)).To(ContainSubstring("ImagePullBackOff"))
+		})
+
+		It("test the revision is not Active within the deadline", func() {
+			deadline := int32(120)
+			handler.Boot.Spec.Strategy = &v1.BootStrategy{ActiveDeadlineSeconds: &deadline}
+			Expect(handler.revisionFailure(running, nil)).To(BeEmpty())
+			Expect(handler.revisionRequeueAfter(running)).To(BeZero())
+
+			running.Annotations = map[string]string{keys.BootRevisionPhaseAnnotationKey: RevisionPhaseRunning}
+			Expect(handler.revisionRequeueAfter(running)).To(BeNumerically("~", time.Minute, 2*time.Second))
+
+			running.CreationTimestamp = metav1.NewTime(time.Now().Add(-3 * time.Minute))
+			Expect(handler.revisionFailure(running, nil)).To(ContainSubstring("not Active"))
+			Expect(handler.revisionRequeueAfter(running)).To(BeZero())
+		})
+	})
+
+	It("test the revision of the boot", func() {
+		boot := newTestBoot("revision")
+		r := InitBootRevision(boot)
+		r.Annotations[keys.BootRevisionHashAnnotationKey] = r.BootHash()
+		Expect(revisionOfBoot(r, boot)).To(BeTrue())
+
+		boot.Spec.Version = "v2"
+		Expect(revisionOfBoot(r, boot)).To(BeFalse())
+	})
+})
@@ -0,0 +1,314 @@
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// DigestHeader is the header of the registry's manifest response carrying the manifest's digest
+	DigestHeader = "Docker-Content-Digest"
+
+	defaultDomain       = "docker.io"
+	defaultRegistryHost = "registry-1.docker.io"
+	officialRepoPrefix  = "library/"
+	defaultTimeout      = 10 * time.Second
+)
+
+// manifestMediaTypes are the accepted manifest types, the manifest list is preferred to keep multi-arch images.
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+}
+
+var digestRegexp = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// ValidDigest return whether the digest is a valid sha256 digest, such as `sha256:<64 hex>`
+func ValidDigest(digest string) bool {
+	return digestRegexp.MatchString(digest)
+}
+
+// Credential is the username and password to pull from a registry
+type Credential struct {
+	Username string
+	Password string
+}
+
+// Keychain are the credentials keyed by the registry host, such as `registry.logan.local`
+type Keychain map[string]Credential
+
+// dockerConfig is the content of the `.dockerconfigjson` of the image pull secret,
+// the legacy `.dockercfg` is the content of its auths
+type dockerConfig struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+type dockerConfigEntry struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+// ParseDockerConfig return the keychain of the image pull secret's data, which is the `.dockerconfigjson`
+// if dockerConfigJSON is true, otherwise the legacy `.dockercfg`
+func ParseDockerConfig(data []byte, dockerConfigJSON bool) (Keychain, error) {
+	var auths map[string]dockerConfigEntry
+	if dockerConfigJSON {
+		config := dockerConfig{}
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, err
+		}
+		auths = config.Auths
+	} else if err := json.Unmarshal(data, &auths); err != nil {
+		return nil, err
+	}
+
+	keychain := make(Keychain)
+	for server, entry := range auths {
+		cred := Credential{Username: entry.Username, Password: entry.Password}
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("invalid auth of registry %s: %v", server, err)
+			}
+			parts := strings.SplitN(string(decoded), ":", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("invalid auth of registry %s", server)
+			}
+			cred = Credential{Username: parts[0], Password: parts[1]}
+		}
+		keychain[registryHost(server)] = cred
+	}
+	return keychain, nil
+}
+
+// registryHost return the registry host of the docker config's server, such as `https://index.docker.io/v1/`
+func registryHost(server string) string {
+	host := server
+	if i := strings.Index(host, "://"); i != -1 {
+		host = host[i+3:]
+	}
+	if i := strings.IndexRune(host, '/'); i != -1 {
+		host = host[:i]
+	}
+	if host == defaultDomain || host == "index.docker.io" {
+		return defaultRegistryHost
+	}
+	return host
+}
+
+// Resolver resolves the image's tag to the immutable digest
+type Resolver interface {
+	// Resolve return the digest of the image's tag, image is the repository without tag, such as `registry/app`.
+	// The credential of the image's registry in the keychain is used if the registry requires authentication.
+	Resolve(image, tag string, keychain Keychain) (string, error)
+}
+
+// Client is a Resolver using the docker registry HTTP API V2.
+// The basic authentication and the bearer token are supported, the token is anonymous if there is no credential.
+type Client struct {
+	// HTTPClient is the client to send the requests, defaults to a client with 10s timeout
+	HTTPClient *http.Client
+	// Insecure are the registry hosts accessed with plain HTTP, the loopback hosts are always insecure.
+	Insecure []string
+}
+
+var _ Resolver = &Client{}
+
+// NewClient return a new registry client
+func NewClient(insecure ...string) *Client {
+	return &Client{
+		HTTPClient: &http.Client{Timeout: defaultTimeout},
+		Insecure:   insecure,
+	}
+}
+
+// SplitImage split the image into the registry host and the repository path, defaults to the docker hub.
+func SplitImage(image string) (string, string) {
+	i := strings.IndexRune(image, '/')
+	if i == -1 || (!strings.ContainsAny(image[:i], ".:") && image[:i] != "localhost") {
+		repo := image
+		if !strings.ContainsRune(repo, '/') {
+			repo = officialRepoPrefix + repo
+		}
+		return defaultRegistryHost, repo
+	}
+
+	host := image[:i]
+	if host == defaultDomain {
+		host = defaultRegistryHost
+	}
+	return host, image[i+1:]
+}
+
+// Resolve return the digest of the image's tag by HEAD of the tag's manifest
+func (c *Client) Resolve(image, tag string, keychain Keychain) (string, error) {
+	if tag == "" {
+		return "", fmt.Errorf("image %s has no tag to resolve", image)
+	}
+
+	host, repo := SplitImage(image)
+	manifestURL := fmt.Sprintf("%s://%s/v2/%s/manifests/%s", c.scheme(host), host, repo, tag)
+
+	resp, err := c.headManifest(manifestURL, "")
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		var cred *Credential
+		if found, ok := keychain[host]; ok {
+			cred = &found
+		}
+		authorization, err := c.authorization(resp.Header.Get("WWW-Authenticate"), cred)
+		if err != nil {
+			return "", err
+		}
+		resp, err = c.headManifest(manifestURL, authorization)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to resolve %s:%s, registry returned %s", image, tag, resp.Status)
+	}
+
+	digest := resp.Header.Get(DigestHeader)
+	if !ValidDigest(digest) {
+		return "", fmt.Errorf("failed to resolve %s:%s, registry returned invalid digest %q", image, tag, digest)
+	}
+	return digest, nil
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return &http.Client{Timeout: defaultTimeout}
+}
+
+func (c *Client) scheme(host string) string {
+	for _, insecure := range c.Insecure {
+		if insecure == host {
+			return "http"
+		}
+	}
+
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+	if hostname == "localhost" {
+		return "http"
+	}
+	if ip := net.ParseIP(hostname); ip != nil && ip.IsLoopback() {
+		return "http"
+	}
+	return "https"
+}
+
+func (c *Client) headManifest(manifestURL string, authorization string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	return resp, nil
+}
+
+// authorization return the Authorization header answering the registry's challenge with the credential.
+// The basic challenge requires the credential, the bearer token is anonymous if the credential is nil.
+func (c *Client) authorization(challenge string, cred *Credential) (string, error) {
+	if strings.HasPrefix(challenge, "Basic ") {
+		if cred == nil {
+			return "", fmt.Errorf("registry requires basic authentication, but no credential found")
+		}
+		return "Basic " + basicAuth(cred), nil
+	}
+
+	token, err := c.token(challenge, cred)
+	if err != nil {
+		return "", err
+	}
+	return "Bearer " + token, nil
+}
+
+func basicAuth(cred *Credential) string {
+	return base64.StdEncoding.EncodeToString([]byte(cred.Username + ":" + cred.Password))
+}
+
+// token request a bearer token from the realm of the challenge, anonymous if the credential is nil,
+// such as `Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/nginx:pull"`
+func (c *Client) token(challenge string, cred *Credential) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("unsupported registry authentication: %q", challenge)
+	}
+
+	params := make(map[string]string)
+	for _, param := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(kv) == 2 {
+			params[kv[0]] = strings.Trim(kv[1], `"`)
+		}
+	}
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("registry authentication has no realm: %q", challenge)
+	}
+
+	query := url.Values{}
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	if params["scope"] != "" {
+		query.Set("scope", params["scope"])
+	}
+
+	req, err := http.NewRequest(http.MethodGet, realm+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	if cred != nil {
+		req.Header.Set("Authorization", "Basic "+basicAuth(cred))
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get registry token, returned %s", resp.Status)
+	}
+
+	body := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	if err != nil {
+		return "", err
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
@@ -0,0 +1,155 @@
+package registry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	digestV1 = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	digestV2 = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+// newLocalRegistry return a registry stand-in serving the manifests of the tags, protected by a bearer token if set,
+// which is only issued to the credential if set
+func newLocalRegistry(tags map[string]string, token string, cred *Credential) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			username, password, ok := r.BasicAuth()
+			if cred != nil && (!ok || username != cred.Username || password != cred.Password) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"token":"` + token + `"}`))
+			return
+		}
+
+		if token != "" && r.Header.Get("Authorization") != "Bearer "+token {
+			w.Header().Set("WWW-Authenticate",
+				`Bearer realm="`+server.URL+`/token",service="local",scope="repository:logan/app:pull"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		prefix := "/v2/logan/app/manifests/"
+		if r.Method != http.MethodHead || !strings.HasPrefix(r.URL.Path, prefix) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		digest, found := tags[strings.TrimPrefix(r.URL.Path, prefix)]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set(DigestHeader, digest)
+		w.WriteHeader(http.StatusOK)
+	}))
+	return server
+}
+
+var _ = Describe("Registry", func() {
+	Context("With image name", func() {
+		It("split the registry host and repository", func() {
+			data := []struct {
+				Image string
+				Host  string
+				Repo  string
+			}{
+				{"nginx", "registry-1.docker.io", "library/nginx"},
+				{"logan/app", "registry-1.docker.io", "logan/app"},
+				{"docker.io/logan/app", "registry-1.docker.io", "logan/app"},
+				{"registry.logan.local/logan/app", "registry.logan.local", "logan/app"},
+				{"localhost:5000/app", "localhost:5000", "app"},
+			}
+			for _, d := range data {
+				host, repo := SplitImage(d.Image)
+				Expect(host).Should(Equal(d.Host))
+				Expect(repo).Should(Equal(d.Repo))
+			}
+		})
+
+		It("validate the digest", func() {
+			Expect(ValidDigest(digestV1)).Should(BeTrue())
+			Expect(ValidDigest("sha256:abc")).Should(BeFalse())
+			Expect(ValidDigest("1.0.0")).Should(BeFalse())
+		})
+	})
+
+	Context("With local registry", func() {
+		It("resolve the tag to the digest", func() {
+			server := newLocalRegistry(map[string]string{"1.0": digestV1, "2.0": digestV2}, "", nil)
+			defer server.Close()
+			image := strings.TrimPrefix(server.URL, "http://") + "/logan/app"
+
+			client := NewClient()
+			digest, err := client.Resolve(image, "1.0", nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(digest).Should(Equal(digestV1))
+
+			digest, err = client.Resolve(image, "2.0", nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(digest).Should(Equal(digestV2))
+		})
+
+		It("fail to resolve the missing tag", func() {
+			server := newLocalRegistry(map[string]string{"1.0": digestV1}, "", nil)
+			defer server.Close()
+			image := strings.TrimPrefix(server.URL, "http://") + "/logan/app"
+
+			_, err := NewClient().Resolve(image, "3.0", nil)
+			Expect(err).Should(HaveOccurred())
+
+			_, err = NewClient().Resolve(image, "", nil)
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("resolve with the anonymous token", func() {
+			server := newLocalRegistry(map[string]string{"1.0": digestV1}, "anonymous", nil)
+			defer server.Close()
+			image := strings.TrimPrefix(server.URL, "http://") + "/logan/app"
+
+			digest, err := NewClient().Resolve(image, "1.0", nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(digest).Should(Equal(digestV1))
+		})
+
+		It("resolve with the credential of the image pull secret", func() {
+			server := newLocalRegistry(map[string]string{"1.0": digestV1}, "private",
+				&Credential{Username: "logan", Password: "secret"})
+			defer server.Close()
+			host := strings.TrimPrefix(server.URL, "http://")
+			image := host + "/logan/app"
+
+			_, err := NewClient().Resolve(image, "1.0", nil)
+			Expect(err).Should(HaveOccurred())
+
+			// base64 of logan:secret
+			keychain, err := ParseDockerConfig([]byte(`{"auths":{"http://`+host+`":{"auth":"bG9nYW46c2VjcmV0"}}}`), true)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(keychain).Should(HaveKeyWithValue(host, Credential{Username: "logan", Password: "secret"}))
+
+			digest, err := NewClient().Resolve(image, "1.0", keychain)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(digest).Should(Equal(digestV1))
+		})
+	})
+
+	Context("With image pull secret", func() {
+		It("parse the docker config", func() {
+			keychain, err := ParseDockerConfig([]byte(
+				`{"https://index.docker.io/v1/":{"username":"logan","password":"secret","email":"logan@local"}}`), false)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(keychain).Should(Equal(Keychain{
+				"registry-1.docker.io": {Username: "logan", Password: "secret"},
+			}))
+
+			_, err = ParseDockerConfig([]byte(`{"auths":{"registry.logan.local":{"auth":"invalid"}}}`), true)
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,147 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	v1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	"reflect"
+	"sort"
+)
+
+// FieldChanges return the field-level changes from previous to current sorted by the paths, which are compared
+// after marshaled to JSON. The paths are joined by ".", the lists of objects with unique names are indexed by the
+// names as `env[JAVA_OPTS]`, and other lists by the indexes as `args[0]`. The values are the JSON of the fields,
+// except that the strings are not quoted, empty if the field is not set.
+func FieldChanges(previous, current interface{}) ([]v1.BootRevisionChange, error) {
+	previousValue, err := jsonValue(previous)
+	if err != nil {
+		return nil, err
+	}
+	currentValue, err := jsonValue(current)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]v1.BootRevisionChange, 0)
+	collectFieldChanges(previousValue, currentValue, "", &changes)
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+func jsonValue(obj interface{}) (interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	err = json.Unmarshal(data, &value)
+	return value, err
+}
+
+func collectFieldChanges(previous, current interface{}, path string, changes *[]v1.BootRevisionChange) {
+	if reflect.DeepEqual(previous, current) {
+		return
+	}
+
+	previousMap, previousIsMap := previous.(map[string]interface{})
+	currentMap, currentIsMap := current.(map[string]interface{})
+	if previousIsMap && currentIsMap {
+		for key, value := range previousMap {
+			collectFieldChanges(value, currentMap[key], joinPath(path, key), changes)
+		}
+		for key, value := range currentMap {
+			if _, found := previousMap[key]; !found {
+				collectFieldChanges(nil, value, joinPath(path, key), changes)
+			}
+		}
+		return
+	}
+
+	// An unset list is compared as an empty one, so the elements are added or removed one by one
+	previousList, previousIsList := previous.([]interface{})
+	currentList, currentIsList := current.([]interface{})
+	if previous == nil {
+		previousIsList = currentIsList
+	}
+	if current == nil {
+		currentIsList = previousIsList
+	}
+	if previousIsList && currentIsList {
+		previousNamed, previousOk := namedElements(previousList)
+		currentNamed, currentOk := namedElements(currentList)
+		if previousOk && currentOk {
+			for name, value := range previousNamed {
+				collectFieldChanges(value, currentNamed[name], fmt.Sprintf("%s[%s]", path, name), changes)
+			}
+			for name, value := range currentNamed {
+				if _, found := previousNamed[name]; !found {
+					collectFieldChanges(nil, value, fmt.Sprintf("%s[%s]", path, name), changes)
+				}
+			}
+			return
+		}
+
+		for i := 0; i < len(previousList) || i < len(currentList); i++ {
+			var previousElem, currentElem interface{}
+			if i < len(previousList) {
+				previousElem = previousList[i]
+			}
+			if i < len(currentList) {
+				currentElem = currentList[i]
+			}
+			collectFieldChanges(previousElem, currentElem, fmt.Sprintf("%s[%d]", path, i), changes)
+		}
+		return
+	}
+
+	*changes = append(*changes, v1.BootRevisionChange{
+		Path: path,
+		Old:  fieldValue(previous),
+		New:  fieldValue(current),
+	})
+}
+
+// namedElements return the list's elements by the names, false if any element is not an object with a unique name
+func namedElements(list []interface{}) (map[string]interface{}, bool) {
+	named := make(map[string]interface{}, len(list))
+	for _, elem := range list {
+		fields, ok := elem.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		name, ok := fields["name"].(string)
+		if !ok || name == "" {
+			return nil, false
+		}
+		if _, found := named[name]; found {
+			return nil, false
+		}
+		named[name] = elem
+	}
+	return named, true
+}
+
+func joinPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+func fieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,53 @@
+package util
+
+import (
+	v1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Changes", func() {
+	Context("With field changes", func() {
+		It("test changed, added and removed fields", func() {
+			previous := v1.BootSpec{Image: "app", Version: "1.0", Port: 8080, Args: []string{"-a", "-b"}}
+			current := v1.BootSpec{Image: "app", Version: "1.1", Port: 8090, Args: []string{"-a"},
+				Command: []string{"run"}}
+
+			changes, err := FieldChanges(previous, current)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(changes).Should(Equal([]v1.BootRevisionChange{
+				{Path: "args[1]", Old: "-b"},
+				{Path: "command[0]", New: "run"},
+				{Path: "port", Old: "8080", New: "8090"},
+				{Path: "version", Old: "1.0", New: "1.1"},
+			}))
+		})
+
+		It("test named list elements", func() {
+			previous := v1.BootSpec{Env: []corev1.EnvVar{
+				{Name: "A", Value: "1"},
+				{Name: "B", Value: "2"},
+			}}
+			current := v1.BootSpec{Env: []corev1.EnvVar{
+				{Name: "B", Value: "3"},
+				{Name: "C", Value: "4"},
+			}}
+
+			changes, err := FieldChanges(previous, current)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(changes).Should(Equal([]v1.BootRevisionChange{
+				{Path: "env[A]", Old: `{"name":"A","value":"1"}`},
+				{Path: "env[B].value", Old: "2", New: "3"},
+				{Path: "env[C]", New: `{"name":"C","value":"4"}`},
+			}))
+		})
+
+		It("test no changes", func() {
+			spec := v1.BootSpec{Image: "app", Version: "1.0"}
+			changes, err := FieldChanges(spec, *spec.DeepCopy())
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(changes).Should(BeEmpty())
+		})
+	})
+})
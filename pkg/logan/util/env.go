@@ -17,6 +17,9 @@ type ValidateNameFunc apimachineryvalidation.ValidateNameFunc
 // ValidateSecretName is a ValidateNameFunc for secret name
 var ValidateSecretName = NameIsDNSSubdomain
 
+// ValidateConfigMapName is a ValidateNameFunc for configMap name
+var ValidateConfigMapName = NameIsDNSSubdomain
+
 // NameIsDNSSubdomain is a ValidateNameFunc for names that must be a DNS subdomain.
 func NameIsDNSSubdomain(name string, prefix bool) []string {
 	return apimachineryvalidation.NameIsDNSSubdomain(name, prefix)
@@ -100,3 +103,40 @@ func validateSecretKeySelector(s *corev1.SecretKeySelector, fldPath *field.Path)
 
 	return allErrs
 }
+
+// ValidateEnvFrom validates envFrom sources
+// copy from https://github.com/kubernetes/kubernetes/blob/release-1.11/pkg/apis/core/validation/validation.go#L2030
+func ValidateEnvFrom(vars []corev1.EnvFromSource, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, ev := range vars {
+		idxPath := fldPath.Index(i)
+		if len(ev.Prefix) > 0 {
+			for _, msg := range validation.IsEnvVarName(ev.Prefix) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("prefix"), ev.Prefix, msg))
+			}
+		}
+
+		numSources := 0
+		if ev.ConfigMapRef != nil {
+			numSources++
+			nameFn := ValidateNameFunc(ValidateConfigMapName)
+			for _, msg := range nameFn(ev.ConfigMapRef.Name, false) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("configMapRef").Child("name"), ev.ConfigMapRef.Name, msg))
+			}
+		}
+		if ev.SecretRef != nil {
+			numSources++
+			nameFn := ValidateNameFunc(ValidateSecretName)
+			for _, msg := range nameFn(ev.SecretRef.Name, false) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("secretRef").Child("name"), ev.SecretRef.Name, msg))
+			}
+		}
+
+		if numSources == 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath, "", "must specify one of: `configMapRef` or `secretRef`"))
+		} else if numSources > 1 {
+			allErrs = append(allErrs, field.Invalid(fldPath, "", "may not have more than one field specified at a time"))
+		}
+	}
+	return allErrs
+}
@@ -19,6 +19,10 @@ const (
 	// PrometheusScrapeAnnotationValue is the Boot's created service's prometheus scrape annotation value
 	PrometheusScrapeAnnotationValue = "true"
 
+	// AppProtocolsAnnotationKey is the Boot's created service's annotation key for the application protocols of
+	// the ports, in the format of `name=appProtocol` split by ,
+	AppProtocolsAnnotationKey = "app.logancloud.com/app-protocols"
+
 	// EnvAnnotationKey is the annotation key for storing when changed env
 	EnvAnnotationKey = "app.logancloud.com/env"
 	// EnvAnnotationValue is default value for for env
@@ -33,6 +37,19 @@ const (
 	BootImagesAnnotationKey = "app.logancloud.com/boot-images"
 	// BootRestartedAtAnnotationKey is the annotation key for recording restarted time
 	BootRestartedAtAnnotationKey = "app.logancloud.com/restartedAt"
+	// BootConfigHashAnnotationKey is the annotation key for recording the hash of the referenced Secrets and ConfigMaps
+	BootConfigHashAnnotationKey = "app.logancloud.com/config-hash"
+	// BootMetadataLabelsAnnotationKey is the annotation key for recording the label keys applied from the boot's metadata
+	BootMetadataLabelsAnnotationKey = "app.logancloud.com/metadata-labels"
+	// BootMetadataAnnotationsAnnotationKey is the annotation key for recording the annotation keys applied from
+	// the boot's metadata
+	BootMetadataAnnotationsAnnotationKey = "app.logancloud.com/metadata-annotations"
+	// BootOverridesHashAnnotationKey is the annotation key for recording the hash of the overrides applied to the object
+	BootOverridesHashAnnotationKey = "app.logancloud.com/overrides-hash"
+	// OperatorAnnotationKeyPrefix is the prefix of the annotation and label keys reserved for the operator
+	OperatorAnnotationKeyPrefix = "app.logancloud.com/"
+	// PrometheusAnnotationKeyPrefix is the prefix of the prometheus annotation keys
+	PrometheusAnnotationKeyPrefix = "prometheus.io/"
 
 	// WorkloadAnnotationKey is the annotation key for storing boot's current workload type
 	WorkloadAnnotationKey = "app.logancloud.com/workload"
@@ -58,14 +75,54 @@ const (
 	BootRevisionHashAnnotationKey = "app.logancloud.com/hash"
 	// BootRevisionPhaseAnnotationKey is the annotation key for boot revision's phase
 	BootRevisionPhaseAnnotationKey = "app.logancloud.com/phase"
-	// BootRevisionDiffAnnotationKey is the annotation key for boot revision's the differences from the previous version
-	BootRevisionDiffAnnotationKey = "app.logancloud.com/diff"
 	// BootRevisionRetryAnnotationKey is the annotation key for boot revision's fail retry times
 	BootRevisionRetryAnnotationKey = "app.logancloud.com/retry"
+	// BootRevisionFailureAnnotationKey is the annotation key for the reason why boot revision is Failed
+	BootRevisionFailureAnnotationKey = "app.logancloud.com/failure"
+
+	// CanaryStepAnnotationKey is the annotation key for storing the current step of the canary on its workload
+	CanaryStepAnnotationKey = "app.logancloud.com/canary-step"
+	// CanaryStepTimeAnnotationKey is the annotation key for storing the start time of the canary's current step
+	CanaryStepTimeAnnotationKey = "app.logancloud.com/canary-step-time"
+	// CanaryPromoteAnnotationKey is the annotation key for promoting the canary to the next step,
+	// every new value of the Boot's annotation promotes one step.
+	CanaryPromoteAnnotationKey = "app.logancloud.com/canary-promote"
+	// CanaryAbortAnnotationKey is the annotation key for aborting the canary, the stable version is kept
+	// while the Boot's annotation is "true".
+	CanaryAbortAnnotationKey = "app.logancloud.com/canary-abort"
+
+	// BlueGreenSwitchedAtAnnotationKey is the annotation key for recording when the app Service is switched to
+	// the active color
+	BlueGreenSwitchedAtAnnotationKey = "app.logancloud.com/bluegreen-switched-at"
+	// BootRevisionRollbackAnnotationKey is the annotation key for boot revision's the revision rolled back from
+	BootRevisionRollbackAnnotationKey = "app.logancloud.com/rollback-from"
+	// BootRollbackToAnnotationKey is the annotation key for rolling back the Boot to the revision of the ID,
+	// it is removed once the Boot's spec is restored from the revision.
+	BootRollbackToAnnotationKey = "app.logancloud.com/rollback-to"
+	// BootChangeCauseAnnotationKey is the annotation key for the cause of the Boot's change, recorded in the
+	// revision created by the update which sets it, such as "rollback from 3"
+	BootChangeCauseAnnotationKey = "app.logancloud.com/change-cause"
+	// BootChangedByAnnotationKey is the annotation key for the username who changed the Boot, recorded in the
+	// revision created by the change
+	BootChangedByAnnotationKey = "app.logancloud.com/changed-by"
+	// BootChangedByGroupsAnnotationKey is the annotation key for the groups of the user who changed the Boot,
+	// joined by ","
+	BootChangedByGroupsAnnotationKey = "app.logancloud.com/changed-by-groups"
+	// BootChangeOperationAnnotationKey is the annotation key for the operation which changed the Boot,
+	// CREATE or UPDATE
+	BootChangeOperationAnnotationKey = "app.logancloud.com/change-operation"
 
 	// BootSecretAnnotaionKeyPrefix is the annotation key prefix for flags whether permission is granted for Secret
 	BootSecretAnnotaionKeyPrefix = "app.logancloud.com/secret-"
 
+	// BootServiceAccountAnnotaionKeyPrefix is the annotation key prefix for flags whether permission is granted for
+	// the existing ServiceAccount
+	BootServiceAccountAnnotaionKeyPrefix = "app.logancloud.com/serviceaccount-"
+
 	// BootPriorityAnnotaionKeyPrefix is the annotation key prefix for flags whether permission is granted for PriorityClass
 	BootPriorityAnnotaionKeyPrefix = "app.logancloud.com/priority-"
+
+	// BootPlacementAnnotaionKeyPrefix is the annotation key prefix of the Namespace for flags whether permission
+	// is granted for the placement class
+	BootPlacementAnnotaionKeyPrefix = "app.logancloud.com/placement-"
 )
@@ -24,6 +24,26 @@ const (
 	// FailedGetStatefulSet is the failed event reason for got statefulSet
 	FailedGetStatefulSet = "FailedGetStatefulSet"
 
+	// StartedCanary is the event reason for started canary
+	StartedCanary = "StartedCanary"
+	// PromotedCanary is the event reason for promoted canary
+	PromotedCanary = "PromotedCanary"
+	// AbortedCanary is the event reason for aborted canary
+	AbortedCanary = "AbortedCanary"
+	// DeletedCanary is the event reason for deleted canary workload
+	DeletedCanary = "DeletedCanary"
+	// FailedCanary is the failed event reason for canary
+	FailedCanary = "FailedCanary"
+
+	// SwitchedBlueGreen is the event reason for switched the Services to the new color
+	SwitchedBlueGreen = "SwitchedBlueGreen"
+	// RolledBackBlueGreen is the event reason for switched the Services back to the previous color
+	RolledBackBlueGreen = "RolledBackBlueGreen"
+	// ScaledDownBlueGreen is the event reason for scaled down the idle color
+	ScaledDownBlueGreen = "ScaledDownBlueGreen"
+	// FailedBlueGreen is the failed event reason for blue/green
+	FailedBlueGreen = "FailedBlueGreen"
+
 	// CreatedService is the event reason for created service
 	CreatedService = "CreatedService"
 	// FailedCreateService is the failed event reason for created service
@@ -39,14 +59,103 @@ const (
 	// FailedGetService is the failed event reason for got service
 	FailedGetService = "FailedGetService"
 
+	// CreatedIngress is the event reason for created ingress or route
+	CreatedIngress = "CreatedIngress"
+	// FailedCreateIngress is the failed event reason for created ingress or route
+	FailedCreateIngress = "FailedCreateIngress"
+	// UpdatedIngress is the event reason for updated ingress or route
+	UpdatedIngress = "UpdatedIngress"
+	// FailedUpdateIngress is the failed event reason for updated ingress or route
+	FailedUpdateIngress = "FailedUpdateIngress"
+	// DeletedIngress is the event reason for deleted ingress or route
+	DeletedIngress = "DeletedIngress"
+	// FailedDeleteIngress is the failed event reason for deleted ingress or route
+	FailedDeleteIngress = "FailedDeleteIngress"
+	// FailedGetIngress is the failed event reason for got ingress or route
+	FailedGetIngress = "FailedGetIngress"
+
+	// CreatedPodDisruptionBudget is the event reason for created PodDisruptionBudget
+	CreatedPodDisruptionBudget = "CreatedPodDisruptionBudget"
+	// FailedCreatePodDisruptionBudget is the failed event reason for created PodDisruptionBudget
+	FailedCreatePodDisruptionBudget = "FailedCreatePodDisruptionBudget"
+	// UpdatedPodDisruptionBudget is the event reason for updated PodDisruptionBudget
+	UpdatedPodDisruptionBudget = "UpdatedPodDisruptionBudget"
+	// FailedUpdatePodDisruptionBudget is the failed event reason for updated PodDisruptionBudget
+	FailedUpdatePodDisruptionBudget = "FailedUpdatePodDisruptionBudget"
+	// DeletedPodDisruptionBudget is the event reason for deleted PodDisruptionBudget
+	DeletedPodDisruptionBudget = "DeletedPodDisruptionBudget"
+	// FailedDeletePodDisruptionBudget is the failed event reason for deleted PodDisruptionBudget
+	FailedDeletePodDisruptionBudget = "FailedDeletePodDisruptionBudget"
+	// FailedGetPodDisruptionBudget is the failed event reason for got PodDisruptionBudget
+	FailedGetPodDisruptionBudget = "FailedGetPodDisruptionBudget"
+
+	// CreatedServiceAccount is the event reason for created ServiceAccount
+	CreatedServiceAccount = "CreatedServiceAccount"
+	// FailedCreateServiceAccount is the failed event reason for created ServiceAccount
+	FailedCreateServiceAccount = "FailedCreateServiceAccount"
+	// DeletedServiceAccount is the event reason for deleted ServiceAccount
+	DeletedServiceAccount = "DeletedServiceAccount"
+	// FailedDeleteServiceAccount is the failed event reason for deleted ServiceAccount
+	FailedDeleteServiceAccount = "FailedDeleteServiceAccount"
+	// FailedGetServiceAccount is the failed event reason for got ServiceAccount
+	FailedGetServiceAccount = "FailedGetServiceAccount"
+
+	// CreatedRole is the event reason for created Role
+	CreatedRole = "CreatedRole"
+	// FailedCreateRole is the failed event reason for created Role
+	FailedCreateRole = "FailedCreateRole"
+	// UpdatedRole is the event reason for updated Role
+	UpdatedRole = "UpdatedRole"
+	// FailedUpdateRole is the failed event reason for updated Role
+	FailedUpdateRole = "FailedUpdateRole"
+	// DeletedRole is the event reason for deleted Role
+	DeletedRole = "DeletedRole"
+	// FailedDeleteRole is the failed event reason for deleted Role
+	FailedDeleteRole = "FailedDeleteRole"
+	// FailedGetRole is the failed event reason for got Role
+	FailedGetRole = "FailedGetRole"
+
+	// CreatedRoleBinding is the event reason for created RoleBinding
+	CreatedRoleBinding = "CreatedRoleBinding"
+	// FailedCreateRoleBinding is the failed event reason for created RoleBinding
+	FailedCreateRoleBinding = "FailedCreateRoleBinding"
+	// UpdatedRoleBinding is the event reason for updated RoleBinding
+	UpdatedRoleBinding = "UpdatedRoleBinding"
+	// FailedUpdateRoleBinding is the failed event reason for updated RoleBinding
+	FailedUpdateRoleBinding = "FailedUpdateRoleBinding"
+	// DeletedRoleBinding is the event reason for deleted RoleBinding
+	DeletedRoleBinding = "DeletedRoleBinding"
+	// FailedDeleteRoleBinding is the failed event reason for deleted RoleBinding
+	FailedDeleteRoleBinding = "FailedDeleteRoleBinding"
+	// FailedGetRoleBinding is the failed event reason for got RoleBinding
+	FailedGetRoleBinding = "FailedGetRoleBinding"
+
 	// UpdatedBootDefaulters is the event reason for updated boot defaulters
 	UpdatedBootDefaulters = "UpdatedBootDefaulters"
 	// FailedUpdateBootDefaulters is the failed event reason for updated boot defaulters
 	FailedUpdateBootDefaulters = "FailedUpdateBootDefaulters"
+	// ResolvedImageDigest is the event reason for resolved boot's image digest
+	ResolvedImageDigest = "ResolvedImageDigest"
+	// FailedResolveImageDigest is the failed event reason for resolved boot's image digest
+	FailedResolveImageDigest = "FailedResolveImageDigest"
+	// RollbackBoot is the event reason for restoring boot's spec from the revision of the rollback annotation
+	RollbackBoot = "RollbackBoot"
+	// FailedRollbackBoot is the failed event reason for restored boot's spec from the revision
+	FailedRollbackBoot = "FailedRollbackBoot"
+	// FailedRevision is the failed event reason for boot's latest revision failed to become Active
+	FailedRevision = "FailedRevision"
+	// AutoRollbackBoot is the event reason for rolling back boot to the last Complete revision automatically
+	AutoRollbackBoot = "AutoRollbackBoot"
+	// UpdatedBootRollback is the event reason for updated boot with the revision rolled back to
+	UpdatedBootRollback = "UpdatedBootRollback"
+	// FailedUpdateBootRollback is the failed event reason for updated boot with the revision rolled back to
+	FailedUpdateBootRollback = "FailedUpdateBootRollback"
 	// UpdatedBootMeta is the event reason for updated boot meta
 	UpdatedBootMeta = "UpdatedBootMeta"
 	// FailedUpdateBootMeta is the failed event reason for updated boot meta
 	FailedUpdateBootMeta = "FailedUpdateBootMeta"
+	// FailedOverride is the failed event reason for applied boot's overrides
+	FailedOverride = "FailedOverride"
 
 	// UpdatedBootStatus is the event reason for updated boot status
 	UpdatedBootStatus = "UpdatedBootStatus"
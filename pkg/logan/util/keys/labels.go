@@ -7,6 +7,20 @@ const (
 	// BootTypeKey is the boot type's label selector key
 	BootTypeKey = "bootType"
 
+	// TrackKey is the label key of the canary and stable workloads' pods
+	TrackKey = "track"
+	// TrackCanary is the label value of the canary workload's pods
+	TrackCanary = "canary"
+	// TrackStable is the label value of the stable workload's pods
+	TrackStable = "stable"
+
+	// ColorKey is the label key of the blue/green workload's pods, selected by the Services
+	ColorKey = "color"
+	// ColorBlue is the label value of the blue workload's pods
+	ColorBlue = "blue"
+	// ColorGreen is the label value of the green workload's pods
+	ColorGreen = "green"
+
 	// SharedKey is the boot's pvc's shared type label selector key
 	SharedKey = "shared"
 )
@@ -0,0 +1,95 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	patchDirective               = "$patch"
+	retainKeysDirective          = "$retainKeys"
+	setElementOrderDirective     = "$setElementOrder/"
+	deleteFromPrimitiveDirective = "$deleteFromPrimitiveList/"
+)
+
+// StrategicMergePatch apply the strategic merge patch to obj, which must be a pointer to a typed api object.
+// The unknown fields of the patched object are rejected.
+func StrategicMergePatch(obj interface{}, patch []byte) error {
+	original, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	patched, err := strategicpatch.StrategicMergePatch(original, patch, obj)
+	if err != nil {
+		return err
+	}
+
+	value := reflect.ValueOf(obj).Elem()
+	value.Set(reflect.Zero(value.Type()))
+
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(obj)
+}
+
+// PatchPaths return the sorted paths of the fields set by the strategic merge patch, joined by ".".
+// Lists are not descended, the list's path is returned. The directives `$patch` and `$retainKeys` return the
+// path of the map they are in, `$setElementOrder` and `$deleteFromPrimitiveList` return the path of the list.
+func PatchPaths(patch []byte) ([]string, error) {
+	fields := make(map[string]interface{})
+	err := json.Unmarshal(patch, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]bool)
+	collectPatchPaths(fields, "", paths)
+
+	ret := make([]string, 0, len(paths))
+	for path := range paths {
+		ret = append(ret, path)
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+func collectPatchPaths(fields map[string]interface{}, parent string, paths map[string]bool) {
+	for key, value := range fields {
+		switch {
+		case key == patchDirective || key == retainKeysDirective:
+			paths[parent] = true
+			continue
+		case strings.HasPrefix(key, setElementOrderDirective):
+			key = strings.TrimPrefix(key, setElementOrderDirective)
+		case strings.HasPrefix(key, deleteFromPrimitiveDirective):
+			key = strings.TrimPrefix(key, deleteFromPrimitiveDirective)
+		}
+
+		path := key
+		if parent != "" {
+			path = parent + "." + key
+		}
+
+		child, ok := value.(map[string]interface{})
+		if !ok || len(child) == 0 {
+			paths[path] = true
+			continue
+		}
+		collectPatchPaths(child, path, paths)
+	}
+}
+
+// PathAllowed return whether the path is one of the allowed paths or under one of them
+func PathAllowed(path string, allowed []string) bool {
+	for _, allowedPath := range allowed {
+		if path == allowedPath || strings.HasPrefix(path, allowedPath+".") {
+			return true
+		}
+	}
+	return false
+}
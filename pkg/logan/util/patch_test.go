@@ -0,0 +1,84 @@
+package util
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Patch", func() {
+	Context("With patch paths", func() {
+		It("test paths and directives", func() {
+			patch := `{
+  "spec": {
+    "revisionHistoryLimit": 3,
+    "template": {
+      "spec": {
+        "hostAliases": [{"ip": "10.0.0.1", "hostnames": ["db.local"]}],
+        "dnsConfig": {"options": [{"name": "ndots", "value": "2"}]},
+        "$setElementOrder/tolerations": [],
+        "securityContext": {"$patch": "replace"}
+      }
+    }
+  }
+}`
+			paths, err := PatchPaths([]byte(patch))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(paths).Should(Equal([]string{
+				"spec.revisionHistoryLimit",
+				"spec.template.spec.dnsConfig.options",
+				"spec.template.spec.hostAliases",
+				"spec.template.spec.securityContext",
+				"spec.template.spec.tolerations",
+			}))
+		})
+
+		It("test allowed paths", func() {
+			allowed := []string{"spec.template.spec.hostAliases", "spec.template.spec.dnsConfig"}
+			Expect(PathAllowed("spec.template.spec.hostAliases", allowed)).Should(BeTrue())
+			Expect(PathAllowed("spec.template.spec.dnsConfig.options", allowed)).Should(BeTrue())
+			Expect(PathAllowed("spec.template.spec.dnsConfigs", allowed)).Should(BeFalse())
+			Expect(PathAllowed("spec.template.spec", allowed)).Should(BeFalse())
+		})
+	})
+
+	Context("With strategic merge patch", func() {
+		It("test patch deployment", func() {
+			dep := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "app"},
+				Spec: appsv1.DeploymentSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "app", Image: "app:1.0"}},
+						},
+					},
+				},
+			}
+			patch := `{"spec":{"template":{"spec":{"shareProcessNamespace":true,` +
+				`"containers":[{"name":"app","stdin":true}]}}}}`
+
+			err := StrategicMergePatch(dep, []byte(patch))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(dep.Name).Should(Equal("app"))
+			Expect(*dep.Spec.Template.Spec.ShareProcessNamespace).Should(BeTrue())
+			Expect(dep.Spec.Template.Spec.Containers).Should(HaveLen(1))
+			Expect(dep.Spec.Template.Spec.Containers[0].Image).Should(Equal("app:1.0"))
+			Expect(dep.Spec.Template.Spec.Containers[0].Stdin).Should(BeTrue())
+		})
+
+		It("test reject unknown and mistyped fields", func() {
+			err := StrategicMergePatch(&corev1.Service{}, []byte(`{"spec":{"externalTrafficPolicys":"Local"}}`))
+			Expect(err).Should(HaveOccurred())
+
+			err = StrategicMergePatch(&corev1.Service{}, []byte(`{"spec":{"externalTrafficPolicy":1}}`))
+			Expect(err).Should(HaveOccurred())
+
+			svc := &corev1.Service{}
+			err = StrategicMergePatch(svc, []byte(`{"spec":{"externalTrafficPolicy":"Local"}}`))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(svc.Spec.ExternalTrafficPolicy).Should(Equal(corev1.ServiceExternalTrafficPolicyTypeLocal))
+		})
+	})
+})
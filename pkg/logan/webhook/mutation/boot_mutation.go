@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
 	"github.com/logancloud/logan-app-operator/pkg/controller/javaboot"
 	"github.com/logancloud/logan-app-operator/pkg/controller/nodejsboot"
 	"github.com/logancloud/logan-app-operator/pkg/controller/phpboot"
@@ -11,6 +12,7 @@ import (
 	"github.com/logancloud/logan-app-operator/pkg/controller/webboot"
 	"github.com/logancloud/logan-app-operator/pkg/logan"
 	"github.com/logancloud/logan-app-operator/pkg/logan/operator"
+	"github.com/logancloud/logan-app-operator/pkg/logan/registry"
 	"github.com/logancloud/logan-app-operator/pkg/logan/util"
 	"github.com/logancloud/logan-app-operator/pkg/logan/util/keys"
 	"github.com/logancloud/logan-app-operator/pkg/logan/webhook"
@@ -23,6 +25,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"strings"
 )
 
 // Now BootMutator only add an annotation to the Boot.
@@ -33,6 +36,10 @@ type BootMutator struct {
 	decoder  *admission.Decoder
 	Schema   *runtime.Scheme
 	Recorder record.EventRecorder
+	// Resolver resolves the boot's version to the image digest in the digest mode, defaults to the registry client
+	Resolver registry.Resolver
+	// OperatorUsername is the username of the operator's ServiceAccount, whose changes are not recorded as the authors
+	OperatorUsername string
 }
 
 var logger = logf.Log.WithName("logan_webhook_mutation")
@@ -74,7 +81,12 @@ func (mHandler *BootMutator) mutateBoot(ctx context.Context, req admission.Reque
 		handler := javaboot.InitHandler(bootCopy, scheme, c, logger, recorder)
 
 		mutationDefault(handler, req, bootCopy.Name)
+		err = mHandler.mutationDigest(handler, req)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err), err
+		}
 		mutationBoot(&bootCopy.ObjectMeta, req)
+		mHandler.mutationAuthor(handler, bootCopy.DeepCopyBoot(), &bootCopy.ObjectMeta, req)
 
 		marshaledBoot, err := json.Marshal(bootCopy)
 		if err != nil {
@@ -91,7 +103,12 @@ func (mHandler *BootMutator) mutateBoot(ctx context.Context, req admission.Reque
 		handler := phpboot.InitHandler(bootCopy, scheme, c, logger, recorder)
 
 		mutationDefault(handler, req, bootCopy.Name)
+		err = mHandler.mutationDigest(handler, req)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err), err
+		}
 		mutationBoot(&bootCopy.ObjectMeta, req)
+		mHandler.mutationAuthor(handler, bootCopy.DeepCopyBoot(), &bootCopy.ObjectMeta, req)
 
 		marshaledBoot, err := json.Marshal(bootCopy)
 		if err != nil {
@@ -108,7 +125,12 @@ func (mHandler *BootMutator) mutateBoot(ctx context.Context, req admission.Reque
 		handler := pythonboot.InitHandler(bootCopy, scheme, c, logger, recorder)
 
 		mutationDefault(handler, req, bootCopy.Name)
+		err = mHandler.mutationDigest(handler, req)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err), err
+		}
 		mutationBoot(&bootCopy.ObjectMeta, req)
+		mHandler.mutationAuthor(handler, bootCopy.DeepCopyBoot(), &bootCopy.ObjectMeta, req)
 
 		marshaledBoot, err := json.Marshal(bootCopy)
 		if err != nil {
@@ -125,7 +147,12 @@ func (mHandler *BootMutator) mutateBoot(ctx context.Context, req admission.Reque
 		handler := nodejsboot.InitHandler(bootCopy, scheme, c, logger, recorder)
 
 		mutationDefault(handler, req, bootCopy.Name)
+		err = mHandler.mutationDigest(handler, req)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err), err
+		}
 		mutationBoot(&bootCopy.ObjectMeta, req)
+		mHandler.mutationAuthor(handler, bootCopy.DeepCopyBoot(), &bootCopy.ObjectMeta, req)
 
 		marshaledBoot, err := json.Marshal(bootCopy)
 		if err != nil {
@@ -142,7 +169,12 @@ func (mHandler *BootMutator) mutateBoot(ctx context.Context, req admission.Reque
 		handler := webboot.InitHandler(bootCopy, scheme, c, logger, recorder)
 
 		mutationDefault(handler, req, bootCopy.Name)
+		err = mHandler.mutationDigest(handler, req)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err), err
+		}
 		mutationBoot(&bootCopy.ObjectMeta, req)
+		mHandler.mutationAuthor(handler, bootCopy.DeepCopyBoot(), &bootCopy.ObjectMeta, req)
 
 		marshaledBoot, err := json.Marshal(bootCopy)
 		if err != nil {
@@ -158,8 +190,8 @@ func mutationDefault(handler *operator.BootHandler, req admission.Request, bootN
 	if logan.MutationDefaulter {
 		changed := handler.DefaultValue()
 
-		//Update the Boot's default Value
-		if changed {
+		//Update the Boot's default Value, no events for the dry-run request
+		if changed && !dryRun(req) {
 			logger.Info(fmt.Sprintf("Updating Boot with Defaulters: [%s/%s]",
 				req.AdmissionRequest.Namespace, req.AdmissionRequest.Name),
 				"operation", req.AdmissionRequest.Operation)
@@ -168,6 +200,35 @@ func mutationDefault(handler *operator.BootHandler, req admission.Request, bootN
 	}
 }
 
+// mutationDigest record the image digest of the boot's version. The webhook declares no side effects on dry run,
+// so the digest is not resolved for the dry-run request, only the stale one is cleared.
+func (mHandler *BootMutator) mutationDigest(handler *operator.BootHandler, req admission.Request) error {
+	oldBoot, err := webhook.DecodeOldBoot(req)
+	if err != nil {
+		logger.Error(err, "Decoding old boot error.")
+		return err
+	}
+
+	var oldSpec *appv1.BootSpec
+	if oldBoot != nil {
+		oldSpec = &oldBoot.Spec
+	}
+
+	resolver := mHandler.Resolver
+	if dryRun(req) {
+		resolver = nil
+	} else if resolver == nil {
+		resolver = registry.NewClient()
+	}
+	_, err = handler.ResolveImageDigest(resolver, oldSpec)
+	return err
+}
+
+// dryRun return whether the admission request is a dry run, which must not have side effects
+func dryRun(req admission.Request) bool {
+	return req.AdmissionRequest.DryRun != nil && *req.AdmissionRequest.DryRun
+}
+
 func mutationBoot(metaData *metav1.ObjectMeta, req admission.Request) {
 	if metaData == nil {
 		return
@@ -186,6 +247,45 @@ func mutationBoot(metaData *metav1.ObjectMeta, req admission.Request) {
 	}
 }
 
+// mutationAuthor record who changed the boot and the operation in the annotations, which are recorded in the new
+// revision. Only the changes of the boot's revision are recorded, and the operator's own changes are skipped unless
+// it rolls back the boot. Both boots are defaulted the same before they are compared.
+func (mHandler *BootMutator) mutationAuthor(handler *operator.BootHandler, boot *appv1.Boot,
+	metaData *metav1.ObjectMeta, req admission.Request) {
+	oldBoot, err := webhook.DecodeOldBoot(req)
+	if err != nil {
+		logger.Error(err, "Decoding old boot error.")
+		return
+	}
+
+	userInfo := req.AdmissionRequest.UserInfo
+	rollbackTo, rollback := boot.Annotations[keys.BootRollbackToAnnotationKey]
+	if rollback && oldBoot != nil {
+		rollback = oldBoot.Annotations[keys.BootRollbackToAnnotationKey] != rollbackTo
+	}
+
+	if !rollback {
+		if userInfo.Username == mHandler.OperatorUsername {
+			return
+		}
+		if oldBoot != nil {
+			oldBoot.BootType = boot.BootType
+			oldBoot.AppKey = boot.AppKey
+			oldHash := operator.InitBootRevision(handler.DefaultedBoot(oldBoot)).BootHash()
+			if oldHash == operator.InitBootRevision(handler.DefaultedBoot(boot)).BootHash() {
+				return
+			}
+		}
+	}
+
+	if metaData.Annotations == nil {
+		metaData.Annotations = make(map[string]string)
+	}
+	metaData.Annotations[keys.BootChangedByAnnotationKey] = userInfo.Username
+	metaData.Annotations[keys.BootChangedByGroupsAnnotationKey] = strings.Join(userInfo.Groups, ",")
+	metaData.Annotations[keys.BootChangeOperationAnnotationKey] = string(req.AdmissionRequest.Operation)
+}
+
 var _ inject.Client = &BootMutator{}
 
 // InjectClient will inject client into BootMutator
@@ -11,29 +11,30 @@ import (
 
 	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
 	v1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
-	"github.com/logancloud/logan-app-operator/pkg/controller/javaboot"
-	"github.com/logancloud/logan-app-operator/pkg/controller/nodejsboot"
-	"github.com/logancloud/logan-app-operator/pkg/controller/phpboot"
-	"github.com/logancloud/logan-app-operator/pkg/controller/pythonboot"
-	"github.com/logancloud/logan-app-operator/pkg/controller/webboot"
 	"github.com/logancloud/logan-app-operator/pkg/logan/config"
 	"github.com/logancloud/logan-app-operator/pkg/logan/operator"
 	"github.com/logancloud/logan-app-operator/pkg/logan/util"
 	"github.com/logancloud/logan-app-operator/pkg/logan/webhook"
 	admssionv1beta1 "k8s.io/api/admission/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscaling "k8s.io/api/autoscaling/v2beta1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	scheduling "k8s.io/api/scheduling/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	"net/http"
+	"path"
 	"reflect"
 	"regexp"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
-	"sort"
-	"strconv"
 	"strings"
 )
 
@@ -117,7 +118,6 @@ func (vHandler *BootValidator) Validate(req admission.Request) (string, bool, er
 	// Check Boot's envs when creating or updating.
 	// Check Boot's pvc when creating or updating.
 	// Check Boot's priority when creating or updating.
-	// Record a revision when creating or updating if validation Boot valid.
 	if operation == admssionv1beta1.Create || operation == admssionv1beta1.Update {
 		msg, valid := vHandler.CheckEnvKeys(boot, operation)
 
@@ -138,217 +138,113 @@ func (vHandler *BootValidator) Validate(req admission.Request) (string, bool, er
 			return msg, false, nil
 		}
 
-		msg, valid = vHandler.checkPriority(boot, operation)
+		msg, valid = vHandler.validateProbes(boot, operation)
 		if !valid {
 			logger.Info(msg)
 			return msg, false, nil
 		}
 
-		msg, valid = vHandler.CheckPvc(boot, operation)
+		msg, valid = vHandler.validateStrategy(boot, operation)
 		if !valid {
 			logger.Info(msg)
 			return msg, false, nil
 		}
 
-		flag, err := vHandler.recordRevision(boot, req)
-		if err != nil || flag == false {
-			return "create up revision error", flag, err
+		msg, valid = vHandler.validateRollout(boot, operation)
+		if !valid {
+			logger.Info(msg)
+			return msg, false, nil
 		}
-	}
 
-	//will never execute if admssionv1beta1.Delete not set on webhook
-	if operation == admssionv1beta1.Delete {
-		_, err := vHandler.deleteRevision(boot)
-		if err != nil {
-			return "remove all revision error", false, err
+		msg, valid = vHandler.validateDisruption(boot, operation)
+		if !valid {
+			logger.Info(msg)
+			return msg, false, nil
 		}
-	}
-
-	logger.Info("Validation Boot valid: ",
-		"name", boot.Name, "namespace", boot.Namespace, "operation", operation)
-
-	return "", true, nil
-}
 
-// recordRevision will make a new revision record on boot created or update
-func (vHandler *BootValidator) recordRevision(inputBoot *v1.Boot, req admission.Request) (bool, error) {
-	// if false,do not record a revision
-	//if !logan.MutationDefaulter {
-	//	return true, nil
-	//}
-	if operator.IsDeletedObject(inputBoot) {
-		logger.Info("Boot resource has been mark deleted. do not need record a revision", "boot", inputBoot)
-		return true, nil
-	}
+		msg, valid = vHandler.validateShutdown(boot, operation)
+		if !valid {
+			logger.Info(msg)
+			return msg, false, nil
+		}
 
-	// merge boot's default value from operator's configmap, before record a revision
-	spec, meta := vHandler.mergeBootDefaultValue(inputBoot, req)
-	if spec == nil || meta == nil {
-		return false, nil
-	}
-	boot := inputBoot.DeepCopy()
-	boot.Spec = *spec
-	boot.ObjectMeta = *meta
+		msg, valid = vHandler.validatePorts(boot, operation)
+		if !valid {
+			logger.Info(msg)
+			return msg, false, nil
+		}
 
-	// init a revision
-	revisionBoot := operator.InitBootRevision(boot)
-	hashcode := revisionBoot.BootHash()
-	logger.V(1).Info("RevisionBoot's BootHash", "BootHash", hashcode, "revision", revisionBoot)
-	revisionBoot.Annotations[keys.BootRevisionHashAnnotationKey] = hashcode
+		msg, valid = vHandler.validateScheduling(boot, operation)
+		if !valid {
+			logger.Info(msg)
+			return msg, false, nil
+		}
 
-	// get ListRevision
-	c := vHandler.client
-	bootLabels := operator.PodLabels(boot)
-	revisionList, err := c.ListRevision(boot.Namespace, bootLabels)
-	if err != nil {
-		return false, err
-	}
+		msg, valid = vHandler.checkPriority(boot, operation)
+		if !valid {
+			logger.Info(msg)
+			return msg, false, nil
+		}
 
-	// the first revision
-	if len(revisionList.Items) == 0 {
-		revisionBoot.Annotations[keys.BootRevisionIdAnnotationKey] = "1"
-		revisionBoot.Annotations[keys.BootRevisionPhaseAnnotationKey] = operator.RevisionPhaseRunning
-		revisionBoot.Annotations[keys.BootRevisionDiffAnnotationKey] = ""
-		revisionBoot.Annotations[keys.BootRevisionRetryAnnotationKey] = "0"
-		revisionBoot.Name = revisionBoot.Name + "-" + revisionBoot.Annotations[keys.BootRevisionIdAnnotationKey]
-		revisionBoot.Labels = bootLabels
+		msg, valid = vHandler.validatePlacement(boot, operation)
+		if !valid {
+			logger.Info(msg)
+			return msg, false, nil
+		}
 
-		logger.Info("Create a new revision", "revision", revisionBoot)
-		err = c.Create(context.TODO(), revisionBoot)
-		if err != nil {
-			logger.Error(err, "Can not create revision", "revision", revisionBoot)
-			return false, err
+		msg, valid = vHandler.validateServiceAccount(boot, operation)
+		if !valid {
+			logger.Info(msg)
+			return msg, false, nil
 		}
 
-		return true, nil
-	}
+		msg, valid = vHandler.validateSecurityContext(boot, operation)
+		if !valid {
+			logger.Info(msg)
+			return msg, false, nil
+		}
 
-	// Compared to the previous revision
-	latestRevision := revisionList.SelectLatestRevision()
-	latestHash := latestRevision.Annotations[keys.BootRevisionHashAnnotationKey]
-	logger.V(1).Info("The latest revisionBoot's BootHash", "BootHash", latestHash, "revision", latestRevision)
+		msg, valid = vHandler.checkImagePullSecrets(boot, operation)
+		if !valid {
+			logger.Info(msg)
+			return msg, false, nil
+		}
 
-	// something change
-	if latestHash != hashcode {
-		// Add a new revision to history
-		latestRevisionId := latestRevision.GetRevisionId()
-		newRevisionId := latestRevisionId + 1
-		revisionBoot.Annotations[keys.BootRevisionIdAnnotationKey] = strconv.Itoa(newRevisionId)
-		revisionBoot.Annotations[keys.BootRevisionPhaseAnnotationKey] = operator.RevisionPhaseRunning
-		revisionBoot.Annotations[keys.BootRevisionDiffAnnotationKey] = operator.RevisionDiff(*revisionBoot, *latestRevision)
-		revisionBoot.Annotations[keys.BootRevisionRetryAnnotationKey] = "0"
-		revisionBoot.Name = revisionBoot.Name + "-" + revisionBoot.Annotations[keys.BootRevisionIdAnnotationKey]
-		revisionBoot.Labels = bootLabels
+		msg, valid = vHandler.validateTLSSecret(boot, operation)
+		if !valid {
+			logger.Info(msg)
+			return msg, false, nil
+		}
 
-		logger.Info("Add a new revision to history", "revision", revisionBoot)
-		err = c.Create(context.TODO(), revisionBoot)
-		if err != nil {
-			logger.Error(err, "Can not create revision", "revision", revisionBoot)
-			return false, err
+		msg, valid = vHandler.validateMetadata(boot, operation)
+		if !valid {
+			logger.Info(msg)
+			return msg, false, nil
 		}
 
-		// Update the previous revision's phase
-		latestPhase := latestRevision.Annotations[keys.BootRevisionPhaseAnnotationKey]
-		newPhase := latestPhase
-		if latestPhase == operator.RevisionPhaseRunning {
-			newPhase = operator.RevisionPhaseCancel
-		} else if latestPhase == operator.RevisionPhaseActive {
-			newPhase = operator.RevisionPhaseComplete
+		msg, valid = vHandler.validateOverrides(boot, operation)
+		if !valid {
+			logger.Info(msg)
+			return msg, false, nil
 		}
-		latestRevision.Annotations[keys.BootRevisionPhaseAnnotationKey] = newPhase
-		logger.Info("Update the previous revision's phase", "revision", latestRevision, "from", latestPhase, "to", newPhase)
-		err = c.Update(context.TODO(), latestRevision)
-		if err != nil {
-			logger.Error(err, "Can not update the previous revision's phase", "revision", latestRevision)
-			return false, err
-		}
-
-		// keep max history revision
-		return vHandler.keepRevisionMaxSizeLimit(revisionList, logan.MaxHistory-1)
-	}
-
-	//maybe just scale or redeploy
-	logger.V(1).Info("No need to do revision with boot", "revision", revisionBoot)
-	return true, nil
-
-}
-
-// mergeBootDefaultValue will merge boot config with operator app config
-func (vHandler *BootValidator) mergeBootDefaultValue(boot *v1.Boot, req admission.Request) (*appv1.BootSpec, *metav1.ObjectMeta) {
-	appType := req.AdmissionRequest.Kind.Kind
-	if appType == webhook.ApiTypeJava {
-		javaBoot := boot.DeepCopyJava()
-		handler := javaboot.InitHandler(javaBoot, vHandler.Schema, vHandler.client, logger, vHandler.Recorder)
-		handler.DefaultValue()
-		return &javaBoot.Spec, &javaBoot.ObjectMeta
-	} else if appType == webhook.ApiTypePython {
-		pythonBoot := boot.DeepCopyPython()
-		handler := pythonboot.InitHandler(pythonBoot, vHandler.Schema, vHandler.client, logger, vHandler.Recorder)
-		handler.DefaultValue()
-		return &pythonBoot.Spec, &pythonBoot.ObjectMeta
-	} else if appType == webhook.ApiTypePhp {
-		phpBoot := boot.DeepCopyPhp()
-		handler := phpboot.InitHandler(phpBoot, vHandler.Schema, vHandler.client, logger, vHandler.Recorder)
-		handler.DefaultValue()
-		return &phpBoot.Spec, &phpBoot.ObjectMeta
-	} else if appType == webhook.ApiTypeNodeJS {
-		nodejsBoot := boot.DeepCopyNodeJS()
-		handler := nodejsboot.InitHandler(nodejsBoot, vHandler.Schema, vHandler.client, logger, vHandler.Recorder)
-		handler.DefaultValue()
-		return &nodejsBoot.Spec, &nodejsBoot.ObjectMeta
-	} else if appType == webhook.ApiTypeWeb {
-		webBoot := boot.DeepCopyWeb()
-		handler := webboot.InitHandler(webBoot, vHandler.Schema, vHandler.client, logger, vHandler.Recorder)
-		handler.DefaultValue()
-		return &webBoot.Spec, &webBoot.ObjectMeta
-	}
-
-	return nil, nil
-}
-
-// keepRevisionMaxSizeLimit will keep only the Max size revisions
-func (vHandler *BootValidator) keepRevisionMaxSizeLimit(lst *v1.BootRevisionList, size int) (bool, error) {
-	if len(lst.Items) > size {
-		items := lst.Items
-		sort.Slice(items, func(i, j int) bool {
-			aId := (&items[i]).GetRevisionId()
-			bId := (&items[j]).GetRevisionId()
-			return aId < bId
-		})
-
-		delSize := len(items) - size
-		delLst := items[:delSize]
 
-		c := vHandler.client
+		msg, valid = vHandler.CheckPvc(boot, operation)
+		if !valid {
+			logger.Info(msg)
+			return msg, false, nil
+		}
 
-		for _, revision := range delLst {
-			logger.V(1).Info("Delete history revision.", "revision", revision)
-			err := c.Delete(context.TODO(), revision.DeepCopyObject())
-			if err != nil {
-				logger.Error(err, "Delete history revision error.", "revision", revision)
-				return false, err
-			}
+		msg, valid = vHandler.validateVolumes(boot, operation)
+		if !valid {
+			logger.Info(msg)
+			return msg, false, nil
 		}
 	}
 
-	return true, nil
-}
+	logger.Info("Validation Boot valid: ",
+		"name", boot.Name, "namespace", boot.Namespace, "operation", operation)
 
-//deleteRevision will delete all revision if boot is delete
-func (vHandler *BootValidator) deleteRevision(boot *v1.Boot) (bool, error) {
-	c := vHandler.client
-	bootLabels := operator.PodLabels(boot)
-	revisionList, err := c.ListRevision(boot.Namespace, bootLabels)
-	if err != nil {
-		return false, err
-	}
-	for _, revision := range revisionList.Items {
-		err := c.Delete(context.TODO(), revision.DeepCopyObject())
-		if err != nil {
-			return false, err
-		}
-	}
-	return true, nil
+	return "", true, nil
 }
 
 // BootNameExist check if name is exist.
@@ -522,6 +418,161 @@ func (vHandler *BootValidator) validateWorkload(boot *appv1.Boot, operation adms
 	return "", true
 }
 
+// validateProbes will validate the boot's structured probes
+func (vHandler *BootValidator) validateProbes(boot *appv1.Boot, operation admssionv1beta1.Operation) (string, bool) {
+	probes := boot.Spec.Probes
+	if probes == nil {
+		return "", true
+	}
+
+	// The startup only delays the liveness probe, the fields other than the delays are not used
+	if startup := probes.Startup; startup != nil {
+		if startup.Type != "" || startup.Port != nil || startup.Path != "" || startup.Scheme != "" ||
+			len(startup.Command) > 0 || startup.Service != "" || startup.TimeoutSeconds != nil ||
+			startup.SuccessThreshold != nil {
+			return "The boot's startup only delays the liveness probe, " +
+				"only initialDelaySeconds, periodSeconds and failureThreshold can be set", false
+		}
+		if probes.Liveness == nil && (boot.Spec.Health == nil || *boot.Spec.Health == "") {
+			return "The boot's startup requires a liveness probe or the health path", false
+		}
+	}
+
+	probeMap := map[string]*appv1.BootProbe{
+		"liveness":  probes.Liveness,
+		"readiness": probes.Readiness,
+	}
+	for name, probe := range probeMap {
+		if probe == nil {
+			continue
+		}
+
+		switch probe.Type {
+		case "", appv1.ProbeHTTP:
+			if probe.Path != "" && !strings.HasPrefix(probe.Path, "/") {
+				return fmt.Sprintf("The boot's %s probe path %s must start with '/'", name, probe.Path), false
+			}
+		case appv1.ProbeExec:
+			if len(probe.Command) == 0 {
+				return fmt.Sprintf("The boot's %s probe command can not be empty with type %s",
+					name, probe.Type), false
+			}
+		case appv1.ProbeTCP, appv1.ProbeGRPC:
+		default:
+			return fmt.Sprintf("The boot's %s probe type %s is not supported", name, probe.Type), false
+		}
+
+		if name != "readiness" && probe.SuccessThreshold != nil && *probe.SuccessThreshold != 1 {
+			return fmt.Sprintf("The boot's %s probe successThreshold must be 1", name), false
+		}
+	}
+
+	return "", true
+}
+
+// validateStrategy will validate the boot's update strategy with the workload type
+func (vHandler *BootValidator) validateStrategy(boot *appv1.Boot, operation admssionv1beta1.Operation) (string, bool) {
+	strategy := boot.Spec.Strategy
+	if strategy == nil {
+		return "", true
+	}
+
+	if boot.Spec.Workload == appv1.StatefulSet {
+		if strategy.Type == appv1.RecreateStrategy {
+			return fmt.Sprintf("The boot %s's strategy %s is not supported by StatefulSet.",
+				boot.Name, strategy.Type), false
+		}
+		if strategy.MaxSurge != nil || strategy.MaxUnavailable != nil ||
+			strategy.ProgressDeadlineSeconds != nil || strategy.MinReadySeconds != 0 {
+			return fmt.Sprintf("The boot %s's strategy only support partition for StatefulSet.", boot.Name), false
+		}
+		return "", true
+	}
+
+	if strategy.Partition != nil {
+		return fmt.Sprintf("The boot %s's strategy partition is only supported by StatefulSet.", boot.Name), false
+	}
+
+	if strategy.Type == appv1.RecreateStrategy && (strategy.MaxSurge != nil || strategy.MaxUnavailable != nil) {
+		return fmt.Sprintf("The boot %s's maxSurge and maxUnavailable can not be set with strategy %s.",
+			boot.Name, strategy.Type), false
+	}
+
+	if strategy.MaxSurge != nil && strategy.MaxUnavailable != nil {
+		surge, _ := intstr.GetValueFromIntOrPercent(strategy.MaxSurge, 100, true)
+		unavailable, _ := intstr.GetValueFromIntOrPercent(strategy.MaxUnavailable, 100, false)
+		if surge == 0 && unavailable == 0 {
+			return fmt.Sprintf("The boot %s's maxSurge and maxUnavailable can not be both 0.", boot.Name), false
+		}
+	}
+
+	if strategy.ProgressDeadlineSeconds != nil && *strategy.ProgressDeadlineSeconds <= strategy.MinReadySeconds {
+		return fmt.Sprintf("The boot %s's progressDeadlineSeconds must be greater than minReadySeconds.",
+			boot.Name), false
+	}
+
+	return "", true
+}
+
+// validateRollout will validate the boot's canary steps and blue/green settings
+func (vHandler *BootValidator) validateRollout(boot *appv1.Boot, operation admssionv1beta1.Operation) (string, bool) {
+	rollout := boot.Spec.Rollout
+	if rollout == nil {
+		return "", true
+	}
+
+	if rollout.Mode == appv1.BlueGreenRollout {
+		if boot.Spec.Workload == appv1.StatefulSet {
+			return fmt.Sprintf("The boot %s's rollout mode %s is not supported by StatefulSet.",
+				boot.Name, rollout.Mode), false
+		}
+		if boot.Spec.Hpa != nil && boot.Spec.Hpa.Enable {
+			return fmt.Sprintf("The boot %s's rollout mode %s can not be used with hpa.",
+				boot.Name, rollout.Mode), false
+		}
+		if len(rollout.Steps) > 0 {
+			return fmt.Sprintf("The boot %s's rollout steps are only supported by %s.",
+				boot.Name, appv1.CanaryRollout), false
+		}
+		if rollout.ScaleDownDelaySeconds != nil && *rollout.ScaleDownDelaySeconds < 0 {
+			return fmt.Sprintf("The boot %s's scaleDownDelaySeconds must not be negative.", boot.Name), false
+		}
+		return "", true
+	}
+
+	if rollout.Mode != appv1.CanaryRollout {
+		return fmt.Sprintf("The boot %s's rollout mode %s is not supported.", boot.Name, rollout.Mode), false
+	}
+
+	if rollout.ScaleDownDelaySeconds != nil {
+		return fmt.Sprintf("The boot %s's scaleDownDelaySeconds is only supported by %s.",
+			boot.Name, appv1.BlueGreenRollout), false
+	}
+
+	if len(rollout.Steps) == 0 {
+		return fmt.Sprintf("The boot %s's canary rollout must have at least one step.", boot.Name), false
+	}
+
+	weight := int32(0)
+	for i, step := range rollout.Steps {
+		if step.Weight < 1 || step.Weight > 100 {
+			return fmt.Sprintf("The boot %s's canary step %d weight %d must be between 1 and 100.",
+				boot.Name, i+1, step.Weight), false
+		}
+		if step.Weight < weight {
+			return fmt.Sprintf("The boot %s's canary step %d weight %d must not be less than the previous step.",
+				boot.Name, i+1, step.Weight), false
+		}
+		if step.PauseSeconds != nil && *step.PauseSeconds < 0 {
+			return fmt.Sprintf("The boot %s's canary step %d pauseSeconds must not be negative.",
+				boot.Name, i+1), false
+		}
+		weight = step.Weight
+	}
+
+	return "", true
+}
+
 // validatePvc will validate the pvcName, mountPath
 func (vHandler *BootValidator) validatePvc(boot *appv1.Boot, pvcMount appv1.PersistentVolumeClaimMount) (bool, string) {
 	pvcName, _ := operator.Decode(boot, pvcMount.Name)
@@ -616,101 +667,161 @@ func (vHandler *BootValidator) checkPriority(boot *v1.Boot, operation admssionv1
 	return "", true
 }
 
-// CheckEnvKeys check the boot's env keys.
-// Returns
-//    msg: error message
-//    valid: If valid false, otherwise false
-func (vHandler *BootValidator) CheckEnvKeys(boot *v1.Boot, operation admssionv1beta1.Operation) (string, bool) {
-	configSpec := operator.GetConfigSpec(boot)
-	if configSpec == nil {
-		logger.Info("AppSpec is nil, valid is true.")
+// validatePlacement check the boot's placement class exists in the config,
+// and the namespace is granted by the annotation of the namespace.
+func (vHandler *BootValidator) validatePlacement(boot *v1.Boot, operation admssionv1beta1.Operation) (string, bool) {
+	placement := boot.Spec.Placement
+	if len(placement) == 0 {
 		return "", true
 	}
 
-	specField := field.NewPath("spec")
-	errLst := util.ValidateEnv(boot.Spec.Env, specField.Child("env"))
-	if len(errLst) > 0 {
-		return fmt.Sprintf("Boot's Env validation fails: %s", errLst), false
+	configSpec := operator.GetConfigSpec(boot)
+	if configSpec == nil || configSpec.Placements == nil {
+		return fmt.Sprintf("the placement class %s don't exist in config.", placement), false
 	}
-
-	msg, ret := vHandler.checkSecret(boot)
-	if !ret {
-		return msg, ret
+	if _, found := configSpec.Placements[placement]; !found {
+		return fmt.Sprintf("the placement class %s don't exist in config.", placement), false
 	}
 
-	//Creating: should not contains the key in global settings.
-	if operation == admssionv1beta1.Create {
-		for _, cfgEnv := range configSpec.Env {
-			cfgEnvName := cfgEnv.Name
-			// Decode the ${APP}, ${ENV} context
-			cfgEnvValue, _ := operator.Decode(boot, cfgEnv.Value)
+	c := vHandler.client
+	ns := &corev1.Namespace{}
+	err := c.Get(context.TODO(), k8stypes.NamespacedName{Name: boot.Namespace}, ns)
+	if err != nil {
+		return fmt.Sprintf("namespace %s can not use placement class %s: %s.",
+			boot.Namespace, placement, err.Error()), false
+	}
 
-			tmpCfgEnv := corev1.EnvVar{
-				Name:      cfgEnvName,
-				Value:     cfgEnvValue,
-				ValueFrom: cfgEnv.ValueFrom,
-			}
+	if ns.Annotations == nil {
+		return fmt.Sprintf("namespace %s can not use placement class %s.",
+			boot.Namespace, placement), false
+	}
+	_, found := ns.Annotations[keys.BootPlacementAnnotaionKeyPrefix+placement]
+	if !found {
+		return fmt.Sprintf("namespace %s can not use placement class %s.",
+			boot.Namespace, placement), false
+	}
 
-			for _, env := range boot.Spec.Env {
-				if env.Name == cfgEnvName {
+	return "", true
+}
 
-					envVal, _ := operator.Decode(boot, env.Value)
+// validateServiceAccount check the boot's ServiceAccount exists and is granted if it is referenced,
+// and the rules of the boot's Role are allowed by the config.
+func (vHandler *BootValidator) validateServiceAccount(boot *v1.Boot, operation admssionv1beta1.Operation) (string, bool) {
+	sa := boot.Spec.ServiceAccount
+	if sa == nil {
+		return "", true
+	}
 
-					bootEnv := corev1.EnvVar{
-						Name:      env.Name,
-						Value:     envVal,
-						ValueFrom: env.ValueFrom,
-					}
+	if sa.Name == "" && !sa.Create {
+		return fmt.Sprintf("The boot %s's serviceAccount name is required if create is false.", boot.Name), false
+	}
+	if sa.Name != "" {
+		if errs := k8svalidation.IsDNS1123Subdomain(sa.Name); len(errs) > 0 {
+			return fmt.Sprintf("The boot %s's serviceAccount name %s is invalid: %s.",
+				boot.Name, sa.Name, strings.Join(errs, ", ")), false
+		}
+	}
 
-					if !reflect.DeepEqual(bootEnv, tmpCfgEnv) {
-						return fmt.Sprintf("Boot's added Env [%s=%s] not allowed with settings [%s=%s]",
-							env.Name, bootEnv, cfgEnvName, tmpCfgEnv), false
-					}
-				}
-			}
+	// The existing ServiceAccount may be bound to any roles, its permission must be granted by its annotation
+	if !sa.Create {
+		c := vHandler.client
+		found := &corev1.ServiceAccount{}
+		err := c.Get(context.TODO(), k8stypes.NamespacedName{Namespace: boot.Namespace, Name: sa.Name}, found)
+		if err != nil && errors.IsNotFound(err) {
+			return fmt.Sprintf("the ServiceAccount %s don't exist in namespace %s.", sa.Name, boot.Namespace), false
 		}
+		if err != nil {
+			return fmt.Sprintf("Failed to get ServiceAccount %s: %s", sa.Name, err.Error()), false
+		}
+		if _, ok := found.Annotations[keys.BootServiceAccountAnnotaionKeyPrefix+boot.Name]; !ok {
+			return fmt.Sprintf("Boot %s's permission for ServiceAccount %s isn't granted", boot.Name, sa.Name), false
+		}
+	}
 
+	if len(sa.Rules) == 0 {
 		return "", true
 	}
 
-	if operation == admssionv1beta1.Update {
-		return vHandler.checkEnvUpdate(configSpec, boot)
+	configSpec := operator.GetConfigSpec(boot)
+	if configSpec == nil || configSpec.ServiceAccount == nil || len(configSpec.ServiceAccount.AllowedRules) == 0 {
+		return fmt.Sprintf("The boot %s's serviceAccount rules are not allowed by config.", boot.Name), false
+	}
+
+	for i, rule := range sa.Rules {
+		if len(rule.NonResourceURLs) > 0 {
+			return fmt.Sprintf("The boot %s's serviceAccount rule %d can not use nonResourceURLs.", boot.Name, i), false
+		}
+		if len(rule.Verbs) == 0 || len(rule.Resources) == 0 {
+			return fmt.Sprintf("The boot %s's serviceAccount rule %d must have verbs and resources.", boot.Name, i), false
+		}
+
+		allowed := false
+		for _, allowedRule := range configSpec.ServiceAccount.AllowedRules {
+			if ruleCovers(allowedRule, rule) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Sprintf("The boot %s's serviceAccount rule %d [apiGroups=%v resources=%v verbs=%v] "+
+				"is not allowed by config.", boot.Name, i, rule.APIGroups, rule.Resources, rule.Verbs), false
+		}
 	}
 
 	return "", true
 }
 
-func (vHandler *BootValidator) checkSecret(boot *appv1.Boot) (string, bool) {
+// checkImagePullSecrets check the boot's imagePullSecrets exist in the boot's namespace
+func (vHandler *BootValidator) checkImagePullSecrets(boot *v1.Boot, operation admssionv1beta1.Operation) (string, bool) {
 	c := vHandler.client
-	for _, env := range boot.Spec.Env {
-		if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
-			name := env.ValueFrom.SecretKeyRef.Name
-			key := env.ValueFrom.SecretKeyRef.Key
+	for _, pullSecret := range boot.Spec.ImagePullSecrets {
+		if pullSecret.Name == "" {
+			return fmt.Sprintf("The boot %s's imagePullSecret name can not be empty.", boot.Name), false
+		}
 
-			secret := &corev1.Secret{}
+		secret := &corev1.Secret{}
+		err := c.Get(context.TODO(), k8stypes.NamespacedName{Namespace: boot.Namespace, Name: pullSecret.Name}, secret)
+		if err != nil && errors.IsNotFound(err) {
+			return fmt.Sprintf("the imagePullSecret %s don't exist in namespace %s.",
+				pullSecret.Name, boot.Namespace), false
+		}
+	}
 
-			namespaceName := k8stypes.NamespacedName{
-				Namespace: boot.Namespace,
-				Name:      name,
-			}
+	return "", true
+}
 
-			err := c.Get(context.TODO(), namespaceName, secret)
-			if errors.IsNotFound(err) {
-				return fmt.Sprintf("Can not found secret: %s", name), false
-			}
+// validateMetadata check the labels and annotations of the boot's pod, service and workload metadata
+// are valid and not reserved by the operator
+func (vHandler *BootValidator) validateMetadata(boot *v1.Boot, operation admssionv1beta1.Operation) (string, bool) {
+	specField := field.NewPath("spec")
+	for _, item := range []struct {
+		name     string
+		metadata *v1.BootMetadata
+	}{
+		{"podMetadata", boot.Spec.PodMetadata},
+		{"serviceMetadata", boot.Spec.ServiceMetadata},
+		{"workloadMetadata", boot.Spec.WorkloadMetadata},
+	} {
+		name, metadata := item.name, item.metadata
+		if metadata == nil {
+			continue
+		}
 
-			_, ok := secret.Data[key]
-			if !ok {
-				return fmt.Sprintf("Can not found key:%s in secret: %s", key, name), false
-			}
+		errLst := metav1validation.ValidateLabels(metadata.Labels, specField.Child(name, "labels"))
+		errLst = append(errLst, apivalidation.ValidateAnnotations(metadata.Annotations,
+			specField.Child(name, "annotations"))...)
+		if len(errLst) > 0 {
+			return fmt.Sprintf("Boot's %s validation fails: %s", name, errLst), false
+		}
 
-			if secret.Annotations == nil {
-				return fmt.Sprintf("Boot %s's permission for secret %s isn't granted", boot.Name, name), false
+		for key := range metadata.Labels {
+			if operator.IsReservedLabel(key) {
+				return fmt.Sprintf("The boot %s's %s label %s is reserved.", boot.Name, name, key), false
 			}
-
-			_, ok = secret.Annotations[keys.BootSecretAnnotaionKeyPrefix+boot.Name]
-			if !ok {
-				return fmt.Sprintf("Boot %s's permission for secret %s isn't granted", boot.Name, name), false
+		}
+		for key := range metadata.Annotations {
+			if operator.IsReservedAnnotation(key) {
+				return fmt.Sprintf("The boot %s's %s annotation %s is reserved.", boot.Name, name, key), false
 			}
 		}
 	}
@@ -718,16 +829,378 @@ func (vHandler *BootValidator) checkSecret(boot *appv1.Boot) (string, bool) {
 	return "", true
 }
 
-func (vHandler *BootValidator) getMetaEnvs(boot *v1.Boot) ([]corev1.EnvVar, string, error) {
-	bootMetaEnvs, err := operator.DecodeAnnotationEnvs(boot)
-	if err != nil {
-		logger.Error(err, "Boot's annotation env decode error")
-		return nil, "Boot's annotation env decode error", err
+// validateOverrides check the paths of the boot's overrides are allowed by the config, and the patches can be
+// applied to the generated objects
+func (vHandler *BootValidator) validateOverrides(boot *v1.Boot, operation admssionv1beta1.Operation) (string, bool) {
+	overrides := boot.Spec.Overrides
+	if overrides == nil {
+		return "", true
 	}
 
-	if bootMetaEnvs == nil {
-		logger.Info("Boot's annotation env decode empty",
-			"namespace", boot.Namespace, "name", boot.Name)
+	policy := &config.OverridePolicy{}
+	configSpec := operator.GetConfigSpec(boot)
+	if configSpec != nil && configSpec.Overrides != nil {
+		policy = configSpec.Overrides
+	}
+
+	var workload interface{} = &appsv1.Deployment{}
+	if boot.Spec.Workload == v1.StatefulSet {
+		workload = &appsv1.StatefulSet{}
+	}
+
+	for _, item := range []struct {
+		name    string
+		patch   *runtime.RawExtension
+		allowed []string
+		obj     interface{}
+	}{
+		{"workload", overrides.Workload, policy.Workload, workload},
+		{"service", overrides.Service, policy.Service, &corev1.Service{}},
+		{"hpa", overrides.Hpa, policy.Hpa, &autoscaling.HorizontalPodAutoscaler{}},
+	} {
+		if item.patch == nil || len(item.patch.Raw) == 0 {
+			continue
+		}
+
+		paths, err := util.PatchPaths(item.patch.Raw)
+		if err != nil {
+			return fmt.Sprintf("Boot's %s override validation fails: %s", item.name, err.Error()), false
+		}
+		for _, p := range paths {
+			if !util.PathAllowed(p, item.allowed) {
+				return fmt.Sprintf("The boot %s's %s override path %s is not allowed in env %s.",
+					boot.Name, item.name, p, logan.OperDev), false
+			}
+		}
+
+		err = util.StrategicMergePatch(item.obj, item.patch.Raw)
+		if err != nil {
+			return fmt.Sprintf("Boot's %s override validation fails: %s", item.name, err.Error()), false
+		}
+	}
+
+	return "", true
+}
+
+// validateSecurityContext check the boot's securityContext complies with the security policy of the config
+func (vHandler *BootValidator) validateSecurityContext(boot *v1.Boot, operation admssionv1beta1.Operation) (string, bool) {
+	sc := boot.Spec.SecurityContext
+	if sc == nil {
+		return "", true
+	}
+
+	if sc.RunAsNonRoot != nil && *sc.RunAsNonRoot && sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+		return fmt.Sprintf("The boot %s's runAsUser can not be 0 with runAsNonRoot.", boot.Name), false
+	}
+
+	configSpec := operator.GetConfigSpec(boot)
+	if configSpec == nil || configSpec.SecurityPolicy == nil {
+		return "", true
+	}
+	policy := configSpec.SecurityPolicy
+
+	if policy.RequireNonRoot {
+		if sc.RunAsNonRoot != nil && !*sc.RunAsNonRoot {
+			return fmt.Sprintf("The boot %s's runAsNonRoot must be true in env %s.", boot.Name, logan.OperDev), false
+		}
+		if sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+			return fmt.Sprintf("The boot %s's runAsUser can not be 0 in env %s.", boot.Name, logan.OperDev), false
+		}
+	}
+
+	if policy.RequireReadOnlyRootFilesystem && sc.ReadOnlyRootFilesystem != nil && !*sc.ReadOnlyRootFilesystem {
+		return fmt.Sprintf("The boot %s's readOnlyRootFilesystem must be true in env %s.",
+			boot.Name, logan.OperDev), false
+	}
+
+	if sc.Capabilities != nil {
+		for _, capability := range sc.Capabilities.Add {
+			allowed := false
+			for _, allowedCapability := range policy.AllowedCapabilities {
+				if capability == allowedCapability {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				return fmt.Sprintf("The boot %s's capability %s is not allowed in env %s.",
+					boot.Name, capability, logan.OperDev), false
+			}
+		}
+	}
+
+	return "", true
+}
+
+// ruleCovers return true if the allowed rule covers every apiGroup, resource, verb and resourceName of the rule
+func ruleCovers(allowed, rule rbacv1.PolicyRule) bool {
+	if !valuesCovered(allowed.APIGroups, rule.APIGroups) ||
+		!valuesCovered(allowed.Resources, rule.Resources) ||
+		!valuesCovered(allowed.Verbs, rule.Verbs) {
+		return false
+	}
+
+	// An empty resourceNames means all the names
+	if len(allowed.ResourceNames) == 0 {
+		return true
+	}
+	return len(rule.ResourceNames) > 0 && valuesCovered(allowed.ResourceNames, rule.ResourceNames)
+}
+
+// valuesCovered return true if each value is in the allowed values, `*` allows all
+func valuesCovered(allowed, values []string) bool {
+	allowedSet := make(map[string]bool, len(allowed))
+	for _, value := range allowed {
+		allowedSet[value] = true
+	}
+	if allowedSet[rbacv1.ResourceAll] {
+		return true
+	}
+
+	for _, value := range values {
+		if !allowedSet[value] {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckEnvKeys check the boot's env keys.
+// Returns
+//    msg: error message
+//    valid: If valid false, otherwise false
+func (vHandler *BootValidator) CheckEnvKeys(boot *v1.Boot, operation admssionv1beta1.Operation) (string, bool) {
+	configSpec := operator.GetConfigSpec(boot)
+	if configSpec == nil {
+		logger.Info("AppSpec is nil, valid is true.")
+		return "", true
+	}
+
+	specField := field.NewPath("spec")
+	errLst := util.ValidateEnv(boot.Spec.Env, specField.Child("env"))
+	if len(errLst) > 0 {
+		return fmt.Sprintf("Boot's Env validation fails: %s", errLst), false
+	}
+
+	errLst = util.ValidateEnvFrom(boot.Spec.EnvFrom, specField.Child("envFrom"))
+	if len(errLst) > 0 {
+		return fmt.Sprintf("Boot's EnvFrom validation fails: %s", errLst), false
+	}
+
+	msg, ret := vHandler.checkSecret(boot)
+	if !ret {
+		return msg, ret
+	}
+
+	//Creating: should not contains the key in global settings.
+	if operation == admssionv1beta1.Create {
+		msg, ret = vHandler.checkEnvFromKeys(configSpec, boot)
+		if !ret {
+			return msg, ret
+		}
+
+		for _, cfgEnv := range configSpec.Env {
+			cfgEnvName := cfgEnv.Name
+			// Decode the ${APP}, ${ENV} context
+			cfgEnvValue, _ := operator.Decode(boot, cfgEnv.Value)
+
+			tmpCfgEnv := corev1.EnvVar{
+				Name:      cfgEnvName,
+				Value:     cfgEnvValue,
+				ValueFrom: cfgEnv.ValueFrom,
+			}
+
+			for _, env := range boot.Spec.Env {
+				if env.Name == cfgEnvName {
+
+					envVal, _ := operator.Decode(boot, env.Value)
+
+					bootEnv := corev1.EnvVar{
+						Name:      env.Name,
+						Value:     envVal,
+						ValueFrom: env.ValueFrom,
+					}
+
+					if !reflect.DeepEqual(bootEnv, tmpCfgEnv) {
+						return fmt.Sprintf("Boot's added Env [%s=%s] not allowed with settings [%s=%s]",
+							env.Name, bootEnv, cfgEnvName, tmpCfgEnv), false
+					}
+				}
+			}
+		}
+
+		return "", true
+	}
+
+	if operation == admssionv1beta1.Update {
+		return vHandler.checkEnvUpdate(configSpec, boot)
+	}
+
+	return "", true
+}
+
+func (vHandler *BootValidator) checkSecret(boot *appv1.Boot) (string, bool) {
+	for _, env := range boot.Spec.Env {
+		if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+			name := env.ValueFrom.SecretKeyRef.Name
+			key := env.ValueFrom.SecretKeyRef.Key
+
+			secret, msg, ok := vHandler.getGrantedSecret(boot, name)
+			if !ok {
+				return msg, false
+			}
+
+			_, ok = secret.Data[key]
+			if !ok {
+				return fmt.Sprintf("Can not found key:%s in secret: %s", key, name), false
+			}
+		}
+	}
+
+	for _, envFrom := range boot.Spec.EnvFrom {
+		if envFrom.SecretRef != nil {
+			_, msg, ok := vHandler.getEnvFromKeys(boot, envFrom)
+			if !ok {
+				return msg, false
+			}
+		}
+	}
+
+	return "", true
+}
+
+// getEnvFromKeys return the keys of the envFrom's configMap or secret, the boot's permission for the secret must be granted.
+// An optional configMap or secret may not exist.
+// Returns
+//    envKeys: the keys of the configMap or secret
+//    msg: error message
+//    valid: If valid false, otherwise false
+func (vHandler *BootValidator) getEnvFromKeys(boot *appv1.Boot, envFrom corev1.EnvFromSource) ([]string, string, bool) {
+	c := vHandler.client
+	envKeys := make([]string, 0)
+
+	if envFrom.ConfigMapRef != nil {
+		name := envFrom.ConfigMapRef.Name
+		cm := &corev1.ConfigMap{}
+		err := c.Get(context.TODO(), k8stypes.NamespacedName{Namespace: boot.Namespace, Name: name}, cm)
+		if errors.IsNotFound(err) {
+			if envFrom.ConfigMapRef.Optional != nil && *envFrom.ConfigMapRef.Optional {
+				return envKeys, "", true
+			}
+			return nil, fmt.Sprintf("Can not found configMap: %s", name), false
+		}
+		if err != nil {
+			return nil, fmt.Sprintf("Failed to get configMap %s: %s", name, err.Error()), false
+		}
+
+		for key := range cm.Data {
+			envKeys = append(envKeys, key)
+		}
+		for key := range cm.BinaryData {
+			envKeys = append(envKeys, key)
+		}
+		return envKeys, "", true
+	}
+
+	if envFrom.SecretRef != nil {
+		name := envFrom.SecretRef.Name
+		if envFrom.SecretRef.Optional != nil && *envFrom.SecretRef.Optional {
+			err := c.Get(context.TODO(), k8stypes.NamespacedName{Namespace: boot.Namespace, Name: name}, &corev1.Secret{})
+			if errors.IsNotFound(err) {
+				return envKeys, "", true
+			}
+		}
+
+		secret, msg, ok := vHandler.getGrantedSecret(boot, name)
+		if !ok {
+			return nil, msg, false
+		}
+
+		for key := range secret.Data {
+			envKeys = append(envKeys, key)
+		}
+	}
+
+	return envKeys, "", true
+}
+
+// checkEnvFromKeys check the envFrom's keys, the env enforced by the settings can not be overridden by envFrom.
+// Returns
+//    msg: error message
+//    valid: If valid false, otherwise false
+func (vHandler *BootValidator) checkEnvFromKeys(configSpec *config.AppSpec, boot *v1.Boot) (string, bool) {
+	if len(boot.Spec.EnvFrom) == 0 || len(configSpec.Env) == 0 {
+		return "", true
+	}
+
+	cfgEnvs := make(map[string]string)
+	for _, cfgEnv := range configSpec.Env {
+		cfgEnvValue, _ := operator.Decode(boot, cfgEnv.Value)
+		cfgEnvs[cfgEnv.Name] = cfgEnvValue
+	}
+
+	for _, envFrom := range boot.Spec.EnvFrom {
+		envKeys, msg, ok := vHandler.getEnvFromKeys(boot, envFrom)
+		if !ok {
+			return msg, false
+		}
+
+		for _, key := range envKeys {
+			envName := envFrom.Prefix + key
+			if cfgEnvValue, found := cfgEnvs[envName]; found {
+				return fmt.Sprintf("Boot's EnvFrom Env [%s] not allowed with settings [%s=%s]",
+					envName, envName, cfgEnvValue), false
+			}
+		}
+	}
+
+	return "", true
+}
+
+// getGrantedSecret get the secret, the boot's permission must be granted by the secret's annotation
+// Returns
+//    secret: the secret
+//    msg: error message
+//    valid: If valid false, otherwise false
+func (vHandler *BootValidator) getGrantedSecret(boot *appv1.Boot, name string) (*corev1.Secret, string, bool) {
+	c := vHandler.client
+	secret := &corev1.Secret{}
+
+	namespaceName := k8stypes.NamespacedName{
+		Namespace: boot.Namespace,
+		Name:      name,
+	}
+
+	err := c.Get(context.TODO(), namespaceName, secret)
+	if errors.IsNotFound(err) {
+		return nil, fmt.Sprintf("Can not found secret: %s", name), false
+	}
+	if err != nil {
+		return nil, fmt.Sprintf("Failed to get secret %s: %s", name, err.Error()), false
+	}
+
+	if secret.Annotations == nil {
+		return nil, fmt.Sprintf("Boot %s's permission for secret %s isn't granted", boot.Name, name), false
+	}
+
+	_, ok := secret.Annotations[keys.BootSecretAnnotaionKeyPrefix+boot.Name]
+	if !ok {
+		return nil, fmt.Sprintf("Boot %s's permission for secret %s isn't granted", boot.Name, name), false
+	}
+
+	return secret, "", true
+}
+
+func (vHandler *BootValidator) getMetaEnvs(boot *v1.Boot) ([]corev1.EnvVar, string, error) {
+	bootMetaEnvs, err := operator.DecodeAnnotationEnvs(boot)
+	if err != nil {
+		logger.Error(err, "Boot's annotation env decode error")
+		return nil, "Boot's annotation env decode error", err
+	}
+
+	if bootMetaEnvs == nil {
+		logger.Info("Boot's annotation env decode empty",
+			"namespace", boot.Namespace, "name", boot.Name)
 
 		latestBoot, err := vHandler.getLatest(boot)
 		if err != nil {
@@ -751,6 +1224,12 @@ func (vHandler *BootValidator) getMetaEnvs(boot *v1.Boot) ([]corev1.EnvVar, stri
 
 // checkEnvUpdate will check the envs is update
 func (vHandler *BootValidator) checkEnvUpdate(configSpec *config.AppSpec, boot *v1.Boot) (string, bool) {
+	// 0. EnvFrom: the keys of the configMaps or secrets may be changed, always check them.
+	msg, valid := vHandler.checkEnvFromKeys(configSpec, boot)
+	if !valid {
+		return msg, false
+	}
+
 	bootMetaEnvs, msg, err := vHandler.getMetaEnvs(boot)
 	if err != nil {
 		return msg, false
@@ -822,3 +1301,471 @@ func (vHandler *BootValidator) checkEnvUpdate(configSpec *config.AppSpec, boot *
 
 	return "", true
 }
+
+// validateDisruption will validate the boot's PodDisruptionBudget settings
+func (vHandler *BootValidator) validateDisruption(boot *appv1.Boot, operation admssionv1beta1.Operation) (string, bool) {
+	disruption := boot.Spec.Disruption
+	if disruption == nil {
+		return "", true
+	}
+
+	if disruption.MinAvailable != nil && disruption.MaxUnavailable != nil {
+		return fmt.Sprintf("The boot %s's minAvailable and maxUnavailable can not be both set.", boot.Name), false
+	}
+
+	for _, value := range []*intstr.IntOrString{disruption.MinAvailable, disruption.MaxUnavailable} {
+		if value == nil {
+			continue
+		}
+		v, err := intstr.GetValueFromIntOrPercent(value, 100, true)
+		if err != nil || v < 0 {
+			return fmt.Sprintf("The boot %s's disruption %s is invalid.", boot.Name, value.String()), false
+		}
+	}
+
+	return "", true
+}
+
+// validateShutdown will validate the boot's preStop hook and drain delay
+func (vHandler *BootValidator) validateShutdown(boot *appv1.Boot, operation admssionv1beta1.Operation) (string, bool) {
+	shutdown := boot.Spec.Shutdown
+	if shutdown == nil {
+		return "", true
+	}
+
+	preStop := shutdown.PreStop
+	if preStop != nil {
+		switch preStop.Type {
+		case "", appv1.ProbeHTTP:
+			if preStop.Path != "" && !strings.HasPrefix(preStop.Path, "/") {
+				return fmt.Sprintf("The boot %s's preStop path %s must start with '/'", boot.Name, preStop.Path), false
+			}
+			if shutdown.DrainDelaySeconds != nil && *shutdown.DrainDelaySeconds > 0 {
+				return fmt.Sprintf("The boot %s's drainDelaySeconds can not be used with the preStop type %s",
+					boot.Name, appv1.ProbeHTTP), false
+			}
+		case appv1.ProbeExec:
+			if len(preStop.Command) == 0 {
+				return fmt.Sprintf("The boot %s's preStop command can not be empty with type %s",
+					boot.Name, preStop.Type), false
+			}
+		default:
+			return fmt.Sprintf("The boot %s's preStop type %s is not supported", boot.Name, preStop.Type), false
+		}
+	}
+
+	if shutdown.DrainDelaySeconds != nil && shutdown.TerminationGracePeriodSeconds != nil &&
+		int64(*shutdown.DrainDelaySeconds) >= *shutdown.TerminationGracePeriodSeconds {
+		return fmt.Sprintf("The boot %s's drainDelaySeconds must be less than terminationGracePeriodSeconds.",
+			boot.Name), false
+	}
+
+	return "", true
+}
+
+// validatePorts will validate the boot's additional ports and metrics port
+func (vHandler *BootValidator) validatePorts(boot *appv1.Boot, operation admssionv1beta1.Operation) (string, bool) {
+	names := map[string]bool{operator.HttpPortName: true}
+	containerPorts := map[int32]bool{boot.Spec.Port: true}
+	for _, port := range boot.Spec.Ports {
+		if errs := k8svalidation.IsValidPortName(port.Name); len(errs) > 0 {
+			return fmt.Sprintf("The boot %s's port name %s is invalid: %s.",
+				boot.Name, port.Name, strings.Join(errs, ", ")), false
+		}
+		if names[port.Name] {
+			return fmt.Sprintf("The boot %s's port name %s is duplicated, %s is reserved for the primary port.",
+				boot.Name, port.Name, operator.HttpPortName), false
+		}
+		if containerPorts[port.ContainerPort] {
+			return fmt.Sprintf("The boot %s's containerPort %d is duplicated.", boot.Name, port.ContainerPort), false
+		}
+		names[port.Name] = true
+		containerPorts[port.ContainerPort] = true
+	}
+
+	if boot.Spec.MetricsPort != "" && !names[boot.Spec.MetricsPort] {
+		return fmt.Sprintf("The boot %s's metricsPort %s is not found in the ports.",
+			boot.Name, boot.Spec.MetricsPort), false
+	}
+
+	return "", true
+}
+
+// validateVolumes will validate the boot's configMap/secret/emptyDir/projected volumes.
+// The boot's permission for the secrets must be granted by the secret's annotation, as the env's secrets.
+func (vHandler *BootValidator) validateVolumes(boot *appv1.Boot, operation admssionv1beta1.Operation) (string, bool) {
+	if len(boot.Spec.Volumes) == 0 {
+		return "", true
+	}
+
+	names := make(map[string]bool)
+	mountPaths := make(map[string]bool)
+	for _, pvc := range boot.Spec.Pvc {
+		pvcName, _ := operator.Decode(boot, pvc.Name)
+		names[pvcName] = true
+		mountPaths[pvc.MountPath] = true
+	}
+
+	configSpec := operator.GetConfigSpec(boot)
+	if configSpec != nil && configSpec.PodSpec != nil {
+		for _, vol := range configSpec.PodSpec.Volumes {
+			volName, _ := operator.Decode(boot, vol.Name)
+			names[volName] = true
+		}
+	}
+
+	for _, vol := range boot.Spec.Volumes {
+		if errs := k8svalidation.IsDNS1123Label(vol.Name); len(errs) > 0 {
+			return fmt.Sprintf("The boot %s's volume name %s is invalid: %s.",
+				boot.Name, vol.Name, strings.Join(errs, ", ")), false
+		}
+		if names[vol.Name] {
+			return fmt.Sprintf("The boot %s's volume name %s is duplicated with the pvc or the settings.",
+				boot.Name, vol.Name), false
+		}
+		names[vol.Name] = true
+
+		if vol.MountPath == "" || strings.Contains(vol.MountPath, ":") {
+			return fmt.Sprintf("The boot %s's volume %s mountPath %s is invalid, must not be empty or contain ':'.",
+				boot.Name, vol.Name, vol.MountPath), false
+		}
+		if mountPaths[vol.MountPath] {
+			return fmt.Sprintf("The boot %s's volume %s mountPath %s is duplicated.",
+				boot.Name, vol.Name, vol.MountPath), false
+		}
+		mountPaths[vol.MountPath] = true
+
+		if vol.SubPath != "" && !isLocalPath(vol.SubPath) {
+			return fmt.Sprintf("The boot %s's volume %s subPath %s must be a relative path without '..'.",
+				boot.Name, vol.Name, vol.SubPath), false
+		}
+
+		msg, valid := vHandler.validateVolumeSource(boot, vol)
+		if !valid {
+			return msg, false
+		}
+	}
+
+	return "", true
+}
+
+// validateVolumeSource will validate the source of the boot's volume, exactly one source must be set
+func (vHandler *BootValidator) validateVolumeSource(boot *appv1.Boot, vol appv1.BootVolume) (string, bool) {
+	sources := 0
+	if vol.ConfigMap != nil {
+		sources++
+	}
+	if vol.Secret != nil {
+		sources++
+	}
+	if vol.EmptyDir != nil {
+		sources++
+	}
+	if vol.Projected != nil {
+		sources++
+	}
+	if sources != 1 {
+		return fmt.Sprintf("The boot %s's volume %s must have exactly one of configMap, secret, emptyDir and projected.",
+			boot.Name, vol.Name), false
+	}
+
+	if vol.ConfigMap != nil {
+		if vol.ConfigMap.Name == "" {
+			return fmt.Sprintf("The boot %s's volume %s configMap name is empty.", boot.Name, vol.Name), false
+		}
+		return validateVolumeItems(boot, vol.Name, vol.ConfigMap.DefaultMode, vol.ConfigMap.Items)
+	}
+
+	if vol.Secret != nil {
+		msg, valid := validateVolumeItems(boot, vol.Name, vol.Secret.DefaultMode, vol.Secret.Items)
+		if !valid {
+			return msg, false
+		}
+		return vHandler.checkVolumeSecret(boot, vol.Name, vol.Secret.SecretName, vol.Secret.Optional, vol.Secret.Items)
+	}
+
+	if vol.Projected != nil {
+		if len(vol.Projected.Sources) == 0 {
+			return fmt.Sprintf("The boot %s's volume %s projected sources is empty.", boot.Name, vol.Name), false
+		}
+		if !validMode(vol.Projected.DefaultMode) {
+			return fmt.Sprintf("The boot %s's volume %s defaultMode must be between 0 and 0777.",
+				boot.Name, vol.Name), false
+		}
+		for _, source := range vol.Projected.Sources {
+			if source.ConfigMap != nil && source.Secret == nil &&
+				source.DownwardAPI == nil && source.ServiceAccountToken == nil {
+				if source.ConfigMap.Name == "" {
+					return fmt.Sprintf("The boot %s's volume %s configMap name is empty.", boot.Name, vol.Name), false
+				}
+				msg, valid := validateVolumeItems(boot, vol.Name, nil, source.ConfigMap.Items)
+				if !valid {
+					return msg, false
+				}
+				continue
+			}
+
+			if source.Secret != nil && source.ConfigMap == nil &&
+				source.DownwardAPI == nil && source.ServiceAccountToken == nil {
+				msg, valid := validateVolumeItems(boot, vol.Name, nil, source.Secret.Items)
+				if !valid {
+					return msg, false
+				}
+				msg, valid = vHandler.checkVolumeSecret(boot, vol.Name,
+					source.Secret.Name, source.Secret.Optional, source.Secret.Items)
+				if !valid {
+					return msg, false
+				}
+				continue
+			}
+
+			return fmt.Sprintf("The boot %s's volume %s projected source must be exactly one of configMap and secret.",
+				boot.Name, vol.Name), false
+		}
+	}
+
+	return "", true
+}
+
+// validateTLSSecret check the boot's TLS secret exists and is granted, and holds the certificate and key
+func (vHandler *BootValidator) validateTLSSecret(boot *appv1.Boot, operation admssionv1beta1.Operation) (string, bool) {
+	name := boot.Spec.TLSSecret
+	if name == "" {
+		return "", true
+	}
+
+	secret, msg, ok := vHandler.getGrantedSecret(boot, name)
+	if !ok {
+		return msg, false
+	}
+
+	for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey} {
+		if _, found := secret.Data[key]; !found {
+			return fmt.Sprintf("Can not found key:%s in secret: %s", key, name), false
+		}
+	}
+
+	return "", true
+}
+
+// checkVolumeSecret check the secret of the boot's volume exists and is granted, and the items' keys exist.
+// An optional secret may not exist.
+func (vHandler *BootValidator) checkVolumeSecret(boot *appv1.Boot, volName string, name string,
+	optional *bool, items []corev1.KeyToPath) (string, bool) {
+	if name == "" {
+		return fmt.Sprintf("The boot %s's volume %s secret name is empty.", boot.Name, volName), false
+	}
+
+	isOptional := optional != nil && *optional
+	if isOptional {
+		err := vHandler.client.Get(context.TODO(),
+			k8stypes.NamespacedName{Namespace: boot.Namespace, Name: name}, &corev1.Secret{})
+		if errors.IsNotFound(err) {
+			return "", true
+		}
+	}
+
+	secret, msg, ok := vHandler.getGrantedSecret(boot, name)
+	if !ok {
+		return msg, false
+	}
+
+	// The keys of an optional secret may not exist
+	if isOptional {
+		return "", true
+	}
+
+	for _, item := range items {
+		if _, found := secret.Data[item.Key]; !found {
+			return fmt.Sprintf("Can not found key:%s in secret: %s", item.Key, name), false
+		}
+	}
+
+	return "", true
+}
+
+// validateVolumeItems validate the modes and the paths of the volume's items
+func validateVolumeItems(boot *appv1.Boot, volName string, defaultMode *int32, items []corev1.KeyToPath) (string, bool) {
+	if !validMode(defaultMode) {
+		return fmt.Sprintf("The boot %s's volume %s defaultMode must be between 0 and 0777.",
+			boot.Name, volName), false
+	}
+
+	for _, item := range items {
+		if item.Key == "" {
+			return fmt.Sprintf("The boot %s's volume %s item key is empty.", boot.Name, volName), false
+		}
+		if item.Path == "" || !isLocalPath(item.Path) {
+			return fmt.Sprintf("The boot %s's volume %s item path %s must be a relative path without '..'.",
+				boot.Name, volName, item.Path), false
+		}
+		if !validMode(item.Mode) {
+			return fmt.Sprintf("The boot %s's volume %s item %s mode must be between 0 and 0777.",
+				boot.Name, volName, item.Key), false
+		}
+	}
+
+	return "", true
+}
+
+// validMode return true if the file mode is nil or between 0 and 0777
+func validMode(mode *int32) bool {
+	return mode == nil || (*mode >= 0 && *mode <= 0777)
+}
+
+// isLocalPath return true if the path is relative and does not contain '..'
+func isLocalPath(p string) bool {
+	if path.IsAbs(p) {
+		return false
+	}
+	for _, item := range strings.Split(p, "/") {
+		if item == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// validateScheduling will validate the boot's tolerations, node affinity and topology spread constraints.
+// The node labels and taints locked by the config's scheduling policy can only be used with the config's values,
+// so that the boots can not be scheduled onto the reserved node pools.
+func (vHandler *BootValidator) validateScheduling(boot *appv1.Boot, operation admssionv1beta1.Operation) (string, bool) {
+	for _, constraint := range boot.Spec.TopologySpreadConstraints {
+		if errs := k8svalidation.IsQualifiedName(constraint.TopologyKey); len(errs) > 0 {
+			return fmt.Sprintf("The boot %s's topologyKey %s is invalid: %s.",
+				boot.Name, constraint.TopologyKey, strings.Join(errs, ", ")), false
+		}
+	}
+
+	for _, toleration := range boot.Spec.Tolerations {
+		if toleration.Key == "" && toleration.Operator != corev1.TolerationOpExists {
+			return fmt.Sprintf("The boot %s's toleration with empty key must use operator Exists.", boot.Name), false
+		}
+		if toleration.Operator == corev1.TolerationOpExists && toleration.Value != "" {
+			return fmt.Sprintf("The boot %s's toleration %s with operator Exists must have empty value.",
+				boot.Name, toleration.Key), false
+		}
+	}
+
+	nodeAffinity := boot.Spec.NodeAffinity
+	if nodeAffinity != nil && nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil &&
+		len(nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) == 0 {
+		return fmt.Sprintf("The boot %s's required node affinity must have at least one nodeSelectorTerm.",
+			boot.Name), false
+	}
+
+	configSpec := operator.GetConfigSpec(boot)
+	if configSpec == nil || configSpec.Scheduling == nil {
+		return "", true
+	}
+	policy := configSpec.Scheduling
+
+	// 1. Locked node labels: nodeSelector and the requirements of nodeAffinity
+	lockedLabels := make(map[string]bool)
+	for _, key := range policy.LockedNodeLabels {
+		lockedLabels[key] = true
+	}
+
+	for key, value := range boot.Spec.NodeSelector {
+		if lockedLabels[key] && configSpec.NodeSelector[key] != value {
+			return fmt.Sprintf("The boot %s can not use the locked node label %s=%s in nodeSelector.",
+				boot.Name, key, value), false
+		}
+	}
+
+	if nodeAffinity != nil {
+		cfgReqs := nodeRequirements(configSpec.NodeAffinity)
+		for _, req := range nodeRequirements(nodeAffinity) {
+			if !lockedLabels[req.Key] {
+				continue
+			}
+			found := false
+			for _, cfgReq := range cfgReqs {
+				if reflect.DeepEqual(req, cfgReq) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Sprintf("The boot %s can not use the locked node label %s in nodeAffinity.",
+					boot.Name, req.Key), false
+			}
+		}
+
+		// The node fields, such as metadata.name, could select the nodes with the locked labels by name,
+		// only the config's field requirements can be used
+		cfgFieldReqs := nodeFieldRequirements(configSpec.NodeAffinity)
+		for _, req := range nodeFieldRequirements(nodeAffinity) {
+			found := len(lockedLabels) == 0
+			for _, cfgReq := range cfgFieldReqs {
+				if reflect.DeepEqual(req, cfgReq) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Sprintf("The boot %s can not use the node field %s in nodeAffinity.",
+					boot.Name, req.Key), false
+			}
+		}
+	}
+
+	// 2. Locked taints: only the config's tolerations can tolerate them
+	lockedTaints := make(map[string]bool)
+	for _, key := range policy.LockedTaints {
+		lockedTaints[key] = true
+	}
+
+	for _, toleration := range boot.Spec.Tolerations {
+		// An empty key with operator Exists tolerates everything
+		if !lockedTaints[toleration.Key] && !(toleration.Key == "" && len(lockedTaints) > 0) {
+			continue
+		}
+		found := false
+		for _, cfgToleration := range configSpec.Tolerations {
+			if reflect.DeepEqual(toleration, cfgToleration) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Sprintf("The boot %s can not tolerate the locked taint %s.", boot.Name, toleration.Key), false
+		}
+	}
+
+	return "", true
+}
+
+// nodeRequirements return all the label requirements of the required and preferred node affinity
+func nodeRequirements(nodeAffinity *corev1.NodeAffinity) []corev1.NodeSelectorRequirement {
+	reqs := make([]corev1.NodeSelectorRequirement, 0)
+	if nodeAffinity == nil {
+		return reqs
+	}
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		for _, term := range nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+			reqs = append(reqs, term.MatchExpressions...)
+		}
+	}
+	for _, term := range nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		reqs = append(reqs, term.Preference.MatchExpressions...)
+	}
+	return reqs
+}
+
+// nodeFieldRequirements return all the field requirements of the required and preferred node affinity
+func nodeFieldRequirements(nodeAffinity *corev1.NodeAffinity) []corev1.NodeSelectorRequirement {
+	reqs := make([]corev1.NodeSelectorRequirement, 0)
+	if nodeAffinity == nil {
+		return reqs
+	}
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		for _, term := range nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+			reqs = append(reqs, term.MatchFields...)
+		}
+	}
+	for _, term := range nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		reqs = append(reqs, term.Preference.MatchFields...)
+	}
+	return reqs
+}
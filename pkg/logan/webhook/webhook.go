@@ -1,6 +1,7 @@
 package webhook
 
 import (
+	"encoding/json"
 	appv1 "github.com/logancloud/logan-app-operator/pkg/apis/app/v1"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
@@ -63,6 +64,21 @@ func DecodeBoot(req admission.Request, decoder *admission.Decoder) (*appv1.Boot,
 	return boot, nil
 }
 
+// DecodeOldBoot decode the Boot object before the update from request, nil if the request has no old object.
+// All the boot types share the same json layout as Boot.
+func DecodeOldBoot(req admission.Request) (*appv1.Boot, error) {
+	if len(req.AdmissionRequest.OldObject.Raw) == 0 {
+		return nil, nil
+	}
+
+	boot := &appv1.Boot{}
+	err := json.Unmarshal(req.AdmissionRequest.OldObject.Raw, boot)
+	if err != nil {
+		return nil, err
+	}
+	return boot, nil
+}
+
 // DecodeJavaBoot decode the JavaBoot object from request.
 func DecodeJavaBoot(req admission.Request, decoder *admission.Decoder) (*appv1.JavaBoot, error) {
 	bootType := req.AdmissionRequest.Kind.Kind
@@ -45,8 +45,10 @@ var _ = Describe("Testing Boot Revision [Revision]", func() {
 				Expect(r.Name).Should(Equal(bootKey.Name + "-1"))
 				Expect(r.GetRevisionId()).Should(Equal(1))
 				Expect(len(r.GetOwnerReferences())).Should(Equal(1))
-				Expect(r.Annotations[keys.BootRevisionDiffAnnotationKey]).Should(Equal(""))
+				Expect(r.Status.Changes).Should(BeEmpty())
 				Expect(r.Annotations[keys.BootRevisionPhaseAnnotationKey]).Should(Or(Equal(operator.RevisionPhaseActive), Equal(operator.RevisionPhaseRunning)))
+				Expect(r.Annotations[keys.BootChangedByAnnotationKey]).ShouldNot(Equal(""))
+				Expect(r.Annotations[keys.BootChangeOperationAnnotationKey]).Should(Equal("CREATE"))
 			},
 		}
 	})
@@ -82,7 +84,7 @@ var _ = Describe("Testing Boot Revision [Revision]", func() {
 				Expect(r.Name).Should(Equal(bootKey.Name + "-1"))
 				Expect(r.GetRevisionId()).Should(Equal(1))
 				Expect(len(r.GetOwnerReferences())).Should(Equal(1))
-				Expect(r.Annotations[keys.BootRevisionDiffAnnotationKey]).Should(Equal(""))
+				Expect(r.Status.Changes).Should(BeEmpty())
 				Expect(r.Annotations[keys.BootRevisionPhaseAnnotationKey]).Should(Or(Equal(operator.RevisionPhaseActive), Equal(operator.RevisionPhaseRunning)))
 			}
 
@@ -120,7 +122,7 @@ var _ = Describe("Testing Boot Revision [Revision]", func() {
 					Expect(previous.Name).Should(Equal(bootKey.Name + "-1"))
 					Expect(previous.GetRevisionId()).Should(Equal(1))
 					Expect(len(previous.GetOwnerReferences())).Should(Equal(1))
-					Expect(previous.Annotations[keys.BootRevisionDiffAnnotationKey]).Should(Equal(""))
+					Expect(previous.Status.Changes).Should(BeEmpty())
 					Expect(previous.Annotations[keys.BootRevisionPhaseAnnotationKey]).Should(Or(Equal(operator.RevisionPhaseActive), Equal(operator.RevisionPhaseRunning)))
 				}
 			}
@@ -151,7 +153,11 @@ var _ = Describe("Testing Boot Revision [Revision]", func() {
 				Expect(latest.Name).Should(Equal(bootKey.Name + "-2"))
 				Expect(latest.GetRevisionId()).Should(Equal(2))
 				Expect(len(latest.GetOwnerReferences())).Should(Equal(1))
-				Expect(latest.Annotations[keys.BootRevisionDiffAnnotationKey]).ShouldNot(Equal(""))
+				Expect(latest.Status.Changes).Should(ContainElement(bootv1.BootRevisionChange{
+					Path: "spec.port", Old: "8080", New: "8090"}))
+				Expect(latest.Annotations[keys.BootChangedByAnnotationKey]).ShouldNot(Equal(""))
+				Expect(latest.Annotations[keys.BootChangeOperationAnnotationKey]).Should(Equal("UPDATE"))
+				Expect(boot.Annotations).ShouldNot(HaveKey(keys.BootChangedByAnnotationKey))
 				Expect(latest.Annotations[keys.BootRevisionPhaseAnnotationKey]).Should(Or(Equal(operator.RevisionPhaseActive), Equal(operator.RevisionPhaseRunning)))
 
 				var previous *bootv1.BootRevision
@@ -167,7 +173,7 @@ var _ = Describe("Testing Boot Revision [Revision]", func() {
 					Expect(previous.Name).Should(Equal(bootKey.Name + "-1"))
 					Expect(previous.GetRevisionId()).Should(Equal(1))
 					Expect(len(previous.GetOwnerReferences())).Should(Equal(1))
-					Expect(previous.Annotations[keys.BootRevisionDiffAnnotationKey]).Should(Equal(""))
+					Expect(previous.Status.Changes).Should(BeEmpty())
 					Expect(previous.Annotations[keys.BootRevisionPhaseAnnotationKey]).Should(Or(Equal(operator.RevisionPhaseComplete), Equal(operator.RevisionPhaseCancel)))
 				}
 			}
@@ -197,13 +203,51 @@ var _ = Describe("Testing Boot Revision [Revision]", func() {
 				//Expect(latest.Name).Should(Equal(bootKey.Name + "-2"))
 				//Expect(latest.GetRevisionId()).Should(Equal(2))
 				Expect(len(latest.GetOwnerReferences())).Should(Equal(1))
-				Expect(latest.Annotations[keys.BootRevisionDiffAnnotationKey]).ShouldNot(Equal(""))
+				Expect(latest.Status.Changes).ShouldNot(BeEmpty())
 				Expect(latest.Annotations[keys.BootRevisionPhaseAnnotationKey]).Should(Or(Equal(operator.RevisionPhaseActive), Equal(operator.RevisionPhaseRunning)))
 			}
 			e2eCase.Run()
 		})
 	})
 
+	Context("test rollback the boot to a revision", func() {
+		It("testing rollback boot by the rollback annotation", func() {
+			e2eCase.Update = func() {
+				boot := operatorFramework.GetBoot(bootKey)
+				boot.Spec.Port = 8090
+				operatorFramework.UpdateBoot(boot)
+
+				boot = operatorFramework.GetBoot(bootKey)
+				newReplica := int32(2)
+				boot.Spec.Replicas = &newReplica
+				boot.Annotations[keys.BootRollbackToAnnotationKey] = "1"
+				operatorFramework.UpdateBoot(boot)
+			}
+
+			e2eCase.Recheck = func() {
+				boot := operatorFramework.GetBoot(bootKey)
+				Expect(boot.Spec.Port).Should(Equal(int32(8080)))
+				Expect(*boot.Spec.Replicas).Should(Equal(int32(2)))
+				Expect(boot.Annotations).ShouldNot(HaveKey(keys.BootRollbackToAnnotationKey))
+				Expect(boot.Annotations).ShouldNot(HaveKey(keys.BootChangeCauseAnnotationKey))
+
+				podLabels := operator.PodLabels(boot.DeepCopyBoot())
+				lst, err := k8sClient.ListRevision(boot.Namespace, podLabels)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(len(lst.Items)).Should(Equal(3))
+				latest := lst.SelectLatestRevision()
+				Expect(latest.GetRevisionId()).Should(Equal(3))
+				Expect(latest.Annotations[keys.BootChangeCauseAnnotationKey]).Should(Equal("rollback from 2"))
+
+				cond := boot.Status.GetCondition(bootv1.BootRolledBack)
+				Expect(cond).ShouldNot(BeNil())
+				Expect(cond.Status).Should(Equal(corev1.ConditionTrue))
+			}
+
+			e2eCase.Run()
+		})
+	})
+
 	Context("test delete the boot with revision", func() {
 		It("test delete the boot with revision, revision should also deleted", func() {
 